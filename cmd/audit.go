@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/audit"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Query the audit log of mutating commands",
+	Long: `Query the audit log of mutating commands.
+
+Every up, down, connect, exec, queue run, queue cancel and pool fill
+appends an entry to $HOME/.ghost/audit.log recording the time, user,
+command, arguments (with secrets redacted), target cluster, result and
+duration.
+
+Teams sharing a host can also record entries in a ConfigMap in the host
+namespace by setting audit.hostSink in $HOME/.ghost/config.yaml, and query
+them with --source host.
+
+Examples:
+  ghostctl audit                              # All local entries
+  ghostctl audit --cluster pr-512 --since 24h # Who touched pr-512 today?
+  ghostctl audit --command down -o json       # Deletions as JSON
+  ghostctl audit --command "queue run"        # Runs of the queue
+  ghostctl audit --source host --since 7d     # Entries shared on the host`,
+	Args: cobra.NoArgs,
+	RunE: runAuditCmd,
+}
+
+var (
+	auditCluster string
+	auditUser    string
+	auditCommand string
+	auditSince   string
+	auditSource  string
+	auditOutput  string
+)
+
+// auditedCommands are the mutating commands recorded in the audit log
+var auditedCommands = []*cobra.Command{upCmd, downCmd, connectCmd, execCmd, queueRunCmd, queueCancelCmd, poolFillCmd}
+
+// untargetedCommands are audited commands whose arguments are not the
+// cluster they act on
+var untargetedCommands = []*cobra.Command{queueRunCmd, poolFillCmd}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditCluster, "cluster", "", "only show entries for this cluster")
+	auditCmd.Flags().StringVar(&auditUser, "user", "", "only show entries by this user")
+	auditCmd.Flags().StringVar(&auditCommand, "command", "", "only show entries for this command (up, down, connect, exec, 'queue run', 'queue cancel', 'pool fill')")
+	auditCmd.Flags().StringVar(&auditSince, "since", "", "only show entries newer than this duration (e.g. 24h, 7d)")
+	auditCmd.Flags().StringVar(&auditSource, "source", "local", "audit log to read (local, host)")
+	auditCmd.Flags().StringVarP(&auditOutput, "output", "o", "table", "output format (table, json)")
+
+	for _, c := range auditedCommands {
+		c.RunE = audited(c.RunE)
+	}
+}
+
+// audited wraps a command so that every run is appended to the audit log
+func audited(run func(cmd *cobra.Command, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		start := time.Now()
+		err := run(cmd, args)
+		recordAudit(newAuditEntry(cmd, args, start, err))
+		return err
+	}
+}
+
+// newAuditEntry describes a finished command run
+func newAuditEntry(cmd *cobra.Command, args []string, start time.Time, err error) audit.Entry {
+	id := identity.Current()
+	entry := audit.Entry{
+		Timestamp:  start,
+		User:       id.Name(),
+		Host:       id.Hostname,
+		Command:    auditCommandName(cmd),
+		Args:       audit.RedactArgs(commandLine(cmd, args)),
+		Cluster:    auditTarget(cmd, args),
+		Result:     audit.ResultSuccess,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		entry.Result = audit.ResultFailure
+		entry.Error = err.Error()
+	}
+	return entry
+}
+
+// commandLine rebuilds the arguments and explicitly set flags of a run
+func commandLine(cmd *cobra.Command, args []string) []string {
+	line := append([]string(nil), args...)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		line = append(line, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return line
+}
+
+// auditCommandName names a command with its parents below the root, e.g.
+// "up" or "queue run"
+func auditCommandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// auditTarget returns the cluster a command acted on, if any
+func auditTarget(cmd *cobra.Command, args []string) string {
+	for _, c := range untargetedCommands {
+		if c == cmd {
+			return ""
+		}
+	}
+	if len(args) == 0 || args[0] == "--" {
+		return ""
+	}
+	return args[0]
+}
+
+// auditSinks returns the sinks configured for the audit log
+func auditSinks(cfg *config.Config) ([]audit.Sink, error) {
+	if cfg.Audit.Disabled {
+		return nil, nil
+	}
+
+	path := cfg.Audit.Path
+	if path == "" {
+		var err error
+		if path, err = audit.DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+	sinks := []audit.Sink{audit.NewFileLog(path)}
+
+	if cfg.Audit.HostSink {
+		sinks = append(sinks, hostAuditLog(cfg))
+	}
+	return sinks, nil
+}
+
+// hostAuditLog returns the shared audit ConfigMap on the host
+func hostAuditLog(cfg *config.Config) *audit.ConfigMapLog {
+	namespace := cfg.Audit.Namespace
+	if namespace == "" {
+		namespace = cfg.Namespace
+	}
+	if namespace == "" {
+		namespace = vcluster.DefaultNamespace
+	}
+	return audit.NewConfigMapLog(namespace, cfg.Audit.ConfigMap)
+}
+
+// recordAudit writes an entry to every configured sink. Audit failures are
+// logged but never fail the command itself.
+func recordAudit(entry audit.Entry) {
+	logger := telemetry.GetLogger()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Warn("Failed to load config for audit log, using defaults", "error", err)
+		cfg = config.DefaultConfig()
+	}
+
+	sinks, err := auditSinks(cfg)
+	if err != nil {
+		logger.Warn("Failed to open audit log", "error", err)
+		return
+	}
+	for _, sink := range sinks {
+		if err := sink.Write(entry); err != nil {
+			logger.Warn("Failed to write audit entry", "error", err)
+		}
+	}
+}
+
+func runAuditCmd(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	filter := audit.Filter{
+		Cluster: auditCluster,
+		User:    auditUser,
+		Command: auditCommand,
+	}
+	if auditSince != "" {
+		since, err := utils.ParseDuration(auditSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		}
+		filter.Since = time.Now().Add(-since)
+	}
+
+	var source audit.Source
+	switch auditSource {
+	case "local", "":
+		path := cfg.Audit.Path
+		if path == "" {
+			if path, err = audit.DefaultPath(); err != nil {
+				return err
+			}
+		}
+		source = audit.NewFileLog(path)
+	case "host":
+		source = hostAuditLog(cfg)
+	default:
+		return fmt.Errorf("unknown audit source %q (expected local or host)", auditSource)
+	}
+
+	all, err := source.Read()
+	if err != nil {
+		return err
+	}
+	entries := audit.Query(all, filter)
+
+	switch auditOutput {
+	case "json":
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit entries: %w", err)
+		}
+		fmt.Println(string(data))
+	case "table", "":
+		return displayAuditTable(entries)
+	default:
+		return fmt.Errorf("unknown output format %q (expected table or json)", auditOutput)
+	}
+	return nil
+}
+
+// displayAuditTable prints audit entries as a table
+func displayAuditTable(entries []audit.Entry) error {
+	if len(entries) == 0 {
+		fmt.Println("No audit entries found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TIME\tUSER\tCOMMAND\tCLUSTER\tRESULT\tDURATION")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			e.User,
+			e.Command,
+			valueOrDash(e.Cluster),
+			e.Result,
+			e.Duration().Round(time.Millisecond),
+		)
+	}
+	return w.Flush()
+}
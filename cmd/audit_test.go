@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/audit"
+	"github.com/spf13/cobra"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	c := &cobra.Command{Use: "up"}
+	c.Flags().String("ttl", "", "")
+	c.Flags().String("token", "", "")
+	c.Flags().Bool("yes", false, "")
+	if err := c.Flags().Parse([]string{"--ttl", "2h", "--token", "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := newAuditEntry(c, []string{"pr-512"}, time.Now(), nil)
+	if entry.Command != "up" || entry.Cluster != "pr-512" || entry.Result != audit.ResultSuccess {
+		t.Errorf("newAuditEntry() = %+v", entry)
+	}
+	wantArgs := []string{"pr-512", "--token=" + audit.Redacted, "--ttl=2h"}
+	if !reflect.DeepEqual(entry.Args, wantArgs) {
+		t.Errorf("Args = %q, want %q", entry.Args, wantArgs)
+	}
+
+	entry = newAuditEntry(c, nil, time.Now(), errors.New("boom"))
+	if entry.Result != audit.ResultFailure || entry.Error != "boom" || entry.Cluster != "" {
+		t.Errorf("newAuditEntry() with error = %+v", entry)
+	}
+
+	// Subcommands are named with their parent; pool fill's arguments are
+	// templates, not clusters
+	if entry := newAuditEntry(queueCancelCmd, []string{"pr-7"}, time.Now(), nil); entry.Command != "queue cancel" || entry.Cluster != "pr-7" {
+		t.Errorf("newAuditEntry(queue cancel) = %+v", entry)
+	}
+	if entry := newAuditEntry(poolFillCmd, []string{"default"}, time.Now(), nil); entry.Command != "pool fill" || entry.Cluster != "" {
+		t.Errorf("newAuditEntry(pool fill) = %+v", entry)
+	}
+}
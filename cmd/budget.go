@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/budget"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/spf13/cobra"
+)
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Inspect cost budgets",
+	Long: `Inspect the cost budgets configured in $HOME/.ghost/config.yaml.
+
+Budgets limit spend per user, per label (for example team=ml) or globally,
+over a daily or monthly window. 'ghostctl up' refuses to create a cluster
+whose projected cost over its TTL exceeds the remaining budget, unless
+--override-budget is given with a reason.
+
+Examples:
+  ghostctl budget status              # Show consumption of each budget
+  ghostctl budget status --user bob   # Evaluate per-user budgets for bob`,
+}
+
+var budgetStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show budget consumption",
+	Args:  cobra.NoArgs,
+	RunE:  runBudgetStatusCmd,
+}
+
+var (
+	budgetUser   string
+	budgetOutput string
+)
+
+func init() {
+	budgetStatusCmd.Flags().StringVar(&budgetUser, "user", "", "user to evaluate per-user budgets for (default: current user)")
+	budgetStatusCmd.Flags().StringVarP(&budgetOutput, "output", "o", "table", "output format (table, json)")
+	budgetCmd.AddCommand(budgetStatusCmd)
+}
+
+func runBudgetStatusCmd(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if len(cfg.Budgets) == 0 {
+		fmt.Println("No budgets configured; add a budgets section to $HOME/.ghost/config.yaml.")
+		return nil
+	}
+
+	user := budgetUser
+	if user == "" {
+		user = identity.CurrentUser()
+	}
+
+	metaStore, err := metadata.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize metadata store: %w", err)
+	}
+	clusters, err := metaStore.List()
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+	deleted, err := metaStore.ListDeleted()
+	if err != nil {
+		return fmt.Errorf("failed to list deleted clusters: %w", err)
+	}
+	clusters = append(clusters, deleted...)
+
+	now := time.Now()
+	statuses := make([]budget.Status, 0, len(cfg.Budgets))
+	for _, b := range cfg.Budgets {
+		status, err := budget.Consumption(b, user, clusters, cfg.Pricing, now)
+		if err != nil {
+			return fmt.Errorf("budget %s: %w", b.Name, err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	switch budgetOutput {
+	case "json":
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal budgets to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case "table":
+	default:
+		return fmt.Errorf("unsupported output format: %s (supported: table, json)", budgetOutput)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer func() { _ = w.Flush() }()
+
+	_, _ = fmt.Fprintln(w, "NAME\tSCOPE\tWINDOW\tSPENT\tLIMIT\tREMAINING\tUSED")
+	for _, s := range statuses {
+		used := "-"
+		if s.Budget.Limit > 0 {
+			used = fmt.Sprintf("%.0f%%", s.Spent/s.Budget.Limit*100)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			s.Budget.Name,
+			s.Scope,
+			valueOrDash(s.Budget.Window),
+			cost.Format(s.Spent, cfg.Pricing),
+			cost.Format(s.Budget.Limit, cfg.Pricing),
+			cost.Format(s.Remaining, cfg.Pricing),
+			used,
+		)
+	}
+
+	return nil
+}
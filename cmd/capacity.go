@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/capacity"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var capacityCmd = &cobra.Command{
+	Use:   "capacity",
+	Short: "Show free capacity on the host",
+	Long: `Show the CPU, memory and GPUs that are free on each host node, after
+the requests of the pods already running there, and the free GPUs of each
+type in the gpuNodeLabels table of $HOME/.ghost/config.yaml.
+
+'ghostctl up' checks the same numbers before creating a cluster. Listing
+nodes and pods needs cluster-wide read access to the host.
+
+Examples:
+  ghostctl capacity             # Free capacity per node and GPU type
+  ghostctl capacity -o json     # As JSON`,
+	Args: cobra.NoArgs,
+	RunE: runCapacityCmd,
+}
+
+var capacityOutput string
+
+func init() {
+	capacityCmd.Flags().StringVarP(&capacityOutput, "output", "o", "table", "output format (table, json, yaml)")
+}
+
+// nodeCapacity is a node's free and allocatable resources
+type nodeCapacity struct {
+	Name        string             `json:"name"`
+	Arch        string             `json:"arch,omitempty"`
+	GPUType     string             `json:"gpuType,omitempty"`
+	Schedulable bool               `json:"schedulable"`
+	Free        capacity.Resources `json:"free"`
+	Allocatable capacity.Resources `json:"allocatable"`
+}
+
+// gpuCapacity is the free and total GPUs of a type across schedulable nodes
+type gpuCapacity struct {
+	Type     string `json:"type"`
+	Resource string `json:"resource"`
+	Nodes    int    `json:"nodes"`
+	Free     int64  `json:"free"`
+	Total    int64  `json:"total"`
+}
+
+type capacityReport struct {
+	Nodes    []nodeCapacity `json:"nodes"`
+	GPUTypes []gpuCapacity  `json:"gpuTypes,omitempty"`
+}
+
+func runCapacityCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	logger.Debug("Listing host nodes")
+	nodes, err := capacity.ListNodes()
+	if err != nil {
+		return err
+	}
+	report := buildCapacityReport(nodes, cfg.GPUNodeLabels)
+
+	switch capacityOutput {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal capacity to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to marshal capacity to YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	case "table":
+	default:
+		return fmt.Errorf("unsupported output format: %s (supported: table, json, yaml)", capacityOutput)
+	}
+
+	if len(report.Nodes) == 0 {
+		fmt.Println("No nodes found on the host")
+		return nil
+	}
+	displayCapacity(report)
+	return nil
+}
+
+// buildCapacityReport summarises nodes per node and per GPU type
+func buildCapacityReport(nodes []capacity.Node, gpuNodeLabels map[string]map[string]string) capacityReport {
+	var report capacityReport
+	for i := range nodes {
+		n := &nodes[i]
+		report.Nodes = append(report.Nodes, nodeCapacity{
+			Name:        n.Name,
+			Arch:        n.Arch(),
+			GPUType:     n.GPUType(gpuNodeLabels),
+			Schedulable: n.Schedulable,
+			Free:        n.Free(),
+			Allocatable: n.Allocatable,
+		})
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Name < report.Nodes[j].Name })
+
+	types := make([]string, 0, len(gpuNodeLabels))
+	for gpuType := range gpuNodeLabels {
+		types = append(types, gpuType)
+	}
+	sort.Strings(types)
+	for _, gpuType := range types {
+		g := gpuCapacity{Type: gpuType, Resource: vcluster.GPUResourceName(gpuType)}
+		for i := range nodes {
+			n := &nodes[i]
+			if !n.Schedulable || !n.Matches(gpuNodeLabels[gpuType]) {
+				continue
+			}
+			g.Nodes++
+			g.Total += n.Allocatable.GPUs[g.Resource]
+			if free := n.Free().GPUs[g.Resource]; free > 0 {
+				g.Free += free
+			}
+		}
+		report.GPUTypes = append(report.GPUTypes, g)
+	}
+	return report
+}
+
+func displayCapacity(report capacityReport) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NODE\tARCH\tCPU FREE\tMEMORY FREE\tGPUS FREE\tGPU TYPE\tSTATUS")
+
+	var free, total capacity.Resources
+	for _, n := range report.Nodes {
+		status := "Ready"
+		if !n.Schedulable {
+			status = "Unschedulable"
+		} else {
+			free.CPUMillis += n.Free.CPUMillis
+			free.MemoryBytes += n.Free.MemoryBytes
+			total.CPUMillis += n.Allocatable.CPUMillis
+			total.MemoryBytes += n.Allocatable.MemoryBytes
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s / %s\t%s / %s\t%s\t%s\t%s\n",
+			n.Name, valueOrDash(n.Arch),
+			capacity.FormatCPU(n.Free.CPUMillis), capacity.FormatCPU(n.Allocatable.CPUMillis),
+			utils.FormatBytes(n.Free.MemoryBytes), utils.FormatBytes(n.Allocatable.MemoryBytes),
+			formatGPUs(n.Free, n.Allocatable), valueOrDash(n.GPUType), status)
+	}
+	_, _ = fmt.Fprintf(w, "TOTAL\t\t%s / %s\t%s / %s\t\t\t\n",
+		capacity.FormatCPU(free.CPUMillis), capacity.FormatCPU(total.CPUMillis),
+		utils.FormatBytes(free.MemoryBytes), utils.FormatBytes(total.MemoryBytes))
+	_ = w.Flush()
+
+	if len(report.GPUTypes) == 0 {
+		return
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "GPU TYPE\tRESOURCE\tNODES\tFREE\tTOTAL")
+	for _, g := range report.GPUTypes {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n", g.Type, g.Resource, g.Nodes, g.Free, g.Total)
+	}
+	_ = w.Flush()
+}
+
+// formatGPUs renders free and allocatable GPUs of each kind as free / total
+func formatGPUs(free, allocatable capacity.Resources) string {
+	var parts []string
+	for _, resource := range vcluster.GPUResourceNames() {
+		if total := allocatable.GPUs[resource]; total > 0 {
+			parts = append(parts, fmt.Sprintf("%d / %d %s", free.GPUs[resource], total, resource))
+		}
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
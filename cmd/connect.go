@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
 	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
 	"github.com/spf13/cobra"
 )
@@ -31,17 +33,18 @@ func runConnectCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	namespace := cfg.Namespace
-	if namespace == "" {
-		namespace = vcluster.DefaultNamespace
+	var meta *metadata.ClusterMetadata
+	if metaStore, err := metadata.NewStore(); err == nil {
+		meta, _ = metaStore.Get(clusterName)
 	}
+	namespace := hostNamespace(cfg, meta, clusterName)
 
 	kubeMgr, err := vcluster.NewKubeconfigManager("", namespace)
 	if err != nil {
 		return fmt.Errorf("failed to create kubeconfig manager: %w", err)
 	}
 
-	ref := vcluster.ClusterRef{Name: clusterName, Namespace: namespace}
+	ref := vcluster.ClusterRef{Name: hostClusterName(meta, clusterName), Namespace: namespace}
 	kubePath, err := kubeMgr.GetOrCreateKubeconfig(ref)
 	if err != nil {
 		if strings.Contains(err.Error(), "vcluster CLI not found") {
@@ -50,6 +53,10 @@ func runConnectCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("virtual cluster %q not found in namespace %q; ensure it exists or run 'ghostctl up %s' first: %w", clusterName, namespace, clusterName, err)
 	}
 
+	if metaStore, err := metadata.NewStore(); err == nil && metaStore.Exists(clusterName) {
+		recordEvent(metaStore, clusterName, metadata.NewEvent(metadata.EventConnected, identity.CurrentUser(), ""))
+	}
+
 	fmt.Printf("# Use this virtual cluster with kubectl:\n")
 	fmt.Printf("export KUBECONFIG=%s\n", kubePath)
 
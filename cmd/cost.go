@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var costCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Report cluster costs",
+	Long: `Report estimated cluster costs, grouped by template, owner or label.
+
+Costs are computed from the pricing table in $HOME/.ghost/config.yaml. Both
+active clusters and clusters deleted with 'ghostctl down' are included, each
+billed for the part of its lifetime that falls inside the reporting window.
+
+Examples:
+  ghostctl cost                          # Cost by template over the last 30 days
+  ghostctl cost --group-by owner         # Cost by owner
+  ghostctl cost --group-by label:team    # Cost by the value of the team label
+  ghostctl cost --since 7d -o json       # Last 7 days as JSON`,
+	RunE: runCostCmd,
+}
+
+var (
+	costGroupBy string
+	costSince   string
+	costOutput  string
+)
+
+func init() {
+	costCmd.Flags().StringVar(&costGroupBy, "group-by", "template", "group by: template, owner, cluster, label:<key>")
+	costCmd.Flags().StringVar(&costSince, "since", "30d", "reporting window (e.g. 24h, 7d)")
+	costCmd.Flags().StringVarP(&costOutput, "output", "o", "table", "output format (table, json, yaml)")
+}
+
+func runCostCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	window, err := utils.ParseDuration(costSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since value: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	metaStore, err := metadata.NewStore()
+	if err != nil {
+		logger.Error("Failed to initialize metadata store", "error", err)
+		return fmt.Errorf("failed to initialize metadata store: %w", err)
+	}
+
+	clusters, err := metaStore.List()
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+	deleted, err := metaStore.ListDeleted()
+	if err != nil {
+		return fmt.Errorf("failed to list deleted clusters: %w", err)
+	}
+
+	now := time.Now()
+	report, err := cost.Report(append(clusters, deleted...), cfg.Pricing, costGroupBy, now.Add(-window), now)
+	if err != nil {
+		return err
+	}
+
+	switch costOutput {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal cost report to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	case "yaml":
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to marshal cost report to YAML: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	case "table":
+	default:
+		return fmt.Errorf("unsupported output format: %s (supported: table, json, yaml)", costOutput)
+	}
+
+	if !cfg.Pricing.Configured() {
+		fmt.Println("No pricing configured; add a pricing section to $HOME/.ghost/config.yaml.")
+		return nil
+	}
+	if len(report) == 0 {
+		fmt.Printf("No clusters in the last %s\n", costSince)
+		return nil
+	}
+
+	displayCostReport(report, cfg.Pricing)
+	return nil
+}
+
+func displayCostReport(report []cost.GroupCost, pricing config.Pricing) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer func() { _ = w.Flush() }()
+
+	_, _ = fmt.Fprintf(w, "%s\tCLUSTERS\tACTIVE\tHOURLY\tACCRUED\n", columnHeader(costGroupBy))
+
+	var total cost.GroupCost
+	for _, g := range report {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n",
+			g.Group, g.Clusters, g.Active,
+			cost.Format(g.Hourly, pricing), cost.Format(g.Accrued, pricing))
+
+		total.Clusters += g.Clusters
+		total.Active += g.Active
+		total.Hourly += g.Hourly
+		total.Accrued += g.Accrued
+	}
+
+	_, _ = fmt.Fprintf(w, "TOTAL\t%d\t%d\t%s\t%s\n",
+		total.Clusters, total.Active,
+		cost.Format(total.Hourly, pricing), cost.Format(total.Accrued, pricing))
+}
+
+// columnHeader returns the table header for a grouping such as "label:team"
+func columnHeader(groupBy string) string {
+	return strings.ToUpper(strings.TrimPrefix(groupBy, "label:"))
+}
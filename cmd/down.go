@@ -3,13 +3,17 @@ package cmd
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
 	"github.com/ghostcluster-ai/ghostctl/internal/kubeconfig"
 	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
 	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
 	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -23,13 +27,20 @@ and clean up local metadata and kubeconfig files.
 
 Examples:
   ghostctl down my-cluster              # Destroy cluster with confirmation
-  ghostctl down my-cluster --force      # Force destroy without confirmation`,
+  ghostctl down my-cluster --force      # Force destroy without confirmation
+
+Destroying a cluster created by someone else requires typing the cluster
+name to confirm, even with --force. Non-interactive callers such as cleanup
+jobs must pass --ignore-owner.
+
+  ghostctl down pr-512 --force --ignore-owner`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDownCmd,
 }
 
 var (
-	force bool
+	force       bool
+	ignoreOwner bool
 )
 
 func init() {
@@ -37,6 +48,10 @@ func init() {
 		&force, "force", false,
 		"force destroy without confirmation",
 	)
+	downCmd.Flags().BoolVar(
+		&ignoreOwner, "ignore-owner", false,
+		"destroy clusters created by other users without the extra confirmation",
+	)
 }
 
 func runDownCmd(cmd *cobra.Command, args []string) error {
@@ -49,11 +64,6 @@ func runDownCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	namespace := cfg.Namespace
-	if namespace == "" {
-		namespace = vcluster.DefaultNamespace
-	}
-
 	// Initialize metadata store
 	var meta *metadata.ClusterMetadata
 	metaStore, err := metadata.NewStore()
@@ -66,23 +76,34 @@ func runDownCmd(cmd *cobra.Command, args []string) error {
 		meta, err = metaStore.Get(clusterName)
 		if err != nil {
 			logger.Info("Local metadata for cluster not found; proceeding with live deletion", "name", clusterName)
-		} else if meta.Namespace != "" {
-			namespace = meta.Namespace
+			meta = nil
 		}
 	}
+	namespace := hostNamespace(cfg, meta, clusterName)
+	hostName := hostClusterName(meta, clusterName)
 
 	logger.Info("Destroying vCluster", "name", clusterName, "namespace", namespace)
 
+	// Guard against deleting someone else's cluster
+	if meta != nil {
+		ok, err := confirmForeignOwner(cmd, meta, identity.Current())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			logger.Info("Cluster destruction cancelled")
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
 	// Confirm deletion
 	if !force {
-		fmt.Printf("Are you sure you want to destroy cluster '%s'? This cannot be undone. (y/n): ", clusterName)
-		reader := bufio.NewReader(cmd.InOrStdin())
-		response, err := reader.ReadString('\n')
+		ok, err := confirm(cmd, fmt.Sprintf("Are you sure you want to destroy cluster '%s'? This cannot be undone.", clusterName))
 		if err != nil {
 			return err
 		}
-		response = strings.TrimSpace(strings.ToLower(response))
-		if response != "y" && response != "yes" {
+		if !ok {
 			logger.Info("Cluster destruction cancelled")
 			fmt.Println("Cancelled")
 			return nil
@@ -91,26 +112,40 @@ func runDownCmd(cmd *cobra.Command, args []string) error {
 
 	// Delete the vCluster
 	logger.Info("Deleting vCluster from Kubernetes")
-	if err := vcluster.Delete(clusterName, namespace); err != nil {
+	if err := vcluster.Delete(hostName, namespace); err != nil {
 		logger.Error("Failed to delete vCluster", "error", err)
 		return fmt.Errorf("failed to delete vCluster: %w", err)
 	}
 
+	// Remove the host network policies isolating the cluster
+	if meta != nil && meta.Security.HostPolicies() {
+		if err := vcluster.DeleteNetworkPolicies(hostName, namespace); err != nil {
+			logger.Warn("Failed to remove network policies", "name", clusterName, "error", err)
+		}
+	}
+
+	// Remove the cluster's namespace once nothing else lives in it
+	if meta != nil {
+		releaseNamespace(metaStore, meta)
+	}
+
 	// Delete kubeconfig file
 	logger.Info("Cleaning up kubeconfig")
 	kubeMgr, err := kubeconfig.NewManager()
 	if err != nil {
 		logger.Warn("Failed to create kubeconfig manager, skipping cleanup", "error", err)
 	} else {
-		_ = kubeMgr.Delete(clusterName)
+		_ = kubeMgr.Delete(hostName)
 	}
 
-	// Remove from metadata store
-	if metaStore != nil {
-		if err := metaStore.Remove(clusterName); err != nil {
-			logger.Error("Failed to remove cluster metadata", "error", err)
+	// Move metadata into the deleted-cluster history
+	if metaStore != nil && meta != nil {
+		deleted := metadata.NewEvent(metadata.EventDeleted, identity.CurrentUser(), "")
+		if err := metaStore.Archive(clusterName, deleted); err != nil {
+			logger.Error("Failed to archive cluster metadata", "error", err)
 			// Don't fail here, cluster was deleted from k8s
 		}
+		pruneHistory(cfg, metaStore)
 	}
 
 	logger.Info("✓ vCluster destroyed successfully", "name", clusterName)
@@ -118,3 +153,49 @@ func runDownCmd(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// confirmForeignOwner asks for extra confirmation before destroying a cluster
+// that was created by someone other than the current user. The user must type
+// the cluster name; non-interactive callers must pass --ignore-owner.
+func confirmForeignOwner(cmd *cobra.Command, meta *metadata.ClusterMetadata, current identity.Identity) (bool, error) {
+	owner := meta.OwnerName()
+	if owner == "" || ownedByAny(meta, currentIdentities(current)) {
+		return true, nil
+	}
+
+	if ignoreOwner {
+		telemetry.GetLogger().Warn("Destroying cluster owned by another user", "name", meta.Name, "owner", owner)
+		return true, nil
+	}
+
+	if !isInteractive(cmd) {
+		return false, fmt.Errorf("cluster %q is owned by %s; pass --ignore-owner to destroy it", meta.Name, owner)
+	}
+
+	fmt.Printf("Cluster '%s' is owned by %s, not you.\n", meta.Name, owner)
+	fmt.Printf("Type the cluster name to confirm: ")
+	reader := bufio.NewReader(cmd.InOrStdin())
+	response, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || response == "") {
+		return false, err
+	}
+	return strings.TrimSpace(response) == meta.Name, nil
+}
+
+// pruneHistory drops deleted clusters older than the configured retention
+func pruneHistory(cfg *config.Config, metaStore metadata.Store) {
+	if cfg.History.Retention == "" {
+		return
+	}
+	logger := telemetry.GetLogger()
+	retention, err := utils.ParseDuration(cfg.History.Retention)
+	if err != nil {
+		logger.Warn("Invalid history retention", "retention", cfg.History.Retention, "error", err)
+		return
+	}
+	if n, err := metaStore.Prune(time.Now().Add(-retention)); err != nil {
+		logger.Warn("Failed to prune cluster history", "error", err)
+	} else if n > 0 {
+		logger.Debug("Pruned cluster history", "clusters", n)
+	}
+}
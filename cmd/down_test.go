@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/spf13/cobra"
+)
+
+func TestConfirmForeignOwner(t *testing.T) {
+	me := identity.Identity{User: "alice", Email: "alice@example.com"}
+
+	tests := []struct {
+		name        string
+		meta        *metadata.ClusterMetadata
+		ignoreOwner bool
+		want        bool
+		wantErr     bool
+	}{
+		{
+			name: "no owner recorded",
+			meta: &metadata.ClusterMetadata{Name: "old"},
+			want: true,
+		},
+		{
+			name: "owned by email",
+			meta: &metadata.ClusterMetadata{Name: "mine", Owner: "alice@example.com"},
+			want: true,
+		},
+		{
+			name: "owned by os user",
+			meta: &metadata.ClusterMetadata{Name: "mine", Owner: "ci-bot", OwnerUser: "alice"},
+			want: true,
+		},
+		{
+			name:    "someone else's cluster without a terminal",
+			meta:    &metadata.ClusterMetadata{Name: "pr-512", Owner: "bob@example.com"},
+			wantErr: true,
+		},
+		{
+			name:        "someone else's cluster with --ignore-owner",
+			meta:        &metadata.ClusterMetadata{Name: "pr-512", Owner: "bob@example.com"},
+			ignoreOwner: true,
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ignoreOwner = tt.ignoreOwner
+			defer func() { ignoreOwner = false }()
+
+			cmd := &cobra.Command{}
+			cmd.SetIn(strings.NewReader("pr-512\n"))
+
+			got, err := confirmForeignOwner(cmd, tt.meta, me)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("confirmForeignOwner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("confirmForeignOwner() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostNamespace(t *testing.T) {
+	cfg := &config.Config{Namespace: "ghostcluster", NamespaceStrategy: config.NamespacePerCluster}
+
+	recorded := &metadata.ClusterMetadata{Name: "old", Namespace: "ghostcluster"}
+	if got := hostNamespace(cfg, recorded, "old"); got != "ghostcluster" {
+		t.Errorf("hostNamespace() = %q, want the recorded namespace", got)
+	}
+	if got := hostNamespace(cfg, nil, "pr-42"); got != "ghostcluster-pr-42" {
+		t.Errorf("hostNamespace() = %q, want ghostcluster-pr-42", got)
+	}
+
+	cfg.NamespaceStrategy = config.NamespacePerOwner
+	owned := &metadata.ClusterMetadata{Name: "pr-42", Owner: "bob@example.com"}
+	if got := hostNamespace(cfg, owned, "pr-42"); got != "ghostcluster-bob-example-com-45a399ba" {
+		t.Errorf("hostNamespace() = %q, want ghostcluster-bob-example-com-45a399ba", got)
+	}
+}
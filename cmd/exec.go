@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
 	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
 	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
 	"github.com/spf13/cobra"
@@ -34,6 +35,16 @@ Examples:
 	RunE: runExecCmd,
 }
 
+// ExitCodeError reports that a command run by exec exited non-zero. ghostctl
+// exits with the same code.
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("command exited with code %d", e.Code)
+}
+
 func runExecCmd(cmd *cobra.Command, args []string) error {
 	logger := telemetry.GetLogger()
 
@@ -77,10 +88,11 @@ func runExecCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	namespace := cfg.Namespace
-	if namespace == "" {
-		namespace = vcluster.DefaultNamespace
+	var meta *metadata.ClusterMetadata
+	if metaStore, err := metadata.NewStore(); err == nil {
+		meta, _ = metaStore.Get(clusterName)
 	}
+	namespace := hostNamespace(cfg, meta, clusterName)
 
 	kubeMgr, err := vcluster.NewKubeconfigManager("", namespace)
 	if err != nil {
@@ -88,7 +100,7 @@ func runExecCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create kubeconfig manager: %w", err)
 	}
 
-	ref := vcluster.ClusterRef{Name: clusterName, Namespace: namespace}
+	ref := vcluster.ClusterRef{Name: hostClusterName(meta, clusterName), Namespace: namespace}
 	kubePath, err := kubeMgr.GetOrCreateKubeconfig(ref)
 	if err != nil {
 		logger.Error("Failed to get kubeconfig", "error", err)
@@ -113,7 +125,10 @@ func runExecCmd(cmd *cobra.Command, args []string) error {
 		logger.Error("Failed to execute command", "error", err)
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) {
-			os.Exit(exitErr.ExitCode())
+			// The command already reported its own failure
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			return &ExitCodeError{Code: exitErr.ExitCode()}
 		}
 		return fmt.Errorf("failed to execute command: %w", err)
 	}
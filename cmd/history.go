@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [cluster-name]",
+	Short: "Show the lifecycle history of clusters",
+	Long: `Show the lifecycle history of a cluster, or of deleted clusters.
+
+Each cluster keeps a history of events: queued, created, ready (with how
+long it took), claimed from a warm pool, connected, failed and deleted.
+The history outlives the cluster, so it can answer "why did my cluster
+disappear?". Deleted clusters are kept for history.retention in
+$HOME/.ghost/config.yaml (forever if unset).
+
+Examples:
+  ghostctl history pr-512                  # Every incarnation of pr-512
+  ghostctl history --deleted --since 7d    # Clusters deleted this week
+  ghostctl history pr-512 -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistoryCmd,
+}
+
+var (
+	historyDeleted bool
+	historySince   string
+	historyOutput  string
+)
+
+func init() {
+	historyCmd.Flags().BoolVar(&historyDeleted, "deleted", false, "show deleted clusters")
+	historyCmd.Flags().StringVar(&historySince, "since", "", "only show events or deletions newer than this duration (e.g. 7d)")
+	historyCmd.Flags().StringVarP(&historyOutput, "output", "o", "table", "output format (table, json)")
+}
+
+func runHistoryCmd(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !historyDeleted {
+		return fmt.Errorf("specify a cluster name or --deleted")
+	}
+	if historyOutput != "table" && historyOutput != "json" {
+		return fmt.Errorf("unknown output format %q (expected table or json)", historyOutput)
+	}
+
+	var since time.Time
+	if historySince != "" {
+		d, err := utils.ParseDuration(historySince)
+		if err != nil {
+			return fmt.Errorf("invalid --since value: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	metaStore, err := metadata.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize metadata store: %w", err)
+	}
+
+	deleted, err := metaStore.ListDeleted()
+	if err != nil {
+		return fmt.Errorf("failed to list deleted clusters: %w", err)
+	}
+
+	if len(args) == 0 {
+		clusters := deletedSince(deleted, since)
+		if historyOutput == "json" {
+			return printJSON(clusters)
+		}
+		displayDeletedClusters(clusters, cfg.Pricing)
+		return nil
+	}
+
+	name := args[0]
+	var incarnations []*metadata.ClusterMetadata
+	for _, meta := range deleted {
+		if meta.Name == name {
+			incarnations = append(incarnations, meta)
+		}
+	}
+	if !historyDeleted {
+		if meta, err := metaStore.Get(name); err == nil {
+			incarnations = append(incarnations, meta)
+		}
+	}
+	if len(incarnations) == 0 {
+		return fmt.Errorf("no history found for cluster %q", name)
+	}
+	sort.SliceStable(incarnations, func(i, j int) bool {
+		return incarnations[i].CreatedAt.Before(incarnations[j].CreatedAt)
+	})
+
+	if historyOutput == "json" {
+		for _, meta := range incarnations {
+			meta.Events = eventsSince(clusterEvents(meta), since)
+		}
+		return printJSON(incarnations)
+	}
+	for i, meta := range incarnations {
+		if i > 0 {
+			fmt.Println()
+		}
+		displayClusterHistory(meta, since, cfg.Pricing)
+	}
+	return nil
+}
+
+// clusterEvents returns a cluster's history. Clusters recorded before
+// events were kept get events synthesized from their timestamps.
+func clusterEvents(meta *metadata.ClusterMetadata) []metadata.Event {
+	if len(meta.Events) > 0 {
+		return meta.Events
+	}
+	events := []metadata.Event{{Type: metadata.EventCreated, Time: meta.CreatedAt, User: meta.OwnerName()}}
+	if meta.DeletedAt != nil {
+		events = append(events, metadata.Event{Type: metadata.EventDeleted, Time: *meta.DeletedAt})
+	}
+	return events
+}
+
+// eventsSince returns the events at or after since
+func eventsSince(events []metadata.Event, since time.Time) []metadata.Event {
+	if since.IsZero() {
+		return events
+	}
+	var result []metadata.Event
+	for _, e := range events {
+		if !e.Time.Before(since) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// deletedSince returns clusters deleted at or after since, most recent first
+func deletedSince(deleted []*metadata.ClusterMetadata, since time.Time) []*metadata.ClusterMetadata {
+	result := []*metadata.ClusterMetadata{}
+	for _, meta := range deleted {
+		if meta.DeletedAt != nil && !meta.DeletedAt.Before(since) {
+			result = append(result, meta)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DeletedAt.After(*result[j].DeletedAt)
+	})
+	return result
+}
+
+// deletionReason explains how a deleted cluster ended
+func deletionReason(meta *metadata.ClusterMetadata) string {
+	events := clusterEvents(meta)
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		switch e.Type {
+		case metadata.EventFailed:
+			return "failed: " + e.Message
+		case metadata.EventDeleted:
+			reason := "deleted"
+			if e.User != "" {
+				reason += " by " + e.User
+			}
+			if e.Message != "" {
+				reason += ": " + e.Message
+			}
+			return reason
+		}
+	}
+	return "-"
+}
+
+// eventDetails renders the details column of an event
+func eventDetails(e metadata.Event) string {
+	details := e.Message
+	if e.DurationMs > 0 {
+		took := "took " + e.Duration().Round(time.Second).String()
+		if details == "" {
+			details = took
+		} else {
+			details = took + "; " + details
+		}
+	}
+	return valueOrDash(details)
+}
+
+func displayClusterHistory(meta *metadata.ClusterMetadata, since time.Time, pricing config.Pricing) {
+	state := "active"
+	if meta.DeletedAt != nil {
+		state = "deleted " + meta.DeletedAt.Local().Format("2006-01-02 15:04")
+	}
+	fmt.Printf("Cluster: %s (%s)\n", meta.Name, state)
+	if owner := meta.OwnerName(); owner != "" {
+		fmt.Printf("Owner: %s\n", owner)
+	}
+	if pricing.Configured() {
+		fmt.Printf("Cost: %s\n", cost.Format(cost.Accrued(meta, pricing, time.Time{}, time.Now()), pricing))
+	}
+
+	events := eventsSince(clusterEvents(meta), since)
+	if len(events) == 0 {
+		fmt.Println("No events in the selected window.")
+		return
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tEVENT\tUSER\tDETAILS")
+	for _, e := range events {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05"),
+			e.Type,
+			valueOrDash(e.User),
+			eventDetails(e),
+		)
+	}
+	w.Flush()
+}
+
+func displayDeletedClusters(clusters []*metadata.ClusterMetadata, pricing config.Pricing) {
+	if len(clusters) == 0 {
+		fmt.Println("No deleted clusters found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "NAME\tOWNER\tTEMPLATE\tCREATED\tDELETED\tLIFETIME"
+	if pricing.Configured() {
+		header += "\tCOST"
+	}
+	fmt.Fprintln(w, header+"\tREASON")
+	for _, meta := range clusters {
+		row := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s",
+			meta.Name,
+			valueOrDash(meta.OwnerName()),
+			valueOrDash(meta.Template),
+			meta.CreatedAt.Local().Format("2006-01-02 15:04"),
+			meta.DeletedAt.Local().Format("2006-01-02 15:04"),
+			utils.FormatDuration(meta.DeletedAt.Sub(meta.CreatedAt)),
+		)
+		if pricing.Configured() {
+			row += "\t" + cost.Format(cost.Accrued(meta, pricing, time.Time{}, *meta.DeletedAt), pricing)
+		}
+		fmt.Fprintln(w, row+"\t"+deletionReason(meta))
+	}
+	w.Flush()
+}
+
+// printJSON prints v as indented JSON
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
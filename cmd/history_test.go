@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+)
+
+func TestDeletionReason(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		meta *metadata.ClusterMetadata
+		want string
+	}{
+		{
+			name: "deleted by user",
+			meta: &metadata.ClusterMetadata{DeletedAt: &now, Events: []metadata.Event{
+				{Type: metadata.EventCreated, User: "alice"},
+				{Type: metadata.EventDeleted, User: "bob"},
+			}},
+			want: "deleted by bob",
+		},
+		{
+			name: "failed to create",
+			meta: &metadata.ClusterMetadata{DeletedAt: &now, Events: []metadata.Event{
+				{Type: metadata.EventFailed, Message: "quota exceeded"},
+			}},
+			want: "failed: quota exceeded",
+		},
+		{
+			name: "recorded before events",
+			meta: &metadata.ClusterMetadata{DeletedAt: &now},
+			want: "deleted",
+		},
+	}
+	for _, tt := range tests {
+		if got := deletionReason(tt.meta); got != tt.want {
+			t.Errorf("%s: deletionReason() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeletedSince(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-10 * 24 * time.Hour)
+	recent := now.Add(-time.Hour)
+	newest := now.Add(-time.Minute)
+	deleted := []*metadata.ClusterMetadata{
+		{Name: "old", DeletedAt: &old},
+		{Name: "recent", DeletedAt: &recent},
+		{Name: "newest", DeletedAt: &newest},
+	}
+
+	got := deletedSince(deleted, now.Add(-7*24*time.Hour))
+	if len(got) != 2 || got[0].Name != "newest" || got[1].Name != "recent" {
+		t.Errorf("deletedSince() = %v, want newest then recent", got)
+	}
+	if got := deletedSince(deleted, time.Time{}); len(got) != 3 {
+		t.Errorf("deletedSince() without a window returned %d clusters, want 3", len(got))
+	}
+}
@@ -4,11 +4,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"sync"
 	"text/tabwriter"
+	"time"
 
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
 	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/printer"
 	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
 	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
 	"github.com/spf13/cobra"
 	"sigs.k8s.io/yaml"
 )
@@ -19,29 +28,126 @@ var listCmd = &cobra.Command{
 	Long: `List all active virtual Kubernetes clusters managed by ghostctl.
 
 This command displays cluster names, status, creation time, and TTL information
-from the local metadata store.
+from the local metadata store. Live status is resolved with a single vcluster
+list call per host namespace, followed by concurrent reachability probes.
+Clusters that have outlived their TTL are marked as expired.
+
+Output formats: table, wide, json, yaml, name, jsonpath=<template> and
+go-template=<template>. Templates see the list as {"items": [...]} with
+fields named as in the JSON output.
 
 Examples:
-  ghostctl list                  # List all clusters
-  ghostctl list --output json    # Output as JSON
-  ghostctl list --output yaml    # Output as YAML`,
+  ghostctl list                                   # List all clusters
+  ghostctl list -o wide                           # Include resources, time left and host
+  ghostctl list --selector team=ml,tier=premium   # Filter by labels
+  ghostctl list --template gpu --status running   # Filter by template and status
+  ghostctl list --mine                            # Only clusters you created
+  ghostctl list --owner alice@example.com         # Clusters created by someone else
+  ghostctl list --expiring-within 1h              # Clusters expiring in the next hour
+  ghostctl list --sort-by expires                 # Soonest to expire first
+  ghostctl list --sort-by cost                    # Most accrued cost first
+  ghostctl list --no-probe                        # Metadata only, no host queries
+  ghostctl list -o name                           # One name per line
+  ghostctl list -o jsonpath='{range .items[*]}{.name}{"\n"}{end}'
+  ghostctl list -o go-template='{{range .items}}{{.name}} {{.status}}{{"\n"}}{{end}}'`,
 	RunE: runListCmd,
 }
 
 var (
-	outputFormat string
+	outputFormat       string
+	listNoProbe        bool
+	listSelector       string
+	listTemplate       string
+	listOwner          string
+	listMine           bool
+	listStatus         string
+	listExpiringWithin string
+	listSortBy         string
 )
 
+// listProbeConcurrency bounds the number of reachability probes run at once
+const listProbeConcurrency = 8
+
+// Live status values reported by list
+const (
+	listStatusRunning     = "running"
+	listStatusUnreachable = "unreachable"
+	listStatusOffline     = "offline"
+	listStatusUnknown     = "unknown"
+)
+
+// Host lookups used by list, replaceable in tests
+var (
+	listHostClusters = vcluster.List
+	probeCluster     = probeClusterReachable
+)
+
+// clusterListEntry pairs stored metadata with the live status resolved for it
+type clusterListEntry struct {
+	*metadata.ClusterMetadata
+	Status    string     `json:"status"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Expired   bool       `json:"expired"`
+
+	AccruedCost float64 `json:"accruedCost"`
+}
+
+// remaining returns the time left before the cluster expires, if it has a TTL
+func (e clusterListEntry) remaining(now time.Time) string {
+	switch {
+	case e.ExpiresAt == nil:
+		return "-"
+	case e.Expired:
+		return "expired"
+	default:
+		return utils.FormatDuration(e.ExpiresAt.Sub(now))
+	}
+}
+
 func init() {
-	listCmd.Flags().StringVar(
-		&outputFormat, "output", "table",
-		"output format (table, json, yaml)",
+	listCmd.Flags().StringVarP(
+		&outputFormat, "output", "o", "table",
+		"output format (table, wide, json, yaml, name, jsonpath=..., go-template=...)",
 	)
+	listCmd.Flags().BoolVar(
+		&listNoProbe, "no-probe", false,
+		"skip host queries and reachability probes (metadata only)",
+	)
+	listCmd.Flags().StringVarP(
+		&listSelector, "selector", "l", "",
+		"label selector, e.g. team=ml,tier!=basic,gpu",
+	)
+	listCmd.Flags().StringVar(&listTemplate, "template", "", "only show clusters created from this template")
+	listCmd.Flags().StringVar(&listOwner, "owner", "", "only show clusters owned by this user, email or CI actor")
+	listCmd.Flags().BoolVar(&listMine, "mine", false, "only show clusters created by the current user")
+	listCmd.Flags().StringVar(&listStatus, "status", "", "only show clusters with this status (running, unreachable, offline, unknown, expired)")
+	listCmd.Flags().StringVar(&listExpiringWithin, "expiring-within", "", "only show clusters expiring within this duration (e.g. 1h)")
+	listCmd.Flags().StringVar(&listSortBy, "sort-by", "name", "sort by: created, expires, name, cost")
 }
 
 func runListCmd(cmd *cobra.Command, args []string) error {
 	logger := telemetry.GetLogger()
 
+	format, err := printer.ParseFormat(outputFormat)
+	if err != nil {
+		return err
+	}
+	switch format.Name {
+	case "table", "wide", "json", "yaml", "name", "jsonpath", "go-template":
+	default:
+		return fmt.Errorf("unsupported output format: %s (supported: table, wide, json, yaml, name, jsonpath=..., go-template=...)", format.Name)
+	}
+
+	filter, err := newListFilter()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
 	logger.Info("Listing vClusters")
 
 	// Initialize metadata store
@@ -58,53 +164,409 @@ func runListCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list clusters: %w", err)
 	}
 
-	if len(clusters) == 0 {
+	// Apply metadata filters before querying the host
+	clusters = filter.matchMetadata(clusters)
+
+	now := time.Now()
+	entries := resolveClusterStatuses(clusters, !listNoProbe, logger)
+	annotateClusterEntries(entries, cfg.Pricing, now)
+	entries = filter.matchEntries(entries, now)
+
+	if err := sortClusterEntries(entries, listSortBy); err != nil {
+		return err
+	}
+
+	if len(entries) == 0 && (format.Name == "table" || format.Name == "wide") {
 		fmt.Println("No active clusters found")
 		return nil
 	}
 
 	// Display clusters based on output format
-	switch outputFormat {
+	switch format.Name {
 	case "json":
-		return displayClustersJSON(clusters)
+		return displayClustersJSON(entries)
 	case "yaml":
-		return displayClustersYAML(clusters)
+		return displayClustersYAML(entries)
+	case "name":
+		for _, e := range entries {
+			fmt.Println(e.Name)
+		}
+	case "jsonpath":
+		return printer.JSONPath(os.Stdout, format.Template, clusterListObject(entries))
+	case "go-template":
+		return printer.GoTemplate(os.Stdout, format.Template, clusterListObject(entries))
 	default:
-		displayClustersTable(clusters)
+		displayClustersTable(entries, format.Name == "wide", now, cfg.Pricing)
 	}
 
 	return nil
 }
 
-func displayClustersTable(clusters []*metadata.ClusterMetadata) {
+// listFilter holds the parsed list filter flags
+type listFilter struct {
+	selector       labelSelector
+	template       string
+	owner          string
+	mine           []string
+	status         string
+	expiringWithin time.Duration
+}
+
+func newListFilter() (*listFilter, error) {
+	selector, err := parseLabelSelector(listSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &listFilter{
+		selector: selector,
+		template: listTemplate,
+		owner:    listOwner,
+		status:   listStatus,
+	}
+
+	if listMine {
+		f.mine = currentIdentities(identity.Current())
+	}
+
+	if listExpiringWithin != "" {
+		f.expiringWithin, err = utils.ParseDuration(listExpiringWithin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --expiring-within value: %w", err)
+		}
+	}
+
+	return f, nil
+}
+
+// matchMetadata applies the filters that only need stored metadata
+func (f *listFilter) matchMetadata(clusters []*metadata.ClusterMetadata) []*metadata.ClusterMetadata {
+	var result []*metadata.ClusterMetadata
+	for _, c := range clusters {
+		if f.template != "" && c.Template != f.template {
+			continue
+		}
+		if f.owner != "" && !c.OwnedBy(f.owner) {
+			continue
+		}
+		if f.mine != nil && !ownedByAny(c, f.mine) {
+			continue
+		}
+		if !f.selector.matches(c.Labels) {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// currentIdentities returns every form of id a cluster's owner may be recorded as
+func currentIdentities(id identity.Identity) []string {
+	var ids []string
+	for _, v := range []string{id.Name(), id.User, id.Email, id.CIActor} {
+		if v != "" {
+			ids = append(ids, v)
+		}
+	}
+	return ids
+}
+
+// ownedByAny reports whether the cluster is owned by any of the identities
+func ownedByAny(c *metadata.ClusterMetadata, ids []string) bool {
+	for _, id := range ids {
+		if c.OwnedBy(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// annotateClusterEntries fills in expiry and accrued cost for each entry
+func annotateClusterEntries(entries []clusterListEntry, pricing config.Pricing, now time.Time) {
+	for i := range entries {
+		e := &entries[i]
+		if expiresAt, ok := e.ClusterMetadata.ExpiresAt(); ok {
+			e.ExpiresAt = &expiresAt
+			e.Expired = e.ClusterMetadata.Expired(now)
+		}
+		e.AccruedCost = cost.Accrued(e.ClusterMetadata, pricing, time.Time{}, now)
+	}
+}
+
+// matchEntries applies the filters that need live status and expiry
+func (f *listFilter) matchEntries(entries []clusterListEntry, now time.Time) []clusterListEntry {
+	var result []clusterListEntry
+	for _, e := range entries {
+		if f.status == "expired" && !e.Expired {
+			continue
+		}
+		if f.status != "" && f.status != "expired" && e.Status != f.status {
+			continue
+		}
+		if f.expiringWithin > 0 && (e.ExpiresAt == nil || e.ExpiresAt.After(now.Add(f.expiringWithin))) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// labelSelector is a parsed kubectl-style equality selector
+type labelSelector []labelRequirement
+
+type labelRequirement struct {
+	key      string
+	value    string
+	operator string // "=", "!=" or "exists"
+}
+
+// parseLabelSelector parses selectors like "team=ml,tier!=basic,gpu"
+func parseLabelSelector(s string) (labelSelector, error) {
+	var selector labelSelector
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		req := labelRequirement{operator: "exists", key: part}
+		if key, value, ok := strings.Cut(part, "!="); ok {
+			req = labelRequirement{key: key, value: value, operator: "!="}
+		} else if key, value, ok := strings.Cut(part, "=="); ok {
+			req = labelRequirement{key: key, value: value, operator: "="}
+		} else if key, value, ok := strings.Cut(part, "="); ok {
+			req = labelRequirement{key: key, value: value, operator: "="}
+		}
+
+		req.key = strings.TrimSpace(req.key)
+		req.value = strings.TrimSpace(req.value)
+		if req.key == "" {
+			return nil, fmt.Errorf("invalid label selector %q: empty key", part)
+		}
+		selector = append(selector, req)
+	}
+	return selector, nil
+}
+
+func (s labelSelector) matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, exists := labels[req.key]
+		switch req.operator {
+		case "exists":
+			if !exists {
+				return false
+			}
+		case "=":
+			if !exists || value != req.value {
+				return false
+			}
+		case "!=":
+			if exists && value == req.value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sortClusterEntries orders entries by the given key
+func sortClusterEntries(entries []clusterListEntry, sortBy string) error {
+	var less func(a, b clusterListEntry) bool
+	switch sortBy {
+	case "", "name":
+		less = func(a, b clusterListEntry) bool { return a.Name < b.Name }
+	case "created":
+		less = func(a, b clusterListEntry) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "expires":
+		// Clusters without a TTL never expire and sort last
+		less = func(a, b clusterListEntry) bool {
+			if a.ExpiresAt == nil || b.ExpiresAt == nil {
+				return a.ExpiresAt != nil && b.ExpiresAt == nil
+			}
+			return a.ExpiresAt.Before(*b.ExpiresAt)
+		}
+	case "cost":
+		// Most expensive first
+		less = func(a, b clusterListEntry) bool { return a.AccruedCost > b.AccruedCost }
+	default:
+		return fmt.Errorf("unsupported sort key: %s (supported: created, expires, name, cost)", sortBy)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return less(entries[i], entries[j]) })
+	return nil
+}
+
+// resolveClusterStatuses determines the live status of each cluster.
+// The host is queried once per namespace; clusters found there are then
+// probed for reachability through a bounded worker pool.
+func resolveClusterStatuses(clusters []*metadata.ClusterMetadata, probe bool, logger *telemetry.Logger) []clusterListEntry {
+	entries := make([]clusterListEntry, len(clusters))
+	for i, c := range clusters {
+		entries[i] = clusterListEntry{ClusterMetadata: c, Status: listStatusUnknown}
+	}
+
+	if !probe {
+		return entries
+	}
+
+	// One host query per namespace
+	hostClusters := make(map[string]map[string]bool)
+	for _, c := range clusters {
+		ns := clusterNamespace(c)
+		if _, done := hostClusters[ns]; done {
+			continue
+		}
+
+		names, err := listHostClusters(ns)
+		if err != nil {
+			logger.Warn("Failed to list vClusters on host", "namespace", ns, "error", err)
+			hostClusters[ns] = nil
+			continue
+		}
+
+		found := make(map[string]bool, len(names))
+		for _, name := range names {
+			found[name] = true
+		}
+		hostClusters[ns] = found
+	}
+
+	// Probe clusters that exist on the host concurrently
+	sem := make(chan struct{}, listProbeConcurrency)
+	var wg sync.WaitGroup
+	for i := range entries {
+		found := hostClusters[clusterNamespace(entries[i].ClusterMetadata)]
+		if found == nil {
+			continue
+		}
+		if !found[entries[i].VClusterName()] {
+			entries[i].Status = listStatusOffline
+			continue
+		}
+
+		wg.Add(1)
+		go func(entry *clusterListEntry) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if err := probeCluster(entry.ClusterMetadata); err != nil {
+				logger.Debug("Cluster reachability probe failed", "name", entry.Name, "error", err)
+				entry.Status = listStatusUnreachable
+				return
+			}
+			entry.Status = listStatusRunning
+		}(&entries[i])
+	}
+	wg.Wait()
+
+	return entries
+}
+
+// probeClusterReachable checks that the vCluster API server answers using its kubeconfig
+func probeClusterReachable(c *metadata.ClusterMetadata) error {
+	namespace := clusterNamespace(c)
+	kubeMgr, err := vcluster.NewKubeconfigManager("", namespace)
+	if err != nil {
+		return err
+	}
+
+	path, err := kubeMgr.GetOrCreateKubeconfig(vcluster.ClusterRef{Name: c.VClusterName(), Namespace: namespace})
+	if err != nil {
+		return err
+	}
+
+	return checkKubeconfigReachable(path)
+}
+
+// clusterNamespace returns the host namespace recorded for a cluster
+func clusterNamespace(c *metadata.ClusterMetadata) string {
+	if c.Namespace == "" {
+		return vcluster.DefaultNamespace
+	}
+	return c.Namespace
+}
+
+// clusterListObject wraps entries the way kubectl wraps lists for templates
+func clusterListObject(entries []clusterListEntry) map[string]interface{} {
+	if entries == nil {
+		entries = []clusterListEntry{}
+	}
+	return map[string]interface{}{"items": entries}
+}
+
+func displayClustersTable(entries []clusterListEntry, wide bool, now time.Time, pricing config.Pricing) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	defer func() { _ = w.Flush() }()
 
 	// Header
-	_, _ = fmt.Fprintln(w, "NAME\tNAMESPACE\tSTATUS\tCREATED\tTTL")
+	if wide {
+		_, _ = fmt.Fprintln(w, "NAME\tNAMESPACE\tSTATUS\tCREATED\tTTL\tTEMPLATE\tCPU\tMEMORY\tGPU\tREMAINING\tCOST\tHOST")
+	} else {
+		_, _ = fmt.Fprintln(w, "NAME\tNAMESPACE\tSTATUS\tCREATED\tTTL")
+	}
 
 	// Rows
-	for _, c := range clusters {
-		// Check if cluster is actually running
-		var status string
-		if err := vcluster.Status(c.Name, c.Namespace); err == nil {
-			status = "running"
-		} else {
-			status = "offline"
+	for _, c := range entries {
+		ttl := valueOrDash(c.TTL)
+		if c.Expired {
+			ttl += " (expired)"
+		}
+
+		if !wide {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				c.Name,
+				c.Namespace,
+				c.Status,
+				c.CreatedAt.Format("2006-01-02 15:04"),
+				ttl,
+			)
+			continue
 		}
 
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+		gpu := "-"
+		if c.GPU > 0 {
+			gpu = fmt.Sprintf("%d", c.GPU)
+			if c.GPUType != "" {
+				gpu += " (" + c.GPUType + ")"
+			}
+		}
+
+		accrued := "-"
+		if c.AccruedCost > 0 {
+			accrued = cost.Format(c.AccruedCost, pricing)
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 			c.Name,
 			c.Namespace,
-			status,
+			c.Status,
 			c.CreatedAt.Format("2006-01-02 15:04"),
-			c.TTL,
+			ttl,
+			valueOrDash(c.Template),
+			valueOrDash(c.CPU),
+			valueOrDash(c.Memory),
+			gpu,
+			c.remaining(now),
+			accrued,
+			valueOrDash(c.HostCluster),
 		)
 	}
 }
 
-func displayClustersJSON(clusters []*metadata.ClusterMetadata) error {
-	data, err := json.MarshalIndent(clusters, "", "  ")
+// valueOrDash returns "-" for empty table cells
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func displayClustersJSON(entries []clusterListEntry) error {
+	if entries == nil {
+		entries = []clusterListEntry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal clusters to JSON: %w", err)
 	}
@@ -112,8 +574,11 @@ func displayClustersJSON(clusters []*metadata.ClusterMetadata) error {
 	return nil
 }
 
-func displayClustersYAML(clusters []*metadata.ClusterMetadata) error {
-	data, err := yaml.Marshal(clusters)
+func displayClustersYAML(entries []clusterListEntry) error {
+	if entries == nil {
+		entries = []clusterListEntry{}
+	}
+	data, err := yaml.Marshal(entries)
 	if err != nil {
 		return fmt.Errorf("failed to marshal clusters to YAML: %w", err)
 	}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+)
+
+func TestResolveClusterStatusesQueriesHostOncePerNamespace(t *testing.T) {
+	var mu sync.Mutex
+	listCalls := map[string]int{}
+
+	origList, origProbe := listHostClusters, probeCluster
+	t.Cleanup(func() { listHostClusters, probeCluster = origList, origProbe })
+
+	listHostClusters = func(namespace string) ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		listCalls[namespace]++
+		if namespace == "broken" {
+			return nil, fmt.Errorf("host unavailable")
+		}
+		return []string{"pr-1", "pr-2", "pr-3"}, nil
+	}
+	probeCluster = func(c *metadata.ClusterMetadata) error {
+		if c.Name == "pr-2" {
+			return fmt.Errorf("connection refused")
+		}
+		return nil
+	}
+
+	clusters := []*metadata.ClusterMetadata{
+		{Name: "pr-1", Namespace: "ghostcluster"},
+		{Name: "pr-2", Namespace: "ghostcluster"},
+		{Name: "pr-3"},
+		{Name: "pr-4", Namespace: "ghostcluster"},
+		{Name: "pr-5", Namespace: "broken"},
+	}
+
+	entries := resolveClusterStatuses(clusters, true, telemetry.GetLogger())
+
+	want := map[string]string{
+		"pr-1": listStatusRunning,
+		"pr-2": listStatusUnreachable,
+		"pr-3": listStatusRunning,
+		"pr-4": listStatusOffline,
+		"pr-5": listStatusUnknown,
+	}
+	for _, e := range entries {
+		if e.Status != want[e.Name] {
+			t.Errorf("status for %s = %q, want %q", e.Name, e.Status, want[e.Name])
+		}
+	}
+
+	if listCalls["ghostcluster"] != 1 || listCalls["broken"] != 1 {
+		t.Fatalf("expected one host query per namespace, got %v", listCalls)
+	}
+}
+
+func TestResolveClusterStatusesWithoutProbe(t *testing.T) {
+	origList := listHostClusters
+	t.Cleanup(func() { listHostClusters = origList })
+
+	listHostClusters = func(namespace string) ([]string, error) {
+		t.Fatalf("host should not be queried with probing disabled")
+		return nil, nil
+	}
+
+	entries := resolveClusterStatuses([]*metadata.ClusterMetadata{{Name: "pr-1"}}, false, telemetry.GetLogger())
+	if entries[0].Status != listStatusUnknown {
+		t.Fatalf("expected unknown status, got %q", entries[0].Status)
+	}
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	selector, err := parseLabelSelector("team=ml, tier!=basic,gpu")
+	if err != nil {
+		t.Fatalf("parseLabelSelector error: %v", err)
+	}
+
+	tests := []struct {
+		labels map[string]string
+		want   bool
+	}{
+		{map[string]string{"team": "ml", "tier": "premium", "gpu": "enabled"}, true},
+		{map[string]string{"team": "ml", "gpu": "enabled"}, true},
+		{map[string]string{"team": "ml", "tier": "basic", "gpu": "enabled"}, false},
+		{map[string]string{"team": "web", "gpu": "enabled"}, false},
+		{map[string]string{"team": "ml"}, false},
+	}
+	for _, tt := range tests {
+		if got := selector.matches(tt.labels); got != tt.want {
+			t.Errorf("matches(%v) = %v, want %v", tt.labels, got, tt.want)
+		}
+	}
+
+	if _, err := parseLabelSelector("=ml"); err == nil {
+		t.Fatalf("expected error for empty selector key")
+	}
+}
+
+func TestListFilterExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []clusterListEntry{
+		{ClusterMetadata: &metadata.ClusterMetadata{Name: "expired", CreatedAt: now.Add(-2 * time.Hour), TTL: "1h"}},
+		{ClusterMetadata: &metadata.ClusterMetadata{Name: "soon", CreatedAt: now.Add(-30 * time.Minute), TTL: "1h"}},
+		{ClusterMetadata: &metadata.ClusterMetadata{Name: "later", CreatedAt: now, TTL: "1d"}},
+		{ClusterMetadata: &metadata.ClusterMetadata{Name: "forever", CreatedAt: now}},
+	}
+
+	annotateClusterEntries(entries, config.Pricing{}, now)
+
+	f := &listFilter{expiringWithin: time.Hour}
+	got := f.matchEntries(entries, now)
+	if len(got) != 2 || got[0].Name != "expired" || got[1].Name != "soon" {
+		t.Fatalf("unexpected entries expiring within 1h: %+v", got)
+	}
+	if !got[0].Expired || got[1].Expired {
+		t.Fatalf("expected only the first entry to be expired")
+	}
+	if got[1].remaining(now) != "30m" {
+		t.Fatalf("expected 30m remaining, got %s", got[1].remaining(now))
+	}
+
+	f = &listFilter{status: "expired"}
+	if got := f.matchEntries(entries, now); len(got) != 1 || got[0].Name != "expired" {
+		t.Fatalf("unexpected expired entries: %+v", got)
+	}
+}
+
+func TestSortClusterEntriesByExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []clusterListEntry{
+		{ClusterMetadata: &metadata.ClusterMetadata{Name: "forever", CreatedAt: now}},
+		{ClusterMetadata: &metadata.ClusterMetadata{Name: "later", CreatedAt: now, TTL: "2h"}},
+		{ClusterMetadata: &metadata.ClusterMetadata{Name: "sooner", CreatedAt: now, TTL: "1h"}},
+	}
+	annotateClusterEntries(entries, config.Pricing{}, now)
+
+	if err := sortClusterEntries(entries, "expires"); err != nil {
+		t.Fatalf("sortClusterEntries error: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	if strings.Join(names, ",") != "sooner,later,forever" {
+		t.Fatalf("unexpected order: %v", names)
+	}
+
+	if err := sortClusterEntries(entries, "size"); err == nil {
+		t.Fatalf("expected error for unsupported sort key")
+	}
+}
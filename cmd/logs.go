@@ -91,7 +91,7 @@ func runLogsCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create kubeconfig manager: %w", err)
 	}
 
-	kubePath, err := kubeMgr.Get(clusterName, meta.Namespace)
+	kubePath, err := kubeMgr.Get(meta.VClusterName(), meta.Namespace)
 	if err != nil {
 		logger.Error("Failed to get kubeconfig", "error", err)
 		return fmt.Errorf("failed to get kubeconfig for cluster %q: %w", clusterName, err)
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+)
+
+// hostNamespace returns the host namespace of a cluster: the one recorded
+// in its metadata, or else the one the configured namespace strategy gives
+// it. meta may be nil.
+func hostNamespace(cfg *config.Config, meta *metadata.ClusterMetadata, name string) string {
+	if meta != nil && meta.Namespace != "" {
+		return meta.Namespace
+	}
+
+	owner := ""
+	if meta != nil {
+		owner = meta.OwnerName()
+	}
+	if owner == "" {
+		owner = identity.Current().Name()
+	}
+
+	namespace, err := vcluster.NamespaceFor(cfg.NamespaceStrategy, cfg.Namespace, name, owner)
+	if err != nil {
+		telemetry.GetLogger().Warn("Falling back to the shared namespace", "error", err)
+		namespace = cfg.Namespace
+		if namespace == "" {
+			namespace = vcluster.DefaultNamespace
+		}
+	}
+	return namespace
+}
+
+// hostClusterName returns the name of a cluster's vCluster on the host,
+// which differs from its name for a claimed pool member. meta may be nil.
+func hostClusterName(meta *metadata.ClusterMetadata, name string) string {
+	if meta != nil {
+		return meta.VClusterName()
+	}
+	return name
+}
+
+// namespaceLabels returns the labels of a namespace created for a cluster
+// under a namespace strategy
+func namespaceLabels(strategy, clusterName, owner string) map[string]string {
+	labels := map[string]string{vcluster.NamespaceStrategyLabel: strategy}
+	switch strategy {
+	case config.NamespacePerCluster:
+		labels[vcluster.ClusterLabel] = clusterName
+	case config.NamespacePerOwner:
+		if value := vcluster.SanitizeLabelValue(owner); value != "" {
+			labels[ownerLabelKey] = value
+		}
+	}
+	return labels
+}
+
+// ownsNamespace reports whether a cluster was given a namespace of its own
+// to create and delete
+func ownsNamespace(strategy string) bool {
+	return strategy == config.NamespacePerCluster || strategy == config.NamespacePerOwner
+}
+
+// releaseNamespace deletes the namespace a cluster was created in once no
+// cluster uses it any more. Clusters in the shared namespace leave it alone.
+func releaseNamespace(metaStore metadata.Store, meta *metadata.ClusterMetadata) {
+	logger := telemetry.GetLogger()
+	if !ownsNamespace(meta.NamespaceStrategy) || meta.Namespace == "" {
+		return
+	}
+
+	if meta.NamespaceStrategy == config.NamespacePerOwner {
+		if metaStore == nil {
+			return
+		}
+		others, err := namespaceClusters(metaStore, meta.Namespace, meta.Name)
+		if err != nil {
+			logger.Warn("Failed to list clusters; keeping namespace", "namespace", meta.Namespace, "error", err)
+			return
+		}
+		if len(others) > 0 {
+			// Shrink the namespace's resource limits to the clusters left
+			logger.Debug("Namespace still in use", "namespace", meta.Namespace, "clusters", len(others))
+			if err := vcluster.ApplyResourceLimits(meta.Namespace, clusterResources(others)); err != nil {
+				logger.Warn("Failed to update resource limits", "namespace", meta.Namespace, "error", err)
+			}
+			return
+		}
+	}
+
+	logger.Info("Deleting namespace", "namespace", meta.Namespace)
+	if err := vcluster.DeleteNamespace(meta.Namespace); err != nil {
+		logger.Warn("Failed to delete namespace", "namespace", meta.Namespace, "error", err)
+	}
+}
+
+// namespaceClusters returns the clusters recorded in a host namespace,
+// other than the one named except
+func namespaceClusters(metaStore metadata.Store, namespace, except string) ([]*metadata.ClusterMetadata, error) {
+	clusters, err := metaStore.List()
+	if err != nil {
+		return nil, err
+	}
+	var found []*metadata.ClusterMetadata
+	for _, c := range clusters {
+		if c.Name != except && c.Namespace == namespace {
+			found = append(found, c)
+		}
+	}
+	return found, nil
+}
+
+// clusterResources returns the resources each cluster was created with
+func clusterResources(clusters []*metadata.ClusterMetadata) []cost.Resources {
+	resources := make([]cost.Resources, len(clusters))
+	for i, c := range clusters {
+		resources[i] = cost.Resources{CPU: c.CPU, Memory: c.Memory, Storage: c.Storage, GPU: c.GPU, GPUType: c.GPUType}
+	}
+	return resources
+}
+
+// applyResourceLimits bounds a cluster's namespace by the resources of the
+// clusters in it, including meta, which need not be recorded yet. The
+// shared namespace is not bounded: its quota would limit every cluster in
+// it, and anything else running there.
+func applyResourceLimits(metaStore metadata.Store, meta *metadata.ClusterMetadata) error {
+	if !ownsNamespace(meta.NamespaceStrategy) {
+		return nil
+	}
+	others, err := namespaceClusters(metaStore, meta.Namespace, meta.Name)
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+	return vcluster.ApplyResourceLimits(meta.Namespace, clusterResources(append(others, meta)))
+}
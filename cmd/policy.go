@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
+	"github.com/ghostcluster-ai/ghostctl/internal/policy"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Inspect and test the cluster creation policy",
+	Long: `Inspect and test the cluster creation policy.
+
+The policy is a YAML file of rules (default: $HOME/.ghost/policy.yaml, or
+policyFile in config.yaml) evaluated against every resolved cluster request
+before it is created. The built-in valid-name rule, requiring cluster names
+to be lowercase DNS labels, always applies before the rules of the file.
+
+Example policy:
+  rules:
+    - name: gpu-cost-center
+      match: {gpu: true}
+      require: {labels: [cost-center]}
+    - name: large-ttl
+      match: {templates: [large]}
+      require: {maxTTL: 8h}
+    - name: ci-names
+      match: {ci: true}
+      require: {namePattern: "pr-[0-9]+"}`,
+}
+
+var policyTestCmd = &cobra.Command{
+	Use:   "test <cluster-name>",
+	Short: "Evaluate a hypothetical cluster request against the policy",
+	Long: `Evaluate a hypothetical cluster request against the policy and explain
+the result of every rule. The request is resolved exactly as 'ghostctl up'
+would resolve it, including template defaults.
+
+Exits with an error if the request would be denied.
+
+Examples:
+  ghostctl policy test pr-123 --template gpu
+  ghostctl policy test pr-123 --template gpu --label cost-center=ml-42
+  ghostctl policy test big --template large --ttl 1d
+  ghostctl policy test my-branch --ci                # As if running in CI`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolicyTestCmd,
+}
+
+var (
+	policyFile      string
+	policyOperation string
+	policyUser      string
+	policyCI        bool
+	policyOutput    string
+)
+
+func init() {
+	addClusterSpecFlags(policyTestCmd)
+	policyTestCmd.Flags().StringVar(&policyFile, "file", "", "policy file to evaluate (default: configured policy)")
+	policyTestCmd.Flags().StringVar(&policyOperation, "operation", policy.OperationUp, "operation to evaluate (up, update, extend)")
+	policyTestCmd.Flags().StringVar(&policyUser, "user", "", "user making the request (default: current user)")
+	policyTestCmd.Flags().BoolVar(&policyCI, "ci", false, "evaluate as if running in CI")
+	policyTestCmd.Flags().StringVarP(&policyOutput, "output", "o", "text", "output format (text, json)")
+	policyCmd.AddCommand(policyTestCmd)
+}
+
+func runPolicyTestCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	path := policyFile
+	if path == "" {
+		path = cfg.PolicyFile
+	}
+	p, err := policy.Load(path)
+	if err != nil {
+		return err
+	}
+
+	opts, err := buildCreateOptions(cmd, args[0], logger)
+	if err != nil {
+		return err
+	}
+
+	user := policyUser
+	if user == "" {
+		user = identity.CurrentUser()
+	}
+
+	decision := p.Evaluate(policy.Request{
+		Operation: policyOperation,
+		Template:  upTemplate,
+		User:      user,
+		CI:        policyCI || identity.InCI(),
+		Options:   opts,
+	})
+
+	switch policyOutput {
+	case "json":
+		data, err := json.MarshalIndent(decision, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal decision to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "text":
+		displayPolicyDecision(decision)
+	default:
+		return fmt.Errorf("unsupported output format: %s (supported: text, json)", policyOutput)
+	}
+
+	// A denial is a result, not a usage error
+	cmd.SilenceUsage = true
+	return decision.Err()
+}
+
+func displayPolicyDecision(decision policy.Decision) {
+	for _, r := range decision.Results {
+		switch {
+		case !r.Applicable:
+			fmt.Printf("- %s: not applicable (%s)\n", r.Rule, r.Reasons[0])
+		case r.Passed:
+			fmt.Printf("✓ %s: passed\n", r.Rule)
+		default:
+			fmt.Printf("✗ %s: failed\n", r.Rule)
+			for _, reason := range r.Reasons {
+				fmt.Printf("    %s\n", reason)
+			}
+		}
+	}
+
+	if decision.Allowed {
+		fmt.Println("\nResult: allowed")
+	} else {
+		fmt.Println("\nResult: denied")
+	}
+}
@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/capacity"
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var poolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Manage warm pools of ready clusters",
+	Long: `Manage the warm pools configured under pools in $HOME/.ghost/config.yaml.
+
+A pool keeps a number of clusters of a template created and ready ahead of
+time. 'ghostctl up --template <template>' claims a ready pool member
+instead of creating a cluster, which takes seconds instead of minutes: the
+member is renamed to the requested name, takes the requester's owner and
+labels, and its TTL starts at the claim.
+
+Pools are not used under namespaceStrategy per-owner: a claimed member
+would stay in the pool's namespace, under its quota, not the owner's.
+
+Pools are kept in the metadata store, so with a shared store one filler
+serves everyone. Keep 'ghostctl pool fill --watch' running in the
+background, or run 'ghostctl pool fill' from a CronJob.
+
+Examples:
+  ghostctl pool status                  # Ready members of each pool
+  ghostctl pool fill                    # Top up every pool, then exit
+  ghostctl pool fill --watch            # Keep the pools topped up every minute`,
+}
+
+var poolStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the members of each warm pool",
+	Args:  cobra.NoArgs,
+	RunE:  runPoolStatusCmd,
+}
+
+var poolFillCmd = &cobra.Command{
+	Use:   "fill [template]...",
+	Short: "Create clusters until each pool has its size",
+	Long: `Create pool members until each pool has as many unclaimed members as
+its size, for every configured pool or only the given templates.
+
+Members are created from the template with no parameters and no TTL, and
+owned by ghostctl-pool. Quotas, budgets and the creation policy apply when a
+member is claimed, not when it is created; host capacity is checked before
+each member is created. Members are created one at a time, each waiting
+until it is ready. With --watch the pools are checked every --interval
+until interrupted.
+
+Failed members are not replaced; remove them with 'ghostctl down'.`,
+	RunE: runPoolFillCmd,
+}
+
+var (
+	poolOutput   string
+	poolWatch    bool
+	poolInterval string
+)
+
+func init() {
+	poolStatusCmd.Flags().StringVarP(&poolOutput, "output", "o", "table", "output format (table, json)")
+	poolFillCmd.Flags().BoolVar(&poolWatch, "watch", false, "keep running, checking the pools every --interval")
+	poolFillCmd.Flags().StringVar(&poolInterval, "interval", "1m", "how often --watch checks the pools")
+	poolCmd.AddCommand(poolStatusCmd, poolFillCmd)
+}
+
+// States of a pool member
+const (
+	poolMemberReady    = "ready"
+	poolMemberStarting = "starting"
+	poolMemberFailed   = "failed"
+)
+
+// poolMemberState returns the state of a pool member from its history
+func poolMemberState(meta *metadata.ClusterMetadata) string {
+	last, ok := meta.LastEvent()
+	if !ok {
+		return poolMemberStarting
+	}
+	switch last.Type {
+	case metadata.EventReady:
+		return poolMemberReady
+	case metadata.EventFailed:
+		return poolMemberFailed
+	default:
+		return poolMemberStarting
+	}
+}
+
+// poolStatus counts the members of a pool
+type poolStatus struct {
+	Template string   `json:"template"`
+	Size     int      `json:"size"`
+	Ready    []string `json:"ready"`
+	Starting []string `json:"starting"`
+	Failed   []string `json:"failed"`
+	Claimed  int      `json:"claimed"` // active clusters claimed from the pool
+}
+
+// unclaimed returns how many members count towards the pool's size
+func (s *poolStatus) unclaimed() int {
+	return len(s.Ready) + len(s.Starting)
+}
+
+// poolStatuses summarises the members of each configured pool
+func poolStatuses(pools []config.Pool, clusters []*metadata.ClusterMetadata) []*poolStatus {
+	sorted := make([]*metadata.ClusterMetadata, len(clusters))
+	copy(sorted, clusters)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].CreatedAt.Before(sorted[j].CreatedAt) })
+
+	statuses := make([]*poolStatus, len(pools))
+	for i, p := range pools {
+		s := &poolStatus{Template: p.Template, Size: p.Size, Ready: []string{}, Starting: []string{}, Failed: []string{}}
+		for _, c := range sorted {
+			if c.Pool != p.Template {
+				continue
+			}
+			if !c.Unclaimed() {
+				s.Claimed++
+				continue
+			}
+			switch poolMemberState(c) {
+			case poolMemberReady:
+				s.Ready = append(s.Ready, c.Name)
+			case poolMemberFailed:
+				s.Failed = append(s.Failed, c.Name)
+			default:
+				s.Starting = append(s.Starting, c.Name)
+			}
+		}
+		statuses[i] = s
+	}
+	return statuses
+}
+
+func runPoolStatusCmd(cmd *cobra.Command, args []string) error {
+	if poolOutput != "table" && poolOutput != "json" {
+		return fmt.Errorf("unknown output format %q (expected table or json)", poolOutput)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	metaStore, err := metadata.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize metadata store: %w", err)
+	}
+	clusters, err := metaStore.List()
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+	statuses := poolStatuses(cfg.Pools, clusters)
+
+	if poolOutput == "json" {
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal pools to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No pools configured")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TEMPLATE\tSIZE\tREADY\tSTARTING\tFAILED\tCLAIMED")
+	for _, s := range statuses {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n",
+			s.Template, s.Size, len(s.Ready), len(s.Starting), len(s.Failed), s.Claimed)
+	}
+	return w.Flush()
+}
+
+func runPoolFillCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	interval, err := utils.ParseDuration(poolInterval)
+	if err != nil {
+		return fmt.Errorf("invalid --interval value: %w", err)
+	}
+
+	for {
+		created, err := fillPools(args, logger)
+		if !poolWatch {
+			if err == nil && created == 0 {
+				fmt.Println("Every pool is full")
+			}
+			return err
+		}
+		if err != nil {
+			logger.Warn("Failed to fill pools", "error", err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// fillPools makes one pass over the configured pools, or the pools of the
+// given templates, creating members until each has its size, and returns
+// how many it created
+func fillPools(templates []string, logger *telemetry.Logger) (int, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.NamespaceStrategy == config.NamespacePerOwner {
+		return 0, fmt.Errorf("warm pools are not used under namespaceStrategy %s, since a claimed member would stay in the pool's namespace", config.NamespacePerOwner)
+	}
+	pools := cfg.Pools
+	if len(templates) > 0 {
+		pools = nil
+		for _, t := range templates {
+			p := cfg.Pool(t)
+			if p == nil {
+				return 0, fmt.Errorf("template %q has no pool; add it to pools in the config", t)
+			}
+			pools = append(pools, *p)
+		}
+	}
+
+	metaStore, err := metadata.NewStore()
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize metadata store: %w", err)
+	}
+	clusters, err := metaStore.List()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	created := 0
+	var short []string
+	for _, s := range poolStatuses(pools, clusters) {
+		if len(s.Failed) > 0 {
+			logger.Warn("Pool has failed members; remove them with 'ghostctl down'", "template", s.Template, "members", s.Failed)
+		}
+		for i := s.unclaimed(); i < s.Size; i++ {
+			name, err := fillPool(cfg, metaStore, s.Template, logger)
+			if err != nil {
+				logger.Error("Failed to create pool member", "template", s.Template, "error", err)
+				short = append(short, s.Template)
+				break
+			}
+			created++
+			fmt.Printf("✓ Pool member '%s' of %s is ready\n", name, s.Template)
+		}
+	}
+	if len(short) > 0 {
+		return created, fmt.Errorf("could not fill the pools of %s", strings.Join(short, ", "))
+	}
+	return created, nil
+}
+
+// fillPool creates one member of a template's pool and returns its name
+func fillPool(cfg *config.Config, metaStore metadata.Store, template string, logger *telemetry.Logger) (string, error) {
+	name, err := poolMemberName(template)
+	if err != nil {
+		return "", err
+	}
+
+	catalog, _, err := templateCatalog()
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := catalog.Render(template, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// Members wait without a TTL; the claimer's TTL starts at the claim
+	opts := &cluster.CreateOptions{Name: name, Labels: make(map[string]string)}
+	applyTemplate(opts, tmpl)
+	opts.TTL = ""
+	applyGPUNodeLabels(cfg, opts, logger)
+
+	opts.Namespace, err = vcluster.NamespaceFor(cfg.NamespaceStrategy, cfg.Namespace, name, metadata.PoolOwner)
+	if err != nil {
+		return "", err
+	}
+	hourly, err := estimateHourly(cfg, opts)
+	if err != nil {
+		return "", err
+	}
+	if err := checkCapacity(opts, logger); err != nil {
+		var insufficient *capacity.InsufficientError
+		if errors.As(err, &insufficient) {
+			return "", fmt.Errorf("the host does not have room for another member: %w", err)
+		}
+		return "", err
+	}
+
+	logger.Info("Creating pool member", "template", template, "name", name)
+	req := &clusterRequest{
+		template: template,
+		opts:     opts,
+		creator:  identity.Identity{User: metadata.PoolOwner},
+		pool:     template,
+	}
+	return name, createCluster(cfg, metaStore, req, hourly)
+}
+
+// poolMemberName returns a new random name for a member of a template's pool
+func poolMemberName(template string) (string, error) {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate pool member name: %w", err)
+	}
+	name := "pool-" + template + "-" + hex.EncodeToString(suffix)
+	if err := utils.ValidateClusterName(name); err != nil {
+		return "", fmt.Errorf("template %q cannot name pool members: %w", template, err)
+	}
+	return name, nil
+}
+
+// usePool reports whether up may claim a member of the template's pool
+// rather than create a cluster. Template parameters may change anything,
+// so requests that set any are always created. Under per-owner a claimed
+// member would keep the pool's namespace, outside the owner's quota.
+func usePool(cfg *config.Config) bool {
+	return !upNoPool && upTemplate != "" && len(upSet) == 0 && cfg.Pool(upTemplate) != nil &&
+		cfg.NamespaceStrategy != config.NamespacePerOwner
+}
+
+// claimablePoolMembers returns the ready, unclaimed members of a template's
+// pool that were created with the options hashed to optionsHash, oldest
+// first. Members recorded without a hash, or created under per-owner and so
+// in the pool's own namespace, are never claimed.
+func claimablePoolMembers(clusters []*metadata.ClusterMetadata, template, optionsHash string) []*metadata.ClusterMetadata {
+	var members []*metadata.ClusterMetadata
+	for _, c := range clusters {
+		if c.Pool != template || !c.Unclaimed() || poolMemberState(c) != poolMemberReady {
+			continue
+		}
+		if c.OptionsHash == "" || c.OptionsHash != optionsHash || c.NamespaceStrategy == config.NamespacePerOwner {
+			continue
+		}
+		members = append(members, c)
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i].CreatedAt.Before(members[j].CreatedAt) })
+	return members
+}
+
+// claimPoolMember renames the first of members that nobody else claims
+// first to the requested name and hands it to the requester. It returns
+// nil if every member was claimed by someone else.
+func claimPoolMember(metaStore metadata.Store, req *clusterRequest, members []*metadata.ClusterMetadata) (*metadata.ClusterMetadata, error) {
+	logger := telemetry.GetLogger()
+	opts := req.opts
+	owner := req.creator.Name()
+
+	for _, member := range members {
+		now := time.Now()
+		claimed := *member
+		claimed.Name = opts.Name
+		claimed.HostName = member.VClusterName()
+		claimed.ClaimedAt = &now
+		claimed.TTL = opts.TTL
+		claimed.Labels = opts.Labels
+		claimed.Owner = owner
+		claimed.OwnerUser = req.creator.User
+		claimed.OwnerEmail = req.creator.Email
+		claimed.OwnerHost = req.creator.Hostname
+		claimed.OwnerCIActor = req.creator.CIActor
+		claimed.BudgetOverride = req.budgetOverride
+		claimed.Events = append(append([]metadata.Event(nil), member.Events...),
+			metadata.NewEvent(metadata.EventClaimed, owner, "from pool member "+member.Name))
+
+		// Rename fails if another claim took the member first
+		if err := metaStore.Rename(member.Name, &claimed); err != nil {
+			if metaStore.Exists(opts.Name) {
+				return nil, fmt.Errorf("cluster %q already exists", opts.Name)
+			}
+			logger.Debug("Pool member was claimed by someone else", "name", member.Name, "error", err)
+			continue
+		}
+
+		// The host-side resources keep the member's name; record the new
+		// owner on them
+		if err := vcluster.Label(claimed.HostName, claimed.Namespace, ownerLabels(req.creator), ownerAnnotations(req.creator)); err != nil {
+			logger.Warn("Failed to label vCluster resources with owner", "error", err)
+		}
+
+		logger.Info("✓ Claimed warm pool member", "name", opts.Name, "member", member.Name)
+		return &claimed, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+)
+
+// testPoolOptions are what the default pool's members are created with
+var testPoolOptions = &cluster.CreateOptions{CPU: "2", Memory: "4Gi"}
+
+func testOptionsHash(opts *cluster.CreateOptions) string {
+	hash, err := opts.OptionsHash()
+	if err != nil {
+		panic(err)
+	}
+	return hash
+}
+
+func testPoolClusters() []*metadata.ClusterMetadata {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	member := func(name string, minutes int, events ...string) *metadata.ClusterMetadata {
+		meta := &metadata.ClusterMetadata{
+			Name:        name,
+			Owner:       metadata.PoolOwner,
+			Pool:        "default",
+			CreatedAt:   start.Add(time.Duration(minutes) * time.Minute),
+			CPU:         "2",
+			Memory:      "4Gi",
+			OptionsHash: testOptionsHash(testPoolOptions),
+		}
+		for _, e := range events {
+			meta.Events = append(meta.Events, metadata.Event{Type: e})
+		}
+		return meta
+	}
+	claimedAt := start.Add(time.Hour)
+	claimed := member("mine", 0, metadata.EventCreated, metadata.EventReady, metadata.EventClaimed)
+	claimed.ClaimedAt = &claimedAt
+	resized := member("pool-default-4", 1, metadata.EventCreated, metadata.EventReady)
+	resized.CPU = "4"
+	resized.OptionsHash = testOptionsHash(&cluster.CreateOptions{CPU: "4", Memory: "4Gi"})
+	scheduled := member("pool-default-6", 6, metadata.EventCreated, metadata.EventReady)
+	scheduled.OptionsHash = testOptionsHash(&cluster.CreateOptions{CPU: "2", Memory: "4Gi", GPUNodeLabels: map[string]string{"gpu": "a100"}})
+	unhashed := member("pool-default-7", 7, metadata.EventCreated, metadata.EventReady)
+	unhashed.OptionsHash = ""
+	perOwner := member("pool-default-8", 8, metadata.EventCreated, metadata.EventReady)
+	perOwner.NamespaceStrategy = config.NamespacePerOwner
+
+	return []*metadata.ClusterMetadata{
+		member("pool-default-2", 3, metadata.EventCreated, metadata.EventReady),
+		member("pool-default-1", 2, metadata.EventCreated, metadata.EventReady),
+		member("pool-default-3", 4, metadata.EventCreated),
+		member("pool-default-5", 5, metadata.EventCreated, metadata.EventFailed),
+		resized,
+		scheduled,
+		unhashed,
+		perOwner,
+		claimed,
+		{Name: "other", Owner: "alice", CPU: "2", Memory: "4Gi"},
+	}
+}
+
+func TestPoolStatuses(t *testing.T) {
+	pools := []config.Pool{{Template: "default", Size: 4}, {Template: "gpu", Size: 1}}
+	statuses := poolStatuses(pools, testPoolClusters())
+
+	want := []*poolStatus{
+		{
+			Template: "default",
+			Size:     4,
+			Ready:    []string{"pool-default-4", "pool-default-1", "pool-default-2", "pool-default-6", "pool-default-7", "pool-default-8"},
+			Starting: []string{"pool-default-3"},
+			Failed:   []string{"pool-default-5"},
+			Claimed:  1,
+		},
+		{Template: "gpu", Size: 1, Ready: []string{}, Starting: []string{}, Failed: []string{}},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Fatalf("poolStatuses() = %+v, want %+v", statuses, want)
+	}
+	if got := statuses[0].unclaimed(); got != 7 {
+		t.Errorf("unclaimed() = %d, want 7", got)
+	}
+}
+
+func TestClaimablePoolMembers(t *testing.T) {
+	hash := testOptionsHash(testPoolOptions)
+	var names []string
+	for _, m := range claimablePoolMembers(testPoolClusters(), "default", hash) {
+		names = append(names, m.Name)
+	}
+	// Starting, failed and claimed members, members created with other
+	// options, members recorded without a hash and members in a per-owner
+	// pool namespace are not claimable
+	want := []string{"pool-default-1", "pool-default-2"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("claimablePoolMembers() = %v, want %v", names, want)
+	}
+
+	if got := claimablePoolMembers(testPoolClusters(), "gpu", hash); len(got) != 0 {
+		t.Errorf("claimablePoolMembers() of another pool = %d members, want none", len(got))
+	}
+}
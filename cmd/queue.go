@@ -0,0 +1,517 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/budget"
+	"github.com/ghostcluster-ai/ghostctl/internal/capacity"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/quota"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Manage clusters waiting for host capacity",
+	Long: `Manage clusters queued by 'ghostctl up --queue'.
+
+A cluster is queued instead of refused when the host has no room for it or
+a quota on clusters or GPUs in use is exceeded. 'ghostctl queue run'
+creates queued clusters as room frees up, in the order set by queue.order
+in $HOME/.ghost/config.yaml: fifo (default) or fair-share, which favours
+owners with the fewest active clusters. A request that does not fit on the
+host yet does not hold up smaller ones behind it until it has waited
+queue.reserveAfter (default 1h); from then on nothing behind it is created
+until it fits, so large requests are not starved.
+
+The queue is kept in the metadata store, so with a shared store one
+runner serves everyone. Keep 'ghostctl queue run --watch' running in the
+background, or run 'ghostctl queue run' from a CronJob.
+
+Examples:
+  ghostctl queue list                   # Show queued clusters and why they wait
+  ghostctl queue cancel ml-job          # Give up on a queued cluster
+  ghostctl queue run                    # Create what fits now, then exit
+  ghostctl queue run --watch            # Keep creating queued clusters every minute`,
+}
+
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List queued clusters",
+	Args:  cobra.NoArgs,
+	RunE:  runQueueListCmd,
+}
+
+var queueCancelCmd = &cobra.Command{
+	Use:   "cancel <cluster-name>...",
+	Short: "Remove clusters from the queue",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runQueueCancelCmd,
+}
+
+var queueRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Create queued clusters the host has room for",
+	Long: `Create the queued clusters that the host and quotas now have room for.
+
+Each queued cluster is checked again against the quotas, budgets and host
+capacity, which may have changed while it waited; the creation policy was
+checked when it was queued. A cluster over budget waits until spending
+falls, unless it was queued with --override-budget, and is dropped if it
+costs more than a whole budget. Clusters are created one at a time, each waiting until it is
+ready. With --watch the queue is checked every --interval until
+interrupted.
+
+A request left creating for over 30 minutes, e.g. by a runner that was
+stopped, is queued again, or dropped from the queue if its cluster exists.`,
+	Args: cobra.NoArgs,
+	RunE: runQueueRunCmd,
+}
+
+var (
+	queueOutput      string
+	queueIgnoreOwner bool
+	queueWatch       bool
+	queueInterval    string
+)
+
+func init() {
+	queueListCmd.Flags().StringVarP(&queueOutput, "output", "o", "table", "output format (table, json)")
+	queueCancelCmd.Flags().BoolVar(&queueIgnoreOwner, "ignore-owner", false, "cancel clusters queued by other users")
+	queueRunCmd.Flags().BoolVar(&queueWatch, "watch", false, "keep running, checking the queue every --interval")
+	queueRunCmd.Flags().StringVar(&queueInterval, "interval", "1m", "how often --watch checks the queue")
+	queueCmd.AddCommand(queueListCmd, queueCancelCmd, queueRunCmd)
+}
+
+func runQueueListCmd(cmd *cobra.Command, args []string) error {
+	if queueOutput != "table" && queueOutput != "json" {
+		return fmt.Errorf("unknown output format %q (expected table or json)", queueOutput)
+	}
+
+	metaStore, err := metadata.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize metadata store: %w", err)
+	}
+	queue, err := metaStore.ListQueue()
+	if err != nil {
+		return fmt.Errorf("failed to list queue: %w", err)
+	}
+
+	if queueOutput == "json" {
+		data, err := json.MarshalIndent(queue, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal queue to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(queue) == 0 {
+		fmt.Println("No clusters queued")
+		return nil
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tOWNER\tTEMPLATE\tRESOURCES\tPHASE\tWAITING\tREASON")
+	for _, q := range queue {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			q.Name, valueOrDash(q.Owner), valueOrDash(q.Template), queuedResources(q), q.Phase,
+			utils.FormatDuration(now.Sub(q.QueuedAt)), valueOrDash(q.Reason))
+	}
+	return w.Flush()
+}
+
+// queuedResources summarises what a queued cluster asks for
+func queuedResources(q *metadata.QueuedCluster) string {
+	if q.Options == nil {
+		return "-"
+	}
+	var parts []string
+	if q.Options.CPU != "" {
+		parts = append(parts, q.Options.CPU+" CPU")
+	}
+	if q.Options.Memory != "" {
+		parts = append(parts, q.Options.Memory)
+	}
+	if q.Options.GPU > 0 {
+		parts = append(parts, fmt.Sprintf("%d GPU %s", q.Options.GPU, q.Options.GPUType))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runQueueCancelCmd(cmd *cobra.Command, args []string) error {
+	metaStore, err := metadata.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize metadata store: %w", err)
+	}
+	queue, err := metaStore.ListQueue()
+	if err != nil {
+		return fmt.Errorf("failed to list queue: %w", err)
+	}
+
+	ids := currentIdentities(identity.Current())
+	for _, name := range args {
+		q := findQueued(queue, name)
+		if q == nil {
+			return fmt.Errorf("cluster %q is not queued", name)
+		}
+		if !queuedByAny(q, ids) && !queueIgnoreOwner {
+			return fmt.Errorf("cluster %q was queued by %s; pass --ignore-owner to cancel it", name, q.Owner)
+		}
+		if q.Phase == metadata.QueuePhaseCreating {
+			telemetry.GetLogger().Warn("Cluster is being created; cancelling does not stop it, use 'ghostctl down' once it exists", "name", name)
+		}
+		if err := metaStore.Dequeue(name); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Cluster '%s' removed from the queue\n", name)
+	}
+	return nil
+}
+
+// queuedByAny reports whether a queued cluster was queued by any of ids
+func queuedByAny(q *metadata.QueuedCluster, ids []string) bool {
+	owner := &metadata.ClusterMetadata{Owner: q.Owner, OwnerUser: q.OwnerUser, OwnerEmail: q.OwnerEmail, OwnerCIActor: q.OwnerCIActor}
+	return owner.OwnerName() == "" || ownedByAny(owner, ids)
+}
+
+func findQueued(queue []*metadata.QueuedCluster, name string) *metadata.QueuedCluster {
+	for _, q := range queue {
+		if q.Name == name {
+			return q
+		}
+	}
+	return nil
+}
+
+// isQueued reports whether a cluster of the given name is queued
+func isQueued(metaStore metadata.Store, name string) bool {
+	queue, err := metaStore.ListQueue()
+	return err == nil && findQueued(queue, name) != nil
+}
+
+// enqueueCluster queues a request that has to wait for room
+func enqueueCluster(metaStore metadata.Store, req *clusterRequest, reason error) error {
+	opts := req.opts
+	q := &metadata.QueuedCluster{
+		Name:              opts.Name,
+		Phase:             metadata.QueuePhaseQueued,
+		Reason:            reason.Error(),
+		Template:          req.template,
+		Options:           opts,
+		Owner:             req.creator.Name(),
+		OwnerUser:         req.creator.User,
+		OwnerEmail:        req.creator.Email,
+		OwnerHost:         req.creator.Hostname,
+		OwnerCIActor:      req.creator.CIActor,
+		BudgetOverride:    req.budgetOverride,
+		SkipCapacityCheck: req.skipCapacityCheck,
+	}
+	if err := metaStore.Enqueue(q); err != nil {
+		return fmt.Errorf("failed to queue cluster: %w", err)
+	}
+
+	telemetry.GetLogger().Debug("Cluster queued", "name", opts.Name, "reason", q.Reason)
+	fmt.Printf("Cluster '%s' is queued: %s\n", opts.Name, q.Reason)
+	fmt.Println("\nIt will be created by 'ghostctl queue run' once there is room.")
+	fmt.Println("\nUseful commands:")
+	fmt.Printf("  ghostctl queue list              # See the queue\n")
+	fmt.Printf("  ghostctl queue cancel %s         # Give up waiting\n", opts.Name)
+	return nil
+}
+
+// requestFromQueue rebuilds the request a queued cluster was created from
+func requestFromQueue(q *metadata.QueuedCluster) *clusterRequest {
+	return &clusterRequest{
+		template: q.Template,
+		opts:     q.Options,
+		creator: identity.Identity{
+			User:     q.OwnerUser,
+			Email:    q.OwnerEmail,
+			Hostname: q.OwnerHost,
+			CIActor:  q.OwnerCIActor,
+		},
+		budgetOverride:    q.BudgetOverride,
+		skipCapacityCheck: q.SkipCapacityCheck,
+		queuedAt:          q.QueuedAt,
+		queuedReason:      q.Reason,
+	}
+}
+
+func runQueueRunCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	interval, err := utils.ParseDuration(queueInterval)
+	if err != nil {
+		return fmt.Errorf("invalid --interval value: %w", err)
+	}
+
+	for {
+		created, err := runQueue(logger)
+		if !queueWatch {
+			if err == nil && created == 0 {
+				fmt.Println("No queued clusters could be created")
+			}
+			return err
+		}
+		if err != nil {
+			logger.Warn("Failed to process the queue", "error", err)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Queue timings
+const (
+	defaultQueueReserveAfter = time.Hour        // see config.Queue.ReserveAfter
+	staleCreatingAfter       = 30 * time.Minute // well past how long creating a cluster can take
+)
+
+// runQueue makes one pass over the queue, creating each queued cluster the
+// host and quotas now have room for, and returns how many it created
+func runQueue(logger *telemetry.Logger) (int, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load config: %w", err)
+	}
+	metaStore, err := metadata.NewStore()
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize metadata store: %w", err)
+	}
+	queue, err := metaStore.ListQueue()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list queue: %w", err)
+	}
+	clusters, err := metaStore.List()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	now := time.Now()
+	for _, q := range queue {
+		if staleCreating(q, now) {
+			retryStaleCreating(metaStore, q, logger)
+		}
+	}
+	reserveAfter := queueReserveAfter(cfg, logger)
+
+	// The first request that has waited too long for host room holds the
+	// queue: nothing behind it is created until it fits
+	var holding *metadata.QueuedCluster
+	created := 0
+	for _, q := range orderQueue(queue, cfg.Queue.Order, clusters) {
+		if metaStore.Exists(q.Name) {
+			logger.Warn("A cluster of the same name exists; dropping it from the queue", "name", q.Name)
+			if err := metaStore.Dequeue(q.Name); err != nil {
+				logger.Warn("Failed to dequeue cluster", "name", q.Name, "error", err)
+			}
+			continue
+		}
+		if q.Options == nil {
+			logger.Warn("Queued cluster has no options; dropping it from the queue", "name", q.Name)
+			if err := metaStore.Dequeue(q.Name); err != nil {
+				logger.Warn("Failed to dequeue cluster", "name", q.Name, "error", err)
+			}
+			continue
+		}
+
+		req := requestFromQueue(q)
+		owner := req.creator.Name()
+
+		// The namespace follows the strategy configured now
+		namespace, err := vcluster.NamespaceFor(cfg.NamespaceStrategy, cfg.Namespace, q.Name, owner)
+		if err != nil {
+			logger.Warn("Failed to resolve namespace", "name", q.Name, "error", err)
+			continue
+		}
+		req.opts.Namespace = namespace
+
+		hourly, err := estimateHourly(cfg, req.opts)
+		if err != nil {
+			logger.Warn("Failed to estimate cost", "name", q.Name, "error", err)
+			continue
+		}
+
+		wait, err := admitQueued(cfg, metaStore, req, hourly, logger)
+		if err != nil {
+			// The request can never pass, e.g. because the quotas changed
+			logger.Error("Queued cluster can no longer be created; dropping it from the queue", "name", q.Name, "error", err)
+			recordFailedCreate(metaStore, req.metadata(cfg, hourly), err)
+			if err := metaStore.Dequeue(q.Name); err != nil {
+				logger.Warn("Failed to dequeue cluster", "name", q.Name, "error", err)
+			}
+			continue
+		}
+		if wait == nil && holding != nil {
+			wait = fmt.Errorf("waiting for %s to fit first", holding.Name)
+		}
+		if wait != nil {
+			reason := wait.Error()
+			if holding == nil && holdsQueue(q, wait, now, reserveAfter) {
+				holding = q
+				reason += fmt.Sprintf(" (waited over %s; holding the queue until it fits)", utils.FormatDuration(reserveAfter))
+			}
+			logger.Debug("Queued cluster still has to wait", "name", q.Name, "reason", reason)
+			if _, err := metaStore.SetQueuePhase(q.Name, metadata.QueuePhaseQueued, metadata.QueuePhaseQueued, reason); err != nil {
+				logger.Warn("Failed to update queued cluster", "name", q.Name, "error", err)
+			}
+			continue
+		}
+
+		// Claim the request, so that another runner does not create it too
+		claimed, err := metaStore.SetQueuePhase(q.Name, metadata.QueuePhaseQueued, metadata.QueuePhaseCreating, q.Reason)
+		if err != nil || !claimed {
+			continue
+		}
+
+		logger.Info("Creating queued cluster", "name", q.Name, "owner", owner, "waited", utils.FormatDuration(time.Since(q.QueuedAt)))
+		err = createCluster(cfg, metaStore, req, hourly)
+		if dqErr := metaStore.Dequeue(q.Name); dqErr != nil {
+			logger.Warn("Failed to dequeue cluster", "name", q.Name, "error", dqErr)
+		}
+		if err != nil {
+			logger.Error("Failed to create queued cluster", "name", q.Name, "error", err)
+			continue
+		}
+		created++
+		fmt.Printf("✓ Cluster '%s' created for %s after %s in the queue\n", q.Name, owner, utils.FormatDuration(time.Since(q.QueuedAt)))
+	}
+	return created, nil
+}
+
+// queueReserveAfter returns how long a queued request waits for host room
+// before it holds the queue
+func queueReserveAfter(cfg *config.Config, logger *telemetry.Logger) time.Duration {
+	if cfg.Queue.ReserveAfter == "" {
+		return defaultQueueReserveAfter
+	}
+	reserveAfter, err := utils.ParseDuration(cfg.Queue.ReserveAfter)
+	if err != nil {
+		logger.Warn("Invalid queue reserveAfter; using the default", "reserveAfter", cfg.Queue.ReserveAfter, "default", utils.FormatDuration(defaultQueueReserveAfter), "error", err)
+		return defaultQueueReserveAfter
+	}
+	return reserveAfter
+}
+
+// holdsQueue reports whether a request that has to wait holds the queue:
+// it has waited reserveAfter for host room. Quota waits never hold the
+// queue, since other owners' requests do not use up the owner's quota.
+func holdsQueue(q *metadata.QueuedCluster, wait error, now time.Time, reserveAfter time.Duration) bool {
+	var insufficient *capacity.InsufficientError
+	return errors.As(wait, &insufficient) && now.Sub(q.QueuedAt) >= reserveAfter
+}
+
+// staleCreating reports whether a request has been creating for so long
+// that the runner creating it must have stopped
+func staleCreating(q *metadata.QueuedCluster, now time.Time) bool {
+	return q.Phase == metadata.QueuePhaseCreating && now.Sub(q.InPhaseSince()) >= staleCreatingAfter
+}
+
+// retryStaleCreating drops a stale creating request from the queue if its
+// cluster was created, and otherwise queues it again
+func retryStaleCreating(metaStore metadata.Store, q *metadata.QueuedCluster, logger *telemetry.Logger) {
+	if metaStore.Exists(q.Name) {
+		logger.Warn("Queued cluster was created but left in the queue; dropping it from the queue", "name", q.Name)
+		if err := metaStore.Dequeue(q.Name); err != nil {
+			logger.Warn("Failed to dequeue cluster", "name", q.Name, "error", err)
+		}
+		return
+	}
+	logger.Warn("Queued cluster was left creating; queueing it again", "name", q.Name, "since", q.InPhaseSince())
+	claimed, err := metaStore.SetQueuePhase(q.Name, metadata.QueuePhaseCreating, metadata.QueuePhaseQueued, "the runner creating it stopped; retrying")
+	if err != nil {
+		logger.Warn("Failed to update queued cluster", "name", q.Name, "error", err)
+		return
+	}
+	if claimed {
+		q.Phase = metadata.QueuePhaseQueued
+	}
+}
+
+// admitQueued checks a queued request against the quotas, budgets and host
+// capacity again, since they may have changed while it waited. wait is why
+// it still has to wait; err means it can never be created.
+func admitQueued(cfg *config.Config, metaStore metadata.Store, req *clusterRequest, hourly float64, logger *telemetry.Logger) (wait, err error) {
+	owner := req.creator.Name()
+	if err := checkQuotas(cfg, metaStore, owner, req.template, req.opts); err != nil {
+		var exceeded *quota.ExceededError
+		if errors.As(err, &exceeded) && exceeded.Temporary() {
+			return err, nil
+		}
+		return nil, err
+	}
+	// The override given when the cluster was queued still holds
+	if err := checkBudgets(cfg, metaStore, owner, req.opts, hourly); err != nil {
+		var exceeded *budget.ExceededError
+		switch {
+		case req.budgetOverride != "":
+			logger.Warn("Budget exceeded, creating anyway", "name", req.opts.Name, "reason", req.budgetOverride, "error", err)
+		case errors.As(err, &exceeded) && exceeded.Temporary():
+			return err, nil
+		default:
+			return nil, err
+		}
+	}
+	if !req.skipCapacityCheck {
+		if err := checkCapacity(req.opts, logger); err != nil {
+			var insufficient *capacity.InsufficientError
+			if errors.As(err, &insufficient) {
+				return err, nil
+			}
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// orderQueue returns the queued (not already claimed) requests in the
+// order they should be created. fifo takes the oldest first. fair-share
+// takes the request of the owner with the fewest active clusters, counting
+// the clusters created from the queue ahead of it, so owners take turns.
+// runQueue holds the queue for a request that has waited too long.
+func orderQueue(queue []*metadata.QueuedCluster, order string, active []*metadata.ClusterMetadata) []*metadata.QueuedCluster {
+	var pending []*metadata.QueuedCluster
+	for _, q := range queue {
+		if q.Phase == metadata.QueuePhaseQueued {
+			pending = append(pending, q)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].QueuedAt.Before(pending[j].QueuedAt) })
+	if order != config.QueueFairShare {
+		return pending
+	}
+
+	counts := make(map[string]int)
+	for _, c := range active {
+		counts[c.OwnerName()]++
+	}
+	ordered := make([]*metadata.QueuedCluster, 0, len(pending))
+	for len(pending) > 0 {
+		next := 0
+		for i, q := range pending {
+			if counts[q.Owner] < counts[pending[next].Owner] {
+				next = i
+			}
+		}
+		counts[pending[next].Owner]++
+		ordered = append(ordered, pending[next])
+		pending = append(pending[:next], pending[next+1:]...)
+	}
+	return ordered
+}
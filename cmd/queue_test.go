@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/capacity"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/quota"
+)
+
+func TestOrderQueue(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	queued := func(name, owner string, minutes int) *metadata.QueuedCluster {
+		return &metadata.QueuedCluster{Name: name, Owner: owner, Phase: metadata.QueuePhaseQueued, QueuedAt: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+	queue := []*metadata.QueuedCluster{
+		queued("alice-2", "alice", 2),
+		queued("alice-1", "alice", 1),
+		queued("alice-3", "alice", 3),
+		queued("bob-1", "bob", 4),
+		queued("carol-1", "carol", 5),
+		{Name: "claimed", Owner: "dave", Phase: metadata.QueuePhaseCreating},
+	}
+	// bob already runs a cluster
+	active := []*metadata.ClusterMetadata{{Name: "bob-0", Owner: "bob"}}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"", []string{"alice-1", "alice-2", "alice-3", "bob-1", "carol-1"}},
+		{config.QueueFIFO, []string{"alice-1", "alice-2", "alice-3", "bob-1", "carol-1"}},
+		// alice and carol have no clusters, then alice, bob and carol have one each
+		{config.QueueFairShare, []string{"alice-1", "carol-1", "alice-2", "bob-1", "alice-3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			got := orderQueue(queue, tt.order, active)
+			var names []string
+			for _, q := range got {
+				names = append(names, q.Name)
+			}
+			if len(names) != len(tt.want) {
+				t.Fatalf("orderQueue() = %v, want %v", names, tt.want)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					t.Fatalf("orderQueue() = %v, want %v", names, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestHoldsQueue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	noRoom := &capacity.InsufficientError{}
+	overQuota := &quota.ExceededError{}
+
+	tests := []struct {
+		name   string
+		waited time.Duration
+		wait   error
+		want   bool
+	}{
+		{"short wait for room", 30 * time.Minute, noRoom, false},
+		{"long wait for room", 2 * time.Hour, noRoom, true},
+		{"long wait for quota", 2 * time.Hour, overQuota, false},
+		{"wrapped wait for room", time.Hour, fmt.Errorf("host: %w", noRoom), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &metadata.QueuedCluster{Name: "big", QueuedAt: now.Add(-tt.waited)}
+			if got := holdsQueue(q, tt.wait, now, time.Hour); got != tt.want {
+				t.Errorf("holdsQueue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaleCreating(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recent, long := now.Add(-5*time.Minute), now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		q    *metadata.QueuedCluster
+		want bool
+	}{
+		{"queued long ago", &metadata.QueuedCluster{Phase: metadata.QueuePhaseQueued, QueuedAt: now.Add(-2 * time.Hour)}, false},
+		{"creating recently", &metadata.QueuedCluster{Phase: metadata.QueuePhaseCreating, QueuedAt: now.Add(-2 * time.Hour), PhaseSince: &recent}, false},
+		{"creating long ago", &metadata.QueuedCluster{Phase: metadata.QueuePhaseCreating, QueuedAt: now.Add(-2 * time.Hour), PhaseSince: &long}, true},
+		{"creating with no phase time", &metadata.QueuedCluster{Phase: metadata.QueuePhaseCreating, QueuedAt: now.Add(-time.Hour)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := staleCreating(tt.q, now); got != tt.want {
+				t.Errorf("staleCreating() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
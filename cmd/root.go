@@ -64,6 +64,15 @@ func init() {
 		disconnectCmd,
 		execCmd,
 		templatesCmd,
+		costCmd,
+		budgetCmd,
+		policyCmd,
+		auditCmd,
+		stateCmd,
+		historyCmd,
+		capacityCmd,
+		queueCmd,
+		poolCmd,
 	)
 }
 
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/spf13/cobra"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Manage the cluster metadata store",
+	Long: `Manage where ghostctl keeps cluster metadata.
+
+The store backend is selected by the store section of
+$HOME/.ghost/config.yaml:
+
+  file       $HOME/.ghost/clusters.json (default)
+  configmap  a ConfigMap in the host namespace, shared by everyone using the host
+  secret     like configmap, but stored in a Secret
+  sqlite     an SQLite database, for large deleted-cluster histories`,
+}
+
+var stateMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy cluster metadata to another store backend",
+	Long: `Copy all active and deleted cluster records and queued clusters from the
+configured store to another backend. The source store is left unchanged; update the store
+section of $HOME/.ghost/config.yaml afterwards to switch to the new backend.
+
+Examples:
+  ghostctl state migrate --to configmap                  # Share clusters with the team
+  ghostctl state migrate --to sqlite --path /data/ghost.db
+  ghostctl state migrate --to configmap --merge          # Add to an existing shared store`,
+	Args: cobra.NoArgs,
+	RunE: runStateMigrateCmd,
+}
+
+var (
+	stateMigrateTo        string
+	stateMigratePath      string
+	stateMigrateNamespace string
+	stateMigrateName      string
+	stateMigrateMerge     bool
+)
+
+func init() {
+	stateMigrateCmd.Flags().StringVar(&stateMigrateTo, "to", "", "target backend (file, configmap, secret, sqlite)")
+	stateMigrateCmd.Flags().StringVar(&stateMigratePath, "path", "", "target directory (file) or database file (sqlite)")
+	stateMigrateCmd.Flags().StringVar(&stateMigrateNamespace, "namespace", "", "target host namespace (configmap, secret)")
+	stateMigrateCmd.Flags().StringVar(&stateMigrateName, "name", "", "target object name (configmap, secret)")
+	stateMigrateCmd.Flags().BoolVar(&stateMigrateMerge, "merge", false, "add records to a target store that already has data")
+	_ = stateMigrateCmd.MarkFlagRequired("to")
+
+	stateCmd.AddCommand(stateMigrateCmd)
+}
+
+func runStateMigrateCmd(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	target := config.Store{
+		Backend:   stateMigrateTo,
+		Path:      stateMigratePath,
+		Namespace: stateMigrateNamespace,
+		Name:      stateMigrateName,
+	}
+	if target == cfg.Store || (target.Backend == metadata.BackendFile && cfg.Store == config.Store{}) {
+		return fmt.Errorf("the target store is the configured store")
+	}
+
+	source, err := metadata.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open source store: %w", err)
+	}
+	targetCfg := *cfg
+	targetCfg.Store = target
+	dest, err := metadata.Open(&targetCfg)
+	if err != nil {
+		return fmt.Errorf("failed to open target store: %w", err)
+	}
+
+	clusters, err := source.List()
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+	deleted, err := source.ListDeleted()
+	if err != nil {
+		return fmt.Errorf("failed to list deleted clusters: %w", err)
+	}
+	queue, err := source.ListQueue()
+	if err != nil {
+		return fmt.Errorf("failed to list queued clusters: %w", err)
+	}
+
+	if !stateMigrateMerge {
+		existing, err := dest.List()
+		if err != nil {
+			return fmt.Errorf("failed to read target store: %w", err)
+		}
+		existingDeleted, err := dest.ListDeleted()
+		if err != nil {
+			return fmt.Errorf("failed to read target store: %w", err)
+		}
+		existingQueue, err := dest.ListQueue()
+		if err != nil {
+			return fmt.Errorf("failed to read target store: %w", err)
+		}
+		if len(existing) > 0 || len(existingDeleted) > 0 || len(existingQueue) > 0 {
+			return fmt.Errorf("target store already has %d active, %d deleted and %d queued clusters; pass --merge to add to it",
+				len(existing), len(existingDeleted), len(existingQueue))
+		}
+	}
+
+	if err := dest.Import(clusters, deleted, queue); err != nil {
+		return fmt.Errorf("failed to write target store: %w", err)
+	}
+
+	fmt.Printf("✓ Copied %d active, %d deleted and %d queued clusters to the %s store\n",
+		len(clusters), len(deleted), len(queue), target.Backend)
+	fmt.Println("\nTo switch to it, set in $HOME/.ghost/config.yaml:")
+	fmt.Println("  store:")
+	fmt.Printf("    backend: %s\n", target.Backend)
+	if target.Path != "" {
+		fmt.Printf("    path: %s\n", target.Path)
+	}
+	if target.Namespace != "" {
+		fmt.Printf("    namespace: %s\n", target.Namespace)
+	}
+	if target.Name != "" {
+		fmt.Printf("    name: %s\n", target.Name)
+	}
+	return nil
+}
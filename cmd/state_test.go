@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+)
+
+// TestStateMigrateCopiesQueue tests that migrating a store keeps the
+// clusters queued in it
+func TestStateMigrateCopiesQueue(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	source, err := metadata.NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Add(&metadata.ClusterMetadata{Name: "running"}); err != nil {
+		t.Fatal(err)
+	}
+	queued := &metadata.QueuedCluster{Name: "ml-job", Phase: metadata.QueuePhaseQueued, Reason: "no room", Options: &cluster.CreateOptions{Name: "ml-job", GPU: 2}}
+	if err := source.Enqueue(queued); err != nil {
+		t.Fatal(err)
+	}
+
+	target := filepath.Join(t.TempDir(), metadata.SQLiteFileName)
+	stateMigrateTo, stateMigratePath = metadata.BackendSQLite, target
+	defer func() { stateMigrateTo, stateMigratePath = "", "" }()
+	captureStdout(t, func() {
+		if err := runStateMigrateCmd(stateMigrateCmd, nil); err != nil {
+			t.Fatalf("runStateMigrateCmd() error = %v", err)
+		}
+	})
+
+	dest, err := metadata.Open(&config.Config{Store: config.Store{Backend: metadata.BackendSQLite, Path: target}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dest.Exists("running") {
+		t.Error("active cluster was not copied")
+	}
+	queue, err := dest.ListQueue()
+	if err != nil || len(queue) != 1 {
+		t.Fatalf("ListQueue() of the target = %v, %v; want ml-job", queue, err)
+	}
+	if got := queue[0]; got.Name != "ml-job" || got.Reason != "no room" || !got.QueuedAt.Equal(queued.QueuedAt) || got.Options.GPU != 2 {
+		t.Errorf("queued cluster copied as %+v, want %+v", got, queued)
+	}
+}
@@ -6,8 +6,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
 	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
 	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
 	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
 	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
@@ -23,12 +27,19 @@ This displays whether the vCluster is running and accessible, creation time,
 and time-to-live information.
 
 Examples:
-  ghostctl status my-cluster        # Show cluster status
-  ghostctl status my-cluster -v     # Show detailed error information`,
+  ghostctl status my-cluster              # Show cluster status
+  ghostctl status my-cluster --detailed   # Also show host resource usage against limits
+  ghostctl status my-cluster -v           # Show detailed error information`,
 	Args: cobra.ExactArgs(1),
 	RunE: runStatusCmd,
 }
 
+var statusDetailed bool
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusDetailed, "detailed", false, "Show host resource usage against the cluster's limits")
+}
+
 func runStatusCmd(cmd *cobra.Command, args []string) error {
 	logger := telemetry.GetLogger()
 	clusterName := args[0]
@@ -38,11 +49,6 @@ func runStatusCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	namespace := cfg.Namespace
-	if namespace == "" {
-		namespace = vcluster.DefaultNamespace
-	}
-
 	logger.Info("Fetching cluster status", "name", clusterName)
 
 	baseDir := resolveGhostDir()
@@ -56,19 +62,20 @@ func runStatusCmd(cmd *cobra.Command, args []string) error {
 		meta, err = metaStore.Get(clusterName)
 		if err != nil {
 			logger.Info("Local metadata for cluster not found; using live vCluster status", "name", clusterName, "path", baseDir)
-		} else if meta.Namespace != "" {
-			namespace = meta.Namespace
+			meta = nil
 		}
 	}
+	namespace := hostNamespace(cfg, meta, clusterName)
+	hostName := hostClusterName(meta, clusterName)
 
-	ref := vcluster.ClusterRef{Name: clusterName, Namespace: namespace}
+	ref := vcluster.ClusterRef{Name: hostName, Namespace: namespace}
 
 	// Check if vCluster exists
 	exists := false
 	reachable := false
 	status := "not found"
 
-	if err := vcluster.Status(clusterName, namespace); err == nil {
+	if err := vcluster.Status(hostName, namespace); err == nil {
 		exists = true
 		status = "offline"
 	} else if strings.Contains(err.Error(), "vcluster CLI not found") {
@@ -99,21 +106,55 @@ func runStatusCmd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Display status
-	displayStatus(clusterName, meta, namespace, kubePath, status, exists, reachable)
+	displayStatus(clusterName, meta, namespace, kubePath, status, exists, reachable, cfg.Pricing)
+
+	if meta != nil && exists {
+		var policies []string
+		var policyErr error
+		if meta.Security.HostPolicies() {
+			policies, policyErr = vcluster.ListNetworkPolicies(hostName, namespace)
+		}
+		displayIsolation(meta.Security, policies, policyErr)
+	}
+
+	if statusDetailed {
+		strategy := cfg.NamespaceStrategy
+		if meta != nil {
+			strategy = meta.NamespaceStrategy
+		}
+		var usage []vcluster.QuotaUsage
+		var found bool
+		var usageErr error
+		if ownsNamespace(strategy) {
+			usage, found, usageErr = vcluster.GetQuotaUsage(namespace)
+		}
+		displayResourceLimits(namespace, strategy, usage, found, usageErr)
+	}
 
 	return nil
 }
 
-func displayStatus(name string, meta *metadata.ClusterMetadata, namespace, kubePath, status string, exists, reachable bool) {
+func displayStatus(name string, meta *metadata.ClusterMetadata, namespace, kubePath, status string, exists, reachable bool, pricing config.Pricing) {
 	fmt.Printf("Cluster: %s\n", name)
-	fmt.Printf("Namespace: %s\n", namespace)
+	if meta != nil && meta.NamespaceStrategy != "" {
+		fmt.Printf("Namespace: %s (%s)\n", namespace, meta.NamespaceStrategy)
+	} else {
+		fmt.Printf("Namespace: %s\n", namespace)
+	}
 	fmt.Printf("Status: %s\n", status)
 
 	if meta != nil {
 		fmt.Printf("Created: %s\n", meta.CreatedAt.Format("2006-01-02 15:04:05"))
+		if meta.ClaimedAt != nil {
+			fmt.Printf("Claimed: %s (from the %s pool as %s)\n", meta.ClaimedAt.Format("2006-01-02 15:04:05"), meta.Pool, meta.VClusterName())
+		}
 		if meta.TTL != "" {
 			fmt.Printf("TTL: %s\n", meta.TTL)
 		}
+		if hourly := cost.ClusterHourly(meta, pricing); hourly > 0 {
+			accrued := cost.Accrued(meta, pricing, time.Time{}, time.Now())
+			fmt.Printf("Cost: %s/hour (accrued %s)\n", cost.Format(hourly, pricing), cost.Format(accrued, pricing))
+		}
 	} else {
 		fmt.Printf("Created: unknown\n")
 		fmt.Printf("TTL: unknown\n")
@@ -139,8 +180,87 @@ func displayStatus(name string, meta *metadata.ClusterMetadata, namespace, kubeP
 	fmt.Printf("  ghostctl connect %s\n", name)
 }
 
+// displayIsolation shows the isolation a cluster was created with, and
+// whether the host network policies implementing it are in place
+func displayIsolation(sec *cluster.Security, policies []string, policyErr error) {
+	fmt.Printf("\nIsolation:\n")
+	lines := isolationSummary(sec)
+	if len(lines) == 0 {
+		fmt.Printf("  none (the cluster's pods can reach, and be reached by, other clusters on the host)\n")
+		return
+	}
+	for _, line := range lines {
+		fmt.Printf("  %s\n", line)
+	}
+
+	if !sec.HostPolicies() {
+		return
+	}
+	switch {
+	case policyErr != nil:
+		fmt.Printf("  ? host network policies could not be checked: %v\n", policyErr)
+	case len(policies) == 0:
+		fmt.Printf("  ✗ host network policies are missing; network isolation is not in effect\n")
+	default:
+		fmt.Printf("  ✓ host network policies: %s\n", strings.Join(policies, ", "))
+	}
+}
+
+// displayResourceLimits shows the host namespace's resource usage against
+// the quota ghostctl derived from its clusters' resources
+func displayResourceLimits(namespace, strategy string, usage []vcluster.QuotaUsage, found bool, usageErr error) {
+	fmt.Printf("\nResource limits (namespace %s):\n", namespace)
+	switch {
+	case !ownsNamespace(strategy):
+		fmt.Printf("  ✗ not enforced: the namespace is shared, so the cluster's resources do not bound it and it may use the whole host\n")
+		fmt.Printf("    set namespaceStrategy to per-cluster or per-owner to enforce them\n")
+		return
+	case usageErr != nil:
+		fmt.Printf("  ? resource quota could not be checked: %v\n", usageErr)
+		return
+	case !found:
+		fmt.Printf("  ✗ resource quota is missing; the cluster's resources are not enforced\n")
+		return
+	}
+	if strategy == config.NamespacePerOwner {
+		fmt.Printf("  Shared by all of the owner's clusters\n")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  RESOURCE\tUSED\tHARD")
+	for _, u := range usage {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", u.Resource, u.Used, u.Hard)
+	}
+	w.Flush()
+}
+
+// isolationSummary describes the effective isolation settings, one per line
+func isolationSummary(sec *cluster.Security) []string {
+	if sec == nil {
+		return nil
+	}
+	var lines []string
+	if level := sec.EffectivePodSecurity(); level != "" {
+		lines = append(lines, "Pod security: "+level)
+	}
+	if sec.Isolated {
+		lines = append(lines, "vCluster isolated mode: enabled")
+	}
+	if sec.NetworkIsolation {
+		lines = append(lines, "Network: isolated from other clusters")
+	}
+	if sec.DenyEgress() {
+		egress := "Egress: denied except to the cluster itself and DNS"
+		if len(sec.Egress.Allow) > 0 {
+			egress += ", " + strings.Join(sec.Egress.Allow, ", ")
+		}
+		lines = append(lines, egress)
+	}
+	return lines
+}
+
 func checkKubeconfigReachable(kubeconfigPath string) error {
-	cmd := exec.Command("kubectl", "--kubeconfig", kubeconfigPath, "get", "ns")
+	cmd := exec.Command("kubectl", "--kubeconfig", kubeconfigPath, "--request-timeout=10s", "get", "ns")
 	return cmd.Run()
 }
 
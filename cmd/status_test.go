@@ -8,12 +8,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
 	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
 )
 
 func TestDisplayStatusWithoutMetadata(t *testing.T) {
 	output := captureStdout(t, func() {
-		displayStatus("pr-789", nil, "ghostcluster", "/tmp/kubeconfig.yaml", "not found", false, false)
+		displayStatus("pr-789", nil, "ghostcluster", "/tmp/kubeconfig.yaml", "not found", false, false, config.Pricing{})
 	})
 
 	if !strings.Contains(output, "Created: unknown") {
@@ -29,14 +32,15 @@ func TestDisplayStatusWithoutMetadata(t *testing.T) {
 
 func TestDisplayStatusWithMetadata(t *testing.T) {
 	meta := &metadata.ClusterMetadata{
-		Name:      "pr-101",
-		Namespace: "ghostcluster",
-		CreatedAt: time.Date(2026, 2, 1, 10, 30, 0, 0, time.UTC),
-		TTL:       "1h",
+		Name:       "pr-101",
+		Namespace:  "ghostcluster",
+		CreatedAt:  time.Date(2026, 2, 1, 10, 30, 0, 0, time.UTC),
+		TTL:        "1h",
+		HourlyCost: 0.5,
 	}
 
 	output := captureStdout(t, func() {
-		displayStatus("pr-101", meta, "ghostcluster", "/tmp/kubeconfig.yaml", "running", true, true)
+		displayStatus("pr-101", meta, "ghostcluster", "/tmp/kubeconfig.yaml", "running", true, true, config.Pricing{})
 	})
 
 	if !strings.Contains(output, "Created: 2026-02-01 10:30:00") {
@@ -45,6 +49,9 @@ func TestDisplayStatusWithMetadata(t *testing.T) {
 	if !strings.Contains(output, "TTL: 1h") {
 		t.Fatalf("expected TTL in output, got: %s", output)
 	}
+	if !strings.Contains(output, "Cost: $0.50/hour") {
+		t.Fatalf("expected hourly cost in output, got: %s", output)
+	}
 	if !strings.Contains(output, "vCluster is accessible") {
 		t.Fatalf("expected accessible message, got: %s", output)
 	}
@@ -71,3 +78,51 @@ func captureStdout(t *testing.T, fn func()) string {
 
 	return buf.String()
 }
+
+func TestDisplayIsolation(t *testing.T) {
+	sec := &cluster.Security{
+		Isolated:         true,
+		NetworkIsolation: true,
+		Egress:           &cluster.Egress{DefaultDeny: true, Allow: []string{"10.0.0.0/8"}},
+	}
+
+	output := captureStdout(t, func() { displayIsolation(sec, []string{"ghostctl-pr-1-workloads"}, nil) })
+	for _, want := range []string{"Pod security: baseline", "isolated mode: enabled", "denied except", "10.0.0.0/8", "✓ host network policies"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	output = captureStdout(t, func() { displayIsolation(sec, nil, nil) })
+	if !strings.Contains(output, "network isolation is not in effect") {
+		t.Errorf("expected missing policies warning, got: %s", output)
+	}
+
+	output = captureStdout(t, func() { displayIsolation(nil, nil, nil) })
+	if !strings.Contains(output, "none") {
+		t.Errorf("expected no isolation, got: %s", output)
+	}
+}
+
+func TestDisplayResourceLimits(t *testing.T) {
+	usage := []vcluster.QuotaUsage{
+		{Resource: "requests.cpu", Used: "350m", Hard: "1"},
+		{Resource: "requests.memory", Used: "512Mi", Hard: "2Gi"},
+	}
+	output := captureStdout(t, func() { displayResourceLimits("ghostcluster-pr-1", "per-cluster", usage, true, nil) })
+	for _, want := range []string{"namespace ghostcluster-pr-1", "RESOURCE", "requests.cpu", "350m", "2Gi"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	output = captureStdout(t, func() { displayResourceLimits("ghostcluster-pr-1", "per-cluster", nil, false, nil) })
+	if !strings.Contains(output, "resource quota is missing") {
+		t.Errorf("expected missing quota warning, got: %s", output)
+	}
+
+	output = captureStdout(t, func() { displayResourceLimits("ghostcluster", "", nil, false, nil) })
+	if !strings.Contains(output, "not enforced: the namespace is shared") {
+		t.Errorf("expected shared namespace warning, got: %s", output)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
 	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
 	"github.com/ghostcluster-ai/ghostctl/internal/templates"
 	"github.com/spf13/cobra"
@@ -17,6 +20,7 @@ var (
 	templateFilter   string
 	templateFormat   string
 	templateExtended bool
+	templateResolved bool
 )
 
 var templatesCmd = &cobra.Command{
@@ -32,7 +36,8 @@ Examples:
   ghostctl templates gpu                # Show details for 'gpu' template
   ghostctl templates --filter gpu       # Filter templates by keyword
   ghostctl templates --format json      # Output as JSON
-  ghostctl templates gpu --extended     # Show full template details`,
+  ghostctl templates gpu --extended     # Show full template details
+  ghostctl templates gpu --resolved     # Show the merged template and where each value came from`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runTemplatesCmd,
 }
@@ -41,38 +46,81 @@ func init() {
 	templatesCmd.Flags().StringVar(&templateFilter, "filter", "", "Filter templates by name or feature")
 	templatesCmd.Flags().StringVar(&templateFormat, "format", "table", "Output format: table, json, yaml")
 	templatesCmd.Flags().BoolVar(&templateExtended, "extended", false, "Show extended template information")
+	templatesCmd.Flags().BoolVar(&templateResolved, "resolved", false, "Show the template with inheritance applied and the file each value came from")
 }
 
 func runTemplatesCmd(cmd *cobra.Command, args []string) error {
 	logger := telemetry.GetLogger()
 
-	// Get templates directory
-	templatesDir := templates.GetTemplatesDir()
-	store := templates.NewFileStore(templatesDir)
+	store, _, err := templateCatalog()
+	if err != nil {
+		return err
+	}
 
 	// If a specific template name is provided, show details
 	if len(args) == 1 {
+		if templateResolved {
+			return showResolvedTemplate(store, args[0], logger)
+		}
 		return showTemplateDetails(store, args[0], logger)
 	}
+	if templateResolved {
+		return fmt.Errorf("--resolved requires a template name")
+	}
 
 	// List all templates
 	return listTemplates(store, logger)
 }
 
-func listTemplates(store templates.Store, logger *telemetry.Logger) error {
+// templateCatalog returns the layers of the template search path followed
+// by the configured remote template sources; earlier layers win on name
+// clashes
+func templateCatalog() (*templates.Catalog, []templates.Remote, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	remotes, err := templates.OpenRemotes(cfg.TemplateSources, templates.DefaultCacheDir())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loaders := templates.Stores(templates.SearchPath(cfg.TemplatePath))
+	for _, r := range remotes {
+		loaders = append(loaders, r)
+	}
+	catalog := templates.NewCatalog(loaders...)
+	catalog.Warn = warnTemplateProblem
+	return catalog, remotes, nil
+}
+
+// warnTemplateProblem reports a template file that was skipped because it
+// could not be loaded or resolved
+func warnTemplateProblem(p templates.Problem) {
+	fmt.Fprintf(os.Stderr, "Warning: skipping %s (run 'ghostctl templates validate' for details)\n", p)
+}
+
+func listTemplates(store *templates.Catalog, logger *telemetry.Logger) error {
 	logger.Info("Listing templates")
 
-	templateList, err := store.List()
+	sources, err := store.Sources()
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			fmt.Println("No templates directory found.")
-			fmt.Println("\nTemplates are expected in one of these locations:")
-			fmt.Printf("  - %s\n", templates.GetTemplatesDir())
+			fmt.Println("\nTemplates are searched for in these locations, in order:")
+			for _, l := range store.Loaders {
+				fmt.Printf("  - %s\n", l)
+			}
 			fmt.Println("\nCreate template YAML files to get started.")
 			return nil
 		}
 		return fmt.Errorf("failed to list templates: %w", err)
 	}
+	templateList, problems := templates.ResolveAll(sources)
+	for _, p := range problems {
+		warnTemplateProblem(p)
+	}
 
 	// Apply filter if specified
 	if templateFilter != "" {
@@ -102,19 +150,29 @@ func listTemplates(store templates.Store, logger *telemetry.Logger) error {
 	case "yaml":
 		return outputYAML(templateList)
 	case "table":
-		return outputTable(templateList)
+		if err := outputTable(templateList, sourcesByName(sources)); err != nil {
+			return err
+		}
+		printShadowed(templateList, sourcesByName(sources))
+		return nil
 	default:
 		return fmt.Errorf("unsupported format: %s (supported: table, json, yaml)", templateFormat)
 	}
 }
 
-func showTemplateDetails(store templates.Store, name string, logger *telemetry.Logger) error {
+func showTemplateDetails(store *templates.Catalog, name string, logger *telemetry.Logger) error {
 	logger.Info("Showing template details", "name", name)
 
-	tmpl, err := store.Get(name)
+	sources, err := store.Sources()
+	if err != nil {
+		return err
+	}
+	res, err := templates.Resolve(sources, name)
 	if err != nil {
 		return err
 	}
+	tmpl := res.Template
+	src := sourcesByName(sources)[name]
 
 	// Output in requested format
 	switch templateFormat {
@@ -133,7 +191,12 @@ func showTemplateDetails(store templates.Store, name string, logger *telemetry.L
 	default:
 		// Default: human-readable details
 		fmt.Printf("Template: %s\n", tmpl.Name)
-		fmt.Printf("Description: %s\n\n", tmpl.Description)
+		fmt.Printf("Description: %s\n", tmpl.Description)
+		fmt.Printf("Source: %s (%s)\n", src.Layer, displayPath(src.File))
+		for _, shadowed := range src.Shadows {
+			fmt.Printf("Shadows: %s (%s)\n", shadowed.Layer, displayPath(shadowed.File))
+		}
+		fmt.Println()
 
 		fmt.Println("Resources:")
 		if tmpl.CPU != "" {
@@ -156,6 +219,17 @@ func showTemplateDetails(store templates.Store, name string, logger *telemetry.L
 			fmt.Printf("  TTL:     %s\n", tmpl.TTL)
 		}
 
+		if len(tmpl.Parameters) > 0 {
+			fmt.Println("\nParameters:")
+			for _, p := range tmpl.Parameters {
+				fmt.Printf("  %s (%s)", p.Name, describeParameter(p))
+				if p.Description != "" {
+					fmt.Printf(": %s", p.Description)
+				}
+				fmt.Println()
+			}
+		}
+
 		if len(tmpl.Labels) > 0 && templateExtended {
 			fmt.Println("\nLabels:")
 			for k, v := range tmpl.Labels {
@@ -163,8 +237,33 @@ func showTemplateDetails(store templates.Store, name string, logger *telemetry.L
 			}
 		}
 
+		if lines := isolationSummary(tmpl.Security); len(lines) > 0 {
+			fmt.Println("\nIsolation:")
+			for _, line := range lines {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+
+		if lines := schedulingSummary(tmpl.Scheduling, nil); len(lines) > 0 {
+			fmt.Println("\nScheduling:")
+			for _, line := range lines {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+
+		if len(tmpl.VClusterValues) > 0 || len(tmpl.ValuesFiles) > 0 {
+			fmt.Println("\nvCluster values:")
+			for _, f := range tmpl.ValuesFiles {
+				fmt.Printf("  file: %s\n", displayPath(f))
+			}
+			if len(tmpl.VClusterValues) > 0 {
+				fmt.Printf("  inline: %s\n", strings.Join(sortedKeys(tmpl.VClusterValues), ", "))
+			}
+			fmt.Printf("  Run 'ghostctl templates render %s' to see the final values\n", tmpl.Name)
+		}
+
 		fmt.Printf("\nUsage:\n")
-		fmt.Printf("  ghostctl up my-cluster --template %s\n", tmpl.Name)
+		fmt.Printf("  ghostctl up my-cluster --template %s%s\n", tmpl.Name, requiredSetFlags(tmpl.Parameters))
 		if tmpl.GPU > 0 {
 			fmt.Printf("  ghostctl up my-cluster --template %s --gpu 2  # Override GPU count\n", tmpl.Name)
 		}
@@ -173,15 +272,85 @@ func showTemplateDetails(store templates.Store, name string, logger *telemetry.L
 	return nil
 }
 
-func outputTable(templateList []templates.Template) error {
+// sortedKeys returns the top-level keys of values in order
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// describeParameter summarises a parameter's type and constraints
+func describeParameter(p templates.Parameter) string {
+	parts := []string{p.TypeName()}
+	if p.Required {
+		parts = append(parts, "required")
+	}
+	if p.Default != "" {
+		parts = append(parts, "default "+p.Default)
+	}
+	if len(p.Enum) > 0 {
+		parts = append(parts, "one of "+strings.Join(p.Enum, "|"))
+	}
+	if p.Regex != "" {
+		parts = append(parts, "matching "+p.Regex)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// requiredSetFlags returns the --set flags a template needs, for usage hints
+func requiredSetFlags(params []templates.Parameter) string {
+	var flags string
+	for _, p := range params {
+		if p.Required {
+			flags += fmt.Sprintf(" --set %s=<%s>", p.Name, p.Name)
+		}
+	}
+	return flags
+}
+
+func showResolvedTemplate(store *templates.Catalog, name string, logger *telemetry.Logger) error {
+	logger.Info("Resolving template", "name", name)
+
+	res, err := store.Resolve(name)
+	if err != nil {
+		return err
+	}
+
+	switch templateFormat {
+	case "json":
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"template": res.Values,
+			"chain":    res.Chain,
+			"origins":  res.Origins,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	default:
+		// YAML with each value annotated with its source file
+		data, err := res.AnnotatedYAML(displayPath)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+	}
+
+	return nil
+}
+
+func outputTable(templateList []templates.Template, sources map[string]*templates.Source) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	defer w.Flush()
 
 	// Header
 	if templateExtended {
-		fmt.Fprintln(w, "NAME\tDESCRIPTION\tCPU\tMEMORY\tSTORAGE\tGPU\tGPU TYPE\tTTL")
+		fmt.Fprintln(w, "NAME\tDESCRIPTION\tCPU\tMEMORY\tSTORAGE\tGPU\tGPU TYPE\tTTL\tLAYER")
 	} else {
-		fmt.Fprintln(w, "NAME\tDESCRIPTION\tCPU\tMEMORY\tGPU\tTTL")
+		fmt.Fprintln(w, "NAME\tDESCRIPTION\tCPU\tMEMORY\tGPU\tTTL\tLAYER")
 	}
 
 	// Rows
@@ -211,18 +380,68 @@ func outputTable(templateList []templates.Template) error {
 			ttl = "-"
 		}
 
+		layer := "-"
+		if src, ok := sources[tmpl.Name]; ok {
+			layer = src.Layer
+		}
+
 		if templateExtended {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
-				tmpl.Name, tmpl.Description, cpu, memory, storage, gpu, gpuType, ttl)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				tmpl.Name, tmpl.Description, cpu, memory, storage, gpu, gpuType, ttl, layer)
 		} else {
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
-				tmpl.Name, tmpl.Description, cpu, memory, gpu, ttl)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				tmpl.Name, tmpl.Description, cpu, memory, gpu, ttl, layer)
 		}
 	}
 
 	return nil
 }
 
+// sourcesByName indexes the winning definition of each template
+func sourcesByName(sources []*templates.Source) map[string]*templates.Source {
+	byName := make(map[string]*templates.Source, len(sources))
+	for _, src := range sources {
+		if _, ok := byName[src.Name]; !ok {
+			byName[src.Name] = src
+		}
+	}
+	return byName
+}
+
+// printShadowed lists the definitions hidden by templates in earlier layers
+func printShadowed(templateList []templates.Template, sources map[string]*templates.Source) {
+	var lines []string
+	for _, tmpl := range templateList {
+		src, ok := sources[tmpl.Name]
+		if !ok {
+			continue
+		}
+		for _, shadowed := range src.Shadows {
+			lines = append(lines, fmt.Sprintf("  %s: %s (%s) overrides %s (%s)",
+				tmpl.Name, src.Layer, displayPath(src.File), shadowed.Layer, displayPath(shadowed.File)))
+		}
+	}
+	if len(lines) > 0 {
+		fmt.Println("\nShadowed definitions:")
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+	}
+}
+
+// displayPath shortens a template file path for display
+func displayPath(path string) string {
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	if home := os.Getenv("HOME"); home != "" && strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + strings.TrimPrefix(path, home)
+	}
+	return path
+}
+
 func outputJSON(templateList []templates.Template) error {
 	data, err := json.MarshalIndent(templateList, "", "  ")
 	if err != nil {
@@ -0,0 +1,441 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/templates"
+	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createDescription string
+	createExtends     string
+	createLabels      []string
+	createCPU         string
+	createMemory      string
+	createStorage     string
+	createGPU         int
+	createGPUType     string
+	createTTL         string
+	createParams      []string
+	createFromCluster string
+	createInteractive bool
+	createForce       bool
+)
+
+var templatesCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a template in your templates directory",
+	Long: `Create a cluster template and save it as ~/.ghost/templates/<name>.yaml.
+
+Fields are taken from flags, from an existing cluster with --from-cluster,
+or asked for one by one with --interactive; flags override the settings
+captured from a cluster, and interactive answers default to both. The
+template is validated against the rest of the catalog before it is written.
+
+Parameters are given as name[:type][:required][=default], for example
+--param pr:int:required or --param size=small. Use 'ghostctl templates edit'
+for enums, patterns and descriptions.
+
+Examples:
+  ghostctl templates create ml --extends gpu --gpu 2 --ttl 8h --label team=ml
+  ghostctl templates create ml --interactive
+  ghostctl templates create nightly --from-cluster nightly-42
+  ghostctl templates create review --extends minimal --param pr:int:required --label pr='{{ .pr }}'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesCreateCmd,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesCreateCmd)
+
+	f := templatesCreateCmd.Flags()
+	f.StringVar(&createDescription, "description", "", "Template description")
+	f.StringVar(&createExtends, "extends", "", "Template to inherit from")
+	f.StringArrayVar(&createLabels, "label", nil, "Label as key=value (repeatable)")
+	f.StringVar(&createCPU, "cpu", "", "CPU allocation (e.g. 2, 500m)")
+	f.StringVar(&createMemory, "memory", "", "Memory allocation (e.g. 4Gi)")
+	f.StringVar(&createStorage, "storage", "", "Storage allocation (e.g. 20Gi)")
+	f.IntVar(&createGPU, "gpu", 0, "Number of GPUs")
+	f.StringVar(&createGPUType, "gpu-type", "", "GPU type (e.g. nvidia-t4)")
+	f.StringVar(&createTTL, "ttl", "", "Time-to-live (e.g. 1h, 30m)")
+	f.StringArrayVar(&createParams, "param", nil, "Parameter as name[:type][:required][=default] (repeatable)")
+	f.StringVar(&createFromCluster, "from-cluster", "", "Start from the settings of an existing cluster")
+	f.BoolVarP(&createInteractive, "interactive", "i", false, "Prompt for each field")
+	f.BoolVar(&createForce, "force", false, "Overwrite an existing template file")
+}
+
+// templateNamePattern restricts template names to safe file names
+var templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func runTemplatesCreateCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+	name := args[0]
+	if !templateNamePattern.MatchString(name) || name == "templates" {
+		return fmt.Errorf("invalid template name %q: use letters, digits, '.', '-' and '_'", name)
+	}
+	// Errors from here on are results, not usage errors
+	cmd.SilenceUsage = true
+
+	path := filepath.Join(templates.UserTemplatesDir(), name+".yaml")
+	if _, err := os.Stat(path); err == nil && !createForce {
+		return fmt.Errorf("template file %s already exists; use --force to replace it or 'ghostctl templates edit %s'", displayPath(path), name)
+	}
+
+	tmpl := &templates.Template{Name: name}
+	if createFromCluster != "" {
+		if err := captureCluster(createFromCluster, tmpl); err != nil {
+			return err
+		}
+	}
+	if err := applyCreateFlags(cmd, tmpl); err != nil {
+		return err
+	}
+	if createInteractive {
+		if err := promptTemplate(cmd, tmpl); err != nil {
+			return err
+		}
+	}
+
+	data, err := templates.Marshal(tmpl)
+	if err != nil {
+		return err
+	}
+
+	catalog, _, err := templateCatalog()
+	if err != nil {
+		return err
+	}
+	sources, err := loadTemplateSources(catalog)
+	if err != nil {
+		return err
+	}
+	if err := checkTemplateFile(cmd, sources, path, data); err != nil {
+		return err
+	}
+
+	logger.Info("Creating template", "name", name, "path", path)
+	if err := templates.Save(path, data); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Template '%s' saved to %s\n", name, displayPath(path))
+	warnIfShadowed(catalog, name, path)
+	return nil
+}
+
+// applyCreateFlags copies the fields given on the command line into tmpl
+func applyCreateFlags(cmd *cobra.Command, tmpl *templates.Template) error {
+	flags := cmd.Flags()
+	strs := []struct {
+		flag  string
+		value string
+		field *string
+	}{
+		{"description", createDescription, &tmpl.Description},
+		{"extends", createExtends, &tmpl.Extends},
+		{"cpu", createCPU, &tmpl.CPU},
+		{"memory", createMemory, &tmpl.Memory},
+		{"storage", createStorage, &tmpl.Storage},
+		{"gpu-type", createGPUType, &tmpl.GPUType},
+		{"ttl", createTTL, &tmpl.TTL},
+	}
+	for _, s := range strs {
+		if flags.Changed(s.flag) {
+			*s.field = s.value
+		}
+	}
+	if flags.Changed("gpu") {
+		tmpl.GPU = createGPU
+	}
+
+	if len(createLabels) > 0 {
+		labels, err := parseLabelFlags(createLabels)
+		if err != nil {
+			return err
+		}
+		if tmpl.Labels == nil {
+			tmpl.Labels = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			tmpl.Labels[k] = v
+		}
+	}
+
+	for _, value := range createParams {
+		param, err := parseParamFlag(value)
+		if err != nil {
+			return err
+		}
+		tmpl.Parameters = append(tmpl.Parameters, param)
+	}
+	return nil
+}
+
+// parseParamFlag parses a parameter given as name[:type][:required][=default]
+func parseParamFlag(value string) (templates.Parameter, error) {
+	spec, def, hasDefault := strings.Cut(value, "=")
+	parts := strings.Split(spec, ":")
+	param := templates.Parameter{Name: strings.TrimSpace(parts[0])}
+	if param.Name == "" {
+		return param, fmt.Errorf("invalid parameter %q: expected name[:type][:required][=default]", value)
+	}
+	for _, part := range parts[1:] {
+		switch part = strings.TrimSpace(part); part {
+		case "required":
+			param.Required = true
+		case templates.ParamString, templates.ParamInt, templates.ParamBool:
+			param.Type = part
+		default:
+			return param, fmt.Errorf("invalid parameter %q: unknown option %q (want string, int, bool or required)", value, part)
+		}
+	}
+	if param.Type == templates.ParamString {
+		param.Type = ""
+	}
+	if hasDefault {
+		param.Default = def
+	}
+	return param, nil
+}
+
+// captureCluster fills tmpl with the settings a cluster was created with,
+// including its isolation. Only clusters created by ghostctl have recorded
+// settings; the cluster must also still exist on the host cluster.
+// Scheduling constraints and vCluster values are not recorded, so they are
+// not captured.
+func captureCluster(name string, tmpl *templates.Template) error {
+	logger := telemetry.GetLogger()
+
+	metaStore, err := metadata.NewStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize metadata store: %w", err)
+	}
+	meta, err := metaStore.Get(name)
+	if err != nil {
+		return fmt.Errorf("no recorded settings for cluster %q (only clusters created with ghostctl up can be captured): %w", name, err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	namespace := hostNamespace(cfg, meta, name)
+	if err := vcluster.Status(meta.VClusterName(), namespace); err != nil {
+		if strings.Contains(err.Error(), "vCluster not found") {
+			return fmt.Errorf("cluster %q is not running in namespace %s", name, namespace)
+		}
+		logger.Warn("Could not check the cluster; using its recorded settings", "name", name, "error", err)
+	}
+
+	tmpl.Description = fmt.Sprintf("Captured from cluster %s", name)
+	if meta.Template != "" {
+		tmpl.Description += fmt.Sprintf(" (template %s)", meta.Template)
+	}
+	tmpl.CPU = meta.CPU
+	tmpl.Memory = meta.Memory
+	tmpl.Storage = meta.Storage
+	tmpl.GPU = meta.GPU
+	tmpl.GPUType = meta.GPUType
+	tmpl.TTL = meta.TTL
+	tmpl.Security = meta.Security
+	for k, v := range meta.Labels {
+		if k == metadata.OwnerLabel {
+			continue
+		}
+		if tmpl.Labels == nil {
+			tmpl.Labels = make(map[string]string)
+		}
+		tmpl.Labels[k] = v
+	}
+	return nil
+}
+
+// prompter asks for template fields on the command's input
+type prompter struct {
+	reader *bufio.Reader
+	eof    bool
+}
+
+// ask prints a prompt showing the current value and returns the answer,
+// or the current value when the answer is empty
+func (p *prompter) ask(label, current string) (string, error) {
+	if current != "" {
+		fmt.Printf("%s [%s]: ", label, current)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+	if p.eof {
+		fmt.Println()
+		return current, nil
+	}
+	response, err := p.reader.ReadString('\n')
+	if err != nil {
+		if err != io.EOF {
+			return "", err
+		}
+		p.eof = true
+	}
+	if response = strings.TrimSpace(response); response == "" {
+		return current, nil
+	}
+	if response == "-" {
+		return "", nil
+	}
+	return response, nil
+}
+
+// askValid asks until the answer passes check
+func (p *prompter) askValid(label, current string, check func(string) error) (string, error) {
+	for {
+		value, err := p.ask(label, current)
+		if err != nil || value == "" {
+			return value, err
+		}
+		if err := check(value); err != nil {
+			if p.eof {
+				return "", fmt.Errorf("%s: %w", strings.ToLower(label), err)
+			}
+			fmt.Printf("  %v\n", err)
+			continue
+		}
+		return value, nil
+	}
+}
+
+// promptTemplate asks for each template field, defaulting to the values
+// already in tmpl. Entering "-" clears a field.
+func promptTemplate(cmd *cobra.Command, tmpl *templates.Template) error {
+	p := &prompter{reader: bufio.NewReader(cmd.InOrStdin())}
+	fmt.Printf("Creating template '%s' (press Enter to keep a value, '-' to clear it)\n", tmpl.Name)
+
+	var err error
+	if tmpl.Description, err = p.ask("Description", tmpl.Description); err != nil {
+		return err
+	}
+	if tmpl.Extends, err = p.ask("Extends", tmpl.Extends); err != nil {
+		return err
+	}
+
+	fields := []struct {
+		label string
+		field string
+		value *string
+	}{
+		{"CPU", "cpu", &tmpl.CPU},
+		{"Memory", "memory", &tmpl.Memory},
+		{"Storage", "storage", &tmpl.Storage},
+		{"GPU type", "gpuType", &tmpl.GPUType},
+		{"TTL", "ttl", &tmpl.TTL},
+	}
+	for _, f := range fields {
+		field := f.field
+		check := func(v string) error { return templates.CheckField(field, v) }
+		if *f.value, err = p.askValid(f.label, *f.value, check); err != nil {
+			return err
+		}
+	}
+
+	gpu := ""
+	if tmpl.GPU > 0 {
+		gpu = strconv.Itoa(tmpl.GPU)
+	}
+	check := func(v string) error { return templates.CheckField("gpu", v) }
+	if gpu, err = p.askValid("GPUs", gpu, check); err != nil {
+		return err
+	}
+	tmpl.GPU, _ = strconv.Atoi(gpu)
+
+	labels, err := p.askValid("Labels (key=value, comma-separated)", formatLabels(tmpl.Labels), func(v string) error {
+		_, err := parseLabelFlags(strings.Split(v, ","))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	tmpl.Labels = nil
+	if labels != "" {
+		tmpl.Labels, _ = parseLabelFlags(strings.Split(labels, ","))
+	}
+
+	for {
+		spec, err := p.askValid("Add parameter as name[:type][:required][=default] (Enter to finish)", "", func(v string) error {
+			_, err := parseParamFlag(v)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if spec == "" {
+			return nil
+		}
+		param, _ := parseParamFlag(spec)
+		tmpl.Parameters = append(tmpl.Parameters, param)
+	}
+}
+
+// formatLabels renders labels as sorted, comma-separated key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// loadTemplateSources returns the catalog's templates for checking a new
+// or changed file. Problems in other files are ignored; a catalog with no
+// template directories is empty.
+func loadTemplateSources(catalog *templates.Catalog) ([]*templates.Source, error) {
+	sources, _, err := catalog.Load()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// checkTemplateFile validates the new contents of a template file and
+// prints its problems
+func checkTemplateFile(cmd *cobra.Command, sources []*templates.Source, path string, data []byte) error {
+	problems := templates.CheckFile(sources, path, data)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	printFileProblems(problems, path)
+	return fmt.Errorf("template is invalid: found %d problem(s)", len(problems))
+}
+
+// printFileProblems prints the problems of the file at path, showing the
+// path as displayPath does
+func printFileProblems(problems []templates.Problem, path string) {
+	for _, p := range problems {
+		fmt.Println(strings.Replace(p.String(), path, displayPath(path), 1))
+	}
+}
+
+// warnIfShadowed tells the user when a template saved at path is hidden
+// by a same-named template in an earlier search path layer
+func warnIfShadowed(catalog *templates.Catalog, name, path string) {
+	sources, err := loadTemplateSources(catalog)
+	if err != nil {
+		return
+	}
+	src := sourcesByName(sources)[name]
+	if src != nil && filepath.Clean(src.File) != filepath.Clean(path) {
+		fmt.Printf("Note: '%s' from %s (%s) takes precedence over this file\n", name, src.Layer, displayPath(src.File))
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+func TestParseParamFlag(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    templates.Parameter
+		wantErr bool
+	}{
+		{"pr", templates.Parameter{Name: "pr"}, false},
+		{"pr:int:required", templates.Parameter{Name: "pr", Type: "int", Required: true}, false},
+		{"size=small", templates.Parameter{Name: "size", Default: "small"}, false},
+		{"debug:bool=false", templates.Parameter{Name: "debug", Type: "bool", Default: "false"}, false},
+		{"url:string=a:b", templates.Parameter{Name: "url", Default: "a:b"}, false},
+		{":int", templates.Parameter{}, true},
+		{"pr:float", templates.Parameter{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseParamFlag(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseParamFlag(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseParamFlag(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestPromptTemplate(t *testing.T) {
+	tmpl := &templates.Template{Name: "ml", CPU: "2", TTL: "1h", Labels: map[string]string{"team": "ml"}}
+
+	// Keep the description and CPU, clear the TTL after an invalid answer,
+	// and add a parameter
+	answers := strings.Join([]string{
+		"", "gpu", "", "8Gi", "", "nvidia-a100", "soon", "-", "2", "team=ml,env=dev", "pr:int:required", "",
+	}, "\n") + "\n"
+	cmd := &cobra.Command{}
+	cmd.SetIn(strings.NewReader(answers))
+
+	if err := promptTemplate(cmd, tmpl); err != nil {
+		t.Fatalf("promptTemplate() error = %v", err)
+	}
+	want := &templates.Template{
+		Name: "ml", Extends: "gpu", CPU: "2", Memory: "8Gi", GPU: 2, GPUType: "nvidia-a100",
+		Labels:     map[string]string{"team": "ml", "env": "dev"},
+		Parameters: []templates.Parameter{{Name: "pr", Type: "int", Required: true}},
+	}
+	if !reflect.DeepEqual(tmpl, want) {
+		t.Errorf("promptTemplate() = %+v, want %+v", tmpl, want)
+	}
+
+	// Running out of input keeps the remaining values, but an invalid last
+	// answer is an error
+	cmd.SetIn(strings.NewReader("\n\n\n\n\n\nsoon"))
+	if err := promptTemplate(cmd, &templates.Template{Name: "x"}); err == nil {
+		t.Error("promptTemplate() with an invalid final answer should fail")
+	}
+}
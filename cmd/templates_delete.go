@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+var templatesDeleteForce bool
+
+var templatesDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a template",
+	Long: `Delete the file defining a template, or its entry in a templates.yaml file.
+
+Only the definition that ghostctl uses is deleted; a template of the same
+name in a later search path layer takes its place. Templates that other
+templates extend are not deleted unless --force is given. Templates from
+remote template sources cannot be deleted.
+
+Examples:
+  ghostctl templates delete ml
+  ghostctl templates delete ml --force   # Skip confirmation`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesDeleteCmd,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesDeleteCmd)
+	templatesDeleteCmd.Flags().BoolVar(&templatesDeleteForce, "force", false, "Delete without confirmation, even if other templates extend it")
+}
+
+func runTemplatesDeleteCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	// Errors are results, not usage errors
+	cmd.SilenceUsage = true
+
+	catalog, _, err := templateCatalog()
+	if err != nil {
+		return err
+	}
+	sources, err := loadTemplateSources(catalog)
+	if err != nil {
+		return err
+	}
+	src, err := writableTemplate(sources, args[0])
+	if err != nil {
+		return err
+	}
+
+	// A shadowed definition keeps the children resolvable
+	if dependents := templates.Dependents(sources, src.Name); len(dependents) > 0 && len(src.Shadows) == 0 && !templatesDeleteForce {
+		return fmt.Errorf("template %q is extended by %s; use --force to delete it anyway", src.Name, strings.Join(dependents, ", "))
+	}
+
+	if !templatesDeleteForce {
+		ok, err := confirm(cmd, fmt.Sprintf("Delete template '%s' from %s?", src.Name, displayPath(src.File)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Cancelled")
+			return nil
+		}
+	}
+
+	logger.Info("Deleting template", "name", src.Name, "path", src.File)
+	if err := templates.Remove(src); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Template '%s' deleted\n", src.Name)
+	if len(src.Shadows) > 0 {
+		next := src.Shadows[0]
+		fmt.Printf("'%s' now comes from %s (%s)\n", src.Name, next.Layer, displayPath(next.File))
+	}
+	return nil
+}
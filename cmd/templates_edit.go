@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+var templatesEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Edit a template in your editor",
+	Long: `Open the file defining a template in $VISUAL or $EDITOR (default vi).
+
+The file is edited as a copy and only written back once it is valid. If
+the edited file has problems they are listed and you can edit it again;
+otherwise the changes are discarded. Templates from remote template
+sources cannot be edited; override them with a template of the same name
+in ~/.ghost/templates instead.
+
+Examples:
+  ghostctl templates edit gpu
+  EDITOR="code --wait" ghostctl templates edit gpu`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesEditCmd,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesEditCmd)
+}
+
+func runTemplatesEditCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	// Errors are results, not usage errors
+	cmd.SilenceUsage = true
+
+	catalog, _, err := templateCatalog()
+	if err != nil {
+		return err
+	}
+	sources, err := loadTemplateSources(catalog)
+	if err != nil {
+		return err
+	}
+	src, err := writableTemplate(sources, args[0])
+	if err != nil {
+		return err
+	}
+
+	original, err := os.ReadFile(src.File)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	// Edit a copy with the same name so editors pick the right syntax
+	tmpDir, err := os.MkdirTemp("", "ghostctl-template-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpFile := filepath.Join(tmpDir, filepath.Base(src.File))
+	if err := os.WriteFile(tmpFile, original, 0600); err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	logger.Info("Editing template", "name", src.Name, "path", src.File)
+	for {
+		if err := runEditor(tmpFile); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(tmpFile)
+		if err != nil {
+			return fmt.Errorf("failed to read edited template: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			fmt.Println("No changes made.")
+			return nil
+		}
+
+		problems := templates.CheckFile(sources, src.File, edited)
+		if len(problems) == 0 {
+			if err := templates.Save(src.File, edited); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Template '%s' saved to %s\n", src.Name, displayPath(src.File))
+			return nil
+		}
+
+		printFileProblems(problems, src.File)
+		again, err := confirm(cmd, "Edit again?")
+		if err != nil || !again {
+			return fmt.Errorf("template is invalid: found %d problem(s); changes discarded", len(problems))
+		}
+	}
+}
+
+// writableTemplate returns the definition of a template that can be
+// changed in place, refusing templates from remote sources
+func writableTemplate(sources []*templates.Source, name string) (*templates.Source, error) {
+	src := sourcesByName(sources)[name]
+	if src == nil {
+		return nil, fmt.Errorf("template %q not found (run 'ghostctl templates' to list templates)", name)
+	}
+	if strings.HasPrefix(src.Layer, "source:") {
+		return nil, fmt.Errorf("template %q comes from remote template %s and cannot be changed locally; create a template of the same name in %s to override it",
+			name, src.Layer, displayPath(templates.UserTemplatesDir()))
+	}
+	return src, nil
+}
+
+// runEditor opens path in the user's editor and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	command, args, err := shell.ParseCommand(editor)
+	if err != nil {
+		return fmt.Errorf("invalid editor %q: %w", editor, err)
+	}
+	exitCode, err := shell.ExecuteCommandStreaming(command, append(args, path)...)
+	if err != nil {
+		return fmt.Errorf("failed to run editor %q: %w", editor, err)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("editor %q exited with code %d", editor, exitCode)
+	}
+	return nil
+}
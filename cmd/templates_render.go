@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/templates"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var renderSet []string
+
+var templatesRenderCmd = &cobra.Command{
+	Use:   "render <name>",
+	Short: "Print the vCluster values a template produces",
+	Long: `Print the vCluster chart values that 'ghostctl up --template <name>' passes
+to vcluster create.
+
+Values are merged in this order, later sources overriding earlier ones and
+nested mappings merged key by key (lists are replaced, not appended):
+
+  1. values ghostctl generates from the template's settings, such as
+     storage
+  2. the template's valuesFiles, parents' files first
+  3. the template's vclusterValues, with a child's values merged over its
+     parents'
+
+Examples:
+  ghostctl templates render gpu
+  ghostctl templates render pr --set pr=42 --set image=app:latest
+  ghostctl templates render gpu > values.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesRenderCmd,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesRenderCmd)
+	templatesRenderCmd.Flags().StringArrayVar(&renderSet, "set", nil, "Template parameter as name=value (repeatable)")
+}
+
+func runTemplatesRenderCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	store, _, err := templateCatalog()
+	if err != nil {
+		return err
+	}
+	set, err := templates.ParseSetFlags(renderSet)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Rendering template values", "name", args[0])
+	tmpl, err := store.Render(args[0], set)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	opts := &cluster.CreateOptions{Name: args[0]}
+	applyTemplate(opts, tmpl)
+	applyGPUNodeLabels(cfg, opts, logger)
+	values, err := opts.Values()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(values); err != nil {
+		return fmt.Errorf("failed to encode values: %w", err)
+	}
+	fmt.Print(buf.String())
+	return nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+var templatesUpdateCmd = &cobra.Command{
+	Use:   "update [source...]",
+	Short: "Refresh remote template sources",
+	Long: `Fetch the configured remote template sources again and replace their
+local cache.
+
+Sources are configured under templateSources in the config file. HTTP(S)
+sources serve an index listing each template file with its sha256 checksum;
+Git sources are fetched at a pinned ref. Files that do not match their
+checksum are rejected. Sources are also refreshed automatically once their
+cache is older than their refresh interval, and served from the cache when
+they cannot be reached.
+
+Examples:
+  ghostctl templates update              # Refresh every source
+  ghostctl templates update platform     # Refresh one source`,
+	RunE: runTemplatesUpdateCmd,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesUpdateCmd)
+}
+
+func runTemplatesUpdateCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	_, remotes, err := templateCatalog()
+	if err != nil {
+		return err
+	}
+	if len(remotes) == 0 {
+		fmt.Println("No remote template sources configured (see templateSources in the config file).")
+		return nil
+	}
+
+	selected, err := selectRemotes(remotes, args)
+	if err != nil {
+		return err
+	}
+
+	// A failed source is a result, not a usage error
+	cmd.SilenceUsage = true
+
+	failed := 0
+	for _, r := range selected {
+		logger.Info("Updating template source", "source", r.SourceName())
+		m, err := r.Update()
+		if err != nil {
+			failed++
+			logger.Error("Failed to update template source", "source", r.SourceName(), "error", err)
+			fmt.Printf("✗ %s: %v\n", r.SourceName(), err)
+			continue
+		}
+
+		fmt.Printf("✓ %s: %d file(s)", r.SourceName(), len(m.Files))
+		if m.Commit != "" {
+			fmt.Printf(" at %s (%s)", m.Ref, shortCommit(m.Commit))
+		}
+		fmt.Println()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d template source(s) failed to update", failed)
+	}
+	return nil
+}
+
+// selectRemotes returns the named sources, or all of them if none are named
+func selectRemotes(remotes []templates.Remote, names []string) ([]templates.Remote, error) {
+	if len(names) == 0 {
+		return remotes, nil
+	}
+
+	byName := make(map[string]templates.Remote, len(remotes))
+	for _, r := range remotes {
+		byName[r.SourceName()] = r
+	}
+	var selected []templates.Remote
+	for _, name := range names {
+		r, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown template source %q", name)
+		}
+		selected = append(selected, r)
+	}
+	return selected, nil
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/templates"
+	"github.com/spf13/cobra"
+)
+
+var templatesValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Check template files for errors",
+	Long: `Check cluster templates for errors.
+
+The path may be a templates directory or a single template file; it defaults
+to the templates ghostctl uses, including the cache of remote template
+sources. Each problem is reported with its file and line:
+
+  - YAML syntax errors and unknown fields
+  - CPU, memory and storage units, GPU types and TTL syntax
+  - architectures, tolerations and affinity kinds
+  - malformed parameters and expressions using undeclared parameters
+  - vclusterValues that are not a mapping and missing values files
+  - template names defined more than once
+  - extends chains that are cyclic or name an unknown template
+
+Examples:
+  ghostctl templates validate
+  ghostctl templates validate ./templates
+  ghostctl templates validate ./templates/gpu.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTemplatesValidateCmd,
+}
+
+func init() {
+	templatesCmd.AddCommand(templatesValidateCmd)
+}
+
+func runTemplatesValidateCmd(cmd *cobra.Command, args []string) error {
+	logger := telemetry.GetLogger()
+
+	var loader interface {
+		Load() ([]*templates.Source, []templates.Problem, error)
+	}
+	if len(args) == 1 {
+		info, err := os.Stat(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read templates: %w", err)
+		}
+		if info.IsDir() {
+			loader = templates.NewFileStore(args[0])
+		} else {
+			loader = fileLoader(args[0])
+		}
+	} else {
+		catalog, _, err := templateCatalog()
+		if err != nil {
+			return err
+		}
+		loader = catalog
+	}
+	path := fmt.Sprint(loader)
+	logger.Info("Validating templates", "path", path)
+
+	sources, problems, err := loader.Load()
+	if err != nil {
+		return err
+	}
+	problems = append(problems, templates.Validate(sources)...)
+
+	// Problems are results, not usage errors
+	cmd.SilenceUsage = true
+
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problem(s) in %s", len(problems), path)
+	}
+
+	fmt.Printf("✓ %d template(s) valid\n", len(sources))
+	return nil
+}
+
+// fileLoader loads the templates of a single file
+type fileLoader string
+
+func (f fileLoader) Load() ([]*templates.Source, []templates.Problem, error) {
+	sources, problems := templates.LoadFile(string(f))
+	return sources, problems, nil
+}
@@ -1,15 +1,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/ghostcluster-ai/ghostctl/internal/budget"
+	"github.com/ghostcluster-ai/ghostctl/internal/capacity"
 	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
+	"github.com/ghostcluster-ai/ghostctl/internal/identity"
 	"github.com/ghostcluster-ai/ghostctl/internal/kubeconfig"
 	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/internal/policy"
+	"github.com/ghostcluster-ai/ghostctl/internal/quota"
 	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
 	"github.com/ghostcluster-ai/ghostctl/internal/templates"
 	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -30,6 +40,10 @@ Examples:
   ghostctl up ml-job --template gpu              # Use GPU template
   ghostctl up ml-job --template gpu --gpu 2      # Override GPU count
   ghostctl up test --template minimal --ttl 30m  # Minimal resources, 30m TTL
+  ghostctl up ml-job --template gpu --label team=ml  # Add labels (used by budgets)
+  ghostctl up pr-42 --template pr --set pr=42 --set image=abc123  # Fill in template parameters
+  ghostctl up ml-job --template gpu --queue      # Wait in the queue if no GPU is free
+  ghostctl up pr-42 --no-pool                    # Create a new cluster even if a pool member is ready
   ghostctl connect my-cluster                    # Connect to the cluster`,
 	RunE: runUpCmd,
 }
@@ -42,16 +56,37 @@ var (
 	upStorage  string
 	upGPU      int
 	upGPUType  string
+	upYes      bool
+	upLabels   []string
+	upSet      []string
+
+	upOverrideBudget    string
+	upSkipCapacityCheck bool
+	upQueue             bool
+	upNoPool            bool
 )
 
 func init() {
-	upCmd.Flags().StringVar(&upTTL, "ttl", "", "Time-to-live for the cluster (e.g., 30m, 2h, 1d)")
-	upCmd.Flags().StringVar(&upTemplate, "template", "default", "Template to use (default, gpu, minimal, large)")
-	upCmd.Flags().StringVar(&upCPU, "cpu", "", "CPU allocation (overrides template)")
-	upCmd.Flags().StringVar(&upMemory, "memory", "", "Memory allocation (overrides template)")
-	upCmd.Flags().StringVar(&upStorage, "storage", "", "Storage allocation (overrides template)")
-	upCmd.Flags().IntVar(&upGPU, "gpu", 0, "Number of GPUs (overrides template)")
-	upCmd.Flags().StringVar(&upGPUType, "gpu-type", "", "GPU type (overrides template)")
+	addClusterSpecFlags(upCmd)
+	upCmd.Flags().BoolVarP(&upYes, "yes", "y", false, "Create without confirming the estimated cost")
+	upCmd.Flags().StringVar(&upOverrideBudget, "override-budget", "", "Create even if a budget would be exceeded; the reason is recorded")
+	upCmd.Flags().BoolVar(&upSkipCapacityCheck, "skip-capacity-check", false, "Create without checking that the host has room (e.g. when nodes are autoscaled)")
+	upCmd.Flags().BoolVar(&upQueue, "queue", false, "Queue the cluster if the host or a quota has no room for it yet")
+	upCmd.Flags().BoolVar(&upNoPool, "no-pool", false, "Create a new cluster instead of claiming one from the template's warm pool")
+}
+
+// addClusterSpecFlags registers the flags that describe a cluster request.
+// They are shared by commands that resolve a request the way up does.
+func addClusterSpecFlags(c *cobra.Command) {
+	c.Flags().StringVar(&upTTL, "ttl", "", "Time-to-live for the cluster (e.g., 30m, 2h, 1d)")
+	c.Flags().StringVar(&upTemplate, "template", "default", "Template to use (default, gpu, minimal, large)")
+	c.Flags().StringVar(&upCPU, "cpu", "", "CPU allocation (overrides template)")
+	c.Flags().StringVar(&upMemory, "memory", "", "Memory allocation (overrides template)")
+	c.Flags().StringVar(&upStorage, "storage", "", "Storage allocation (overrides template)")
+	c.Flags().IntVar(&upGPU, "gpu", 0, "Number of GPUs (overrides template)")
+	c.Flags().StringVar(&upGPUType, "gpu-type", "", "GPU type (overrides template)")
+	c.Flags().StringArrayVar(&upLabels, "label", nil, "Label to add to the cluster as key=value (repeatable)")
+	c.Flags().StringArrayVar(&upSet, "set", nil, "Template parameter as name=value (repeatable)")
 }
 
 func runUpCmd(cmd *cobra.Command, args []string) error {
@@ -63,6 +98,12 @@ func runUpCmd(cmd *cobra.Command, args []string) error {
 	}
 	clusterName := args[0]
 
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Error("Failed to load config", "error", err)
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
 	// Initialize metadata store
 	metaStore, err := metadata.NewStore()
 	if err != nil {
@@ -74,6 +115,9 @@ func runUpCmd(cmd *cobra.Command, args []string) error {
 	if metaStore.Exists(clusterName) {
 		return fmt.Errorf("cluster %q already exists", clusterName)
 	}
+	if isQueued(metaStore, clusterName) {
+		return fmt.Errorf("cluster %q is already queued; see 'ghostctl queue list'", clusterName)
+	}
 
 	// Load template and build create options
 	opts, err := buildCreateOptions(cmd, clusterName, logger)
@@ -81,32 +125,299 @@ func runUpCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	applyGPUNodeLabels(cfg, opts, logger)
+
+	// Build the chart values now so a broken values file fails fast
+	if _, err := opts.Values(); err != nil {
+		return err
+	}
+
+	// Estimate cost and confirm before creating anything
+	hourly, err := estimateHourly(cfg, opts)
+	if err != nil {
+		return err
+	}
+
+	// Evaluate the creation policy
+	creator := identity.Current()
+	owner := creator.Name()
+	namespace, err := vcluster.NamespaceFor(cfg.NamespaceStrategy, cfg.Namespace, clusterName, owner)
+	if err != nil {
+		return err
+	}
+	opts.Namespace = namespace
+	if err := checkPolicy(cfg, policy.OperationUp, owner, opts); err != nil {
+		logger.Error("Cluster request denied by policy", "error", err)
+		return err
+	}
+
+	// Enforce quotas before anything is created. With --queue, a request
+	// that only has to wait for other clusters to go is queued instead.
+	var waitReason error
+	if err := checkQuotas(cfg, metaStore, owner, upTemplate, opts); err != nil {
+		var exceeded *quota.ExceededError
+		if !upQueue || !errors.As(err, &exceeded) || !exceeded.Temporary() {
+			logger.Error("Quota exceeded", "error", err)
+			return err
+		}
+		waitReason = err
+	}
+
+	// Enforce budgets against the projected cost over the TTL
+	if err := checkBudgets(cfg, metaStore, owner, opts, hourly); err != nil {
+		if upOverrideBudget == "" {
+			return fmt.Errorf("%w\n\nUse --override-budget \"<reason>\" to create the cluster anyway", err)
+		}
+		logger.Warn("Budget exceeded, creating anyway", "reason", upOverrideBudget, "error", err)
+		fmt.Printf("Warning: %v\nProceeding with budget override: %s\n", err, upOverrideBudget)
+	}
+
+	// A ready member of the template's warm pool is claimed instead of
+	// creating a cluster, so the host needs no room for a new one
+	var members []*metadata.ClusterMetadata
+	if waitReason == nil && usePool(cfg) {
+		clusters, err := metaStore.List()
+		if err != nil {
+			return fmt.Errorf("failed to list clusters: %w", err)
+		}
+		optionsHash, err := opts.OptionsHash()
+		if err != nil {
+			return err
+		}
+		members = claimablePoolMembers(clusters, upTemplate, optionsHash)
+	}
+
+	// Fail fast if the host cannot fit the cluster, rather than waiting
+	// for a control plane that stays Pending
+	if waitReason == nil && len(members) == 0 && !upSkipCapacityCheck {
+		if err := checkCapacity(opts, logger); err != nil {
+			var insufficient *capacity.InsufficientError
+			if !upQueue || !errors.As(err, &insufficient) {
+				cmd.SilenceUsage = true
+				return explainCapacity(opts.Name, err)
+			}
+			waitReason = err
+		}
+	}
+
+	if cfg.Pricing.Configured() {
+		displayCostEstimate(hourly, opts.TTL, cfg.Pricing)
+		if !upYes && isInteractive(cmd) {
+			prompt := "Create cluster?"
+			if waitReason != nil {
+				prompt = "Queue cluster?"
+			}
+			ok, err := confirm(cmd, prompt)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Cancelled")
+				return nil
+			}
+		}
+	}
+
+	req := &clusterRequest{
+		template:          upTemplate,
+		opts:              opts,
+		creator:           creator,
+		budgetOverride:    upOverrideBudget,
+		skipCapacityCheck: upSkipCapacityCheck,
+	}
+	if waitReason != nil {
+		return enqueueCluster(metaStore, req, waitReason)
+	}
+
+	if len(members) > 0 {
+		claimed, err := claimPoolMember(metaStore, req, members)
+		if err != nil {
+			return err
+		}
+		if claimed != nil {
+			displayCreationSummary(clusterName, opts)
+			return nil
+		}
+
+		// Other users claimed every ready member first
+		logger.Info("No pool member left to claim; creating a new cluster", "template", upTemplate)
+		if !upSkipCapacityCheck {
+			if err := checkCapacity(opts, logger); err != nil {
+				cmd.SilenceUsage = true
+				return explainCapacity(opts.Name, err)
+			}
+		}
+	}
+
+	if err := createCluster(cfg, metaStore, req, hourly); err != nil {
+		return err
+	}
+
+	// Display creation summary
+	displayCreationSummary(clusterName, opts)
+
+	return nil
+}
+
+// clusterRequest is a resolved request to create a cluster that passed
+// the creation policy
+type clusterRequest struct {
+	template          string
+	opts              *cluster.CreateOptions
+	creator           identity.Identity
+	budgetOverride    string
+	skipCapacityCheck bool
+	pool              string // template of the warm pool the cluster is created for
+
+	// queuedAt and queuedReason are set for requests that waited in the queue
+	queuedAt     time.Time
+	queuedReason string
+}
+
+// metadata returns the metadata a cluster created from the request is
+// recorded with
+func (req *clusterRequest) metadata(cfg *config.Config, hourly float64) *metadata.ClusterMetadata {
+	opts := req.opts
+	kubePath, _ := metadata.GetClusterPath(opts.Name)
+	optionsHash, _ := opts.OptionsHash()
+	return &metadata.ClusterMetadata{
+		Name:              opts.Name,
+		Namespace:         opts.Namespace,
+		NamespaceStrategy: cfg.NamespaceStrategy,
+		CreatedAt:         time.Now(),
+		TTL:               opts.TTL,
+		KubeconfigPath:    kubePath,
+		HostCluster:       "current",
+		Template:          req.template,
+		CPU:               opts.CPU,
+		Memory:            opts.Memory,
+		Storage:           opts.Storage,
+		GPU:               opts.GPU,
+		GPUType:           opts.GPUType,
+		Labels:            opts.Labels,
+		Security:          opts.Security,
+		OptionsHash:       optionsHash,
+		Owner:             req.creator.Name(),
+		OwnerUser:         req.creator.User,
+		OwnerEmail:        req.creator.Email,
+		OwnerHost:         req.creator.Hostname,
+		OwnerCIActor:      req.creator.CIActor,
+		HourlyCost:        hourly,
+		BudgetOverride:    req.budgetOverride,
+		Pool:              req.pool,
+	}
+}
+
+// createCluster creates the vCluster of a request and records it: its
+// namespace and the host resources bounding it first, then the vCluster,
+// waiting until it is ready
+func createCluster(cfg *config.Config, metaStore metadata.Store, req *clusterRequest, hourly float64) error {
+	logger := telemetry.GetLogger()
+	opts := req.opts
+	clusterName := opts.Name
+	namespace := opts.Namespace
+	owner := req.creator.Name()
+
+	values, err := opts.Values()
+	if err != nil {
+		return err
+	}
+
 	logger.Info("Creating new vCluster",
 		"name", clusterName,
-		"template", upTemplate,
+		"template", req.template,
 		"ttl", opts.TTL,
 		"cpu", opts.CPU,
 		"memory", opts.Memory,
 		"gpu", opts.GPU,
 	)
 
-	namespace := vcluster.DefaultNamespace
+	meta := req.metadata(cfg, hourly)
+
+	// Give the cluster its own namespace under the per-cluster and
+	// per-owner strategies
+	if ownsNamespace(cfg.NamespaceStrategy) {
+		logger.Info("Creating namespace", "namespace", namespace)
+		if err := vcluster.EnsureNamespace(namespace, namespaceLabels(cfg.NamespaceStrategy, clusterName, owner)); err != nil {
+			logger.Error("Failed to create namespace", "error", err)
+			recordFailedCreate(metaStore, meta, err)
+			return err
+		}
+	}
+
+	// Bound what the cluster may use on the host before any pods start
+	if ownsNamespace(cfg.NamespaceStrategy) {
+		logger.Info("Applying resource limits", "namespace", namespace)
+		if err := applyResourceLimits(metaStore, meta); err != nil {
+			logger.Error("Failed to apply resource limits", "error", err)
+			releaseNamespace(metaStore, meta)
+			recordFailedCreate(metaStore, meta, err)
+			return err
+		}
+	} else if opts.CPU != "" || opts.Memory != "" || opts.Storage != "" || opts.GPU > 0 {
+		// A quota on the shared namespace would bound every cluster in it
+		fmt.Printf("Warning: cluster '%s' is in the shared namespace %s, so its cpu, memory, storage and gpu are not enforced and it may use the whole host; set namespaceStrategy to per-cluster or per-owner to enforce them\n",
+			clusterName, namespace)
+	}
+
+	// Isolate the cluster's pods before any of them start
+	if opts.Security.HostPolicies() {
+		logger.Info("Applying network policies", "name", clusterName)
+		if err := vcluster.ApplyNetworkPolicies(clusterName, namespace, opts.Security); err != nil {
+			logger.Error("Failed to isolate vCluster", "error", err)
+			releaseNamespace(metaStore, meta)
+			recordFailedCreate(metaStore, meta, err)
+			return err
+		}
+	}
 
 	// Create the vCluster
 	logger.Info("Creating vCluster in Kubernetes")
-	if err := vcluster.Create(clusterName, namespace); err != nil {
+	createStart := time.Now()
+	if err := vcluster.Create(clusterName, namespace, values); err != nil {
 		logger.Error("Failed to create vCluster", "error", err)
+		if opts.Security.HostPolicies() {
+			if err := vcluster.DeleteNetworkPolicies(clusterName, namespace); err != nil {
+				logger.Warn("Failed to remove network policies", "error", err)
+			}
+		}
+		releaseNamespace(metaStore, meta)
+		recordFailedCreate(metaStore, meta, err)
 		return err
 	}
 
+	// Record the cluster as soon as it exists on the host, so that a
+	// cluster that never becomes ready can still be found and deleted
+	meta.Events = []metadata.Event{metadata.NewEvent(metadata.EventCreated, owner, "")}
+	if !req.queuedAt.IsZero() {
+		queued := metadata.Event{Type: metadata.EventQueued, Time: req.queuedAt, User: owner, Message: req.queuedReason}
+		meta.Events[0].Message = "from the queue after " + utils.FormatDuration(createStart.Sub(req.queuedAt))
+		meta.Events = append([]metadata.Event{queued}, meta.Events...)
+	}
+	if err := metaStore.Add(meta); err != nil {
+		logger.Error("Failed to store cluster metadata", "error", err)
+		return fmt.Errorf("failed to store cluster metadata: %w", err)
+	}
+
 	// Wait for vCluster to be ready
 	logger.Info("Waiting for vCluster to be ready")
 	waitTimeout := 5 * time.Minute
 	if err := vcluster.IsReady(clusterName, namespace, waitTimeout); err != nil {
 		logger.Error("vCluster failed to become ready", "error", err)
+		recordEvent(metaStore, clusterName, metadata.NewEvent(metadata.EventFailed, owner, err.Error()))
 		return err
 	}
 
+	ready := metadata.NewEvent(metadata.EventReady, owner, "")
+	ready.DurationMs = time.Since(createStart).Milliseconds()
+	recordEvent(metaStore, clusterName, ready)
+
+	// Record the owner on the host-side resources so it is visible to
+	// anyone sharing the host
+	if err := vcluster.Label(clusterName, namespace, ownerLabels(req.creator), ownerAnnotations(req.creator)); err != nil {
+		logger.Warn("Failed to label vCluster resources with owner", "error", err)
+	}
+
 	// Retrieve and store kubeconfig
 	logger.Info("Retrieving kubeconfig")
 	kubeconfigPath, err := kubeconfig.NewManager()
@@ -121,35 +432,25 @@ func runUpCmd(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Store metadata
-	kubePath, _ := metadata.GetClusterPath(clusterName)
-	meta := &metadata.ClusterMetadata{
-		Name:           clusterName,
-		Namespace:      namespace,
-		CreatedAt:      time.Now(),
-		TTL:            opts.TTL,
-		KubeconfigPath: kubePath,
-		HostCluster:    "current",
-		Template:       upTemplate,
-		CPU:            opts.CPU,
-		Memory:         opts.Memory,
-		GPU:            opts.GPU,
-		Labels:         opts.Labels,
-	}
-
-	if err := metaStore.Add(meta); err != nil {
-		logger.Error("Failed to store cluster metadata", "error", err)
-		return fmt.Errorf("failed to store cluster metadata: %w", err)
-	}
-
 	logger.Info("✓ vCluster created successfully", "name", clusterName)
-	
-	// Display creation summary
-	displayCreationSummary(clusterName, opts)
-
 	return nil
 }
 
+// estimateHourly estimates the hourly cost of a cluster
+func estimateHourly(cfg *config.Config, opts *cluster.CreateOptions) (float64, error) {
+	estimate, err := cost.Hourly(cost.Resources{
+		CPU:     opts.CPU,
+		Memory:  opts.Memory,
+		Storage: opts.Storage,
+		GPU:     opts.GPU,
+		GPUType: opts.GPUType,
+	}, cfg.Pricing)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate cost: %w", err)
+	}
+	return estimate.Total, nil
+}
+
 // buildCreateOptions loads template and applies CLI flag overrides
 func buildCreateOptions(cmd *cobra.Command, clusterName string, logger *telemetry.Logger) (*cluster.CreateOptions, error) {
 	opts := &cluster.CreateOptions{
@@ -158,33 +459,40 @@ func buildCreateOptions(cmd *cobra.Command, clusterName string, logger *telemetr
 		Labels:    make(map[string]string),
 	}
 
+	if len(upSet) > 0 && upTemplate == "" {
+		return nil, fmt.Errorf("--set requires a template")
+	}
+
 	// Load template if specified
 	if upTemplate != "" {
-		templatesDir := templates.GetTemplatesDir()
-		store := templates.NewFileStore(templatesDir)
+		store, _, err := templateCatalog()
+		if err != nil {
+			return nil, err
+		}
 
-		tmpl, err := store.Get(upTemplate)
+		set, err := templates.ParseSetFlags(upSet)
 		if err != nil {
-			// Template not found - show helpful message
-			logger.Warn("Template not found, using defaults", "template", upTemplate)
-			fmt.Printf("Warning: Template %q not found. Using default values.\n", upTemplate)
-			fmt.Println("Run 'ghostctl templates' to see available templates.")
-		} else {
-			// Apply template defaults
-			logger.Info("Loaded template", "name", tmpl.Name)
-			opts.CPU = tmpl.CPU
-			opts.Memory = tmpl.Memory
-			opts.Storage = tmpl.Storage
-			opts.GPU = tmpl.GPU
-			opts.GPUType = tmpl.GPUType
-			opts.TTL = tmpl.TTL
-			if tmpl.Labels != nil {
-				opts.Labels = tmpl.Labels
-			}
+			return nil, err
+		}
+		tmpl, err := store.Render(upTemplate, set)
+		if err != nil {
+			logger.Error("Failed to load template", "template", upTemplate, "error", err)
+			return nil, err
 		}
+
+		// Apply template defaults
+		logger.Info("Loaded template", "name", tmpl.Name)
+		applyTemplate(opts, tmpl)
 	}
 
 	// Apply CLI flag overrides (flags take precedence over template)
+	if len(upLabels) > 0 {
+		labels, err := parseLabelFlags(upLabels)
+		if err != nil {
+			return nil, err
+		}
+		opts.Labels = utils.MergeStringMaps(opts.Labels, labels)
+	}
 	if cmd.Flags().Changed("cpu") {
 		opts.CPU = upCPU
 	}
@@ -207,6 +515,192 @@ func buildCreateOptions(cmd *cobra.Command, clusterName string, logger *telemetr
 	return opts, nil
 }
 
+// applyTemplate copies a rendered template's settings into opts
+func applyTemplate(opts *cluster.CreateOptions, tmpl *templates.Template) {
+	opts.CPU = tmpl.CPU
+	opts.Memory = tmpl.Memory
+	opts.Storage = tmpl.Storage
+	opts.GPU = tmpl.GPU
+	opts.GPUType = tmpl.GPUType
+	opts.TTL = tmpl.TTL
+	if tmpl.Labels != nil {
+		opts.Labels = tmpl.Labels
+	}
+	opts.Security = tmpl.Security
+	opts.Scheduling = tmpl.Scheduling
+	opts.VClusterValues = tmpl.VClusterValues
+	opts.ValuesFiles = tmpl.ValuesFiles
+}
+
+// checkPolicy evaluates the configured policy against a resolved request
+func checkPolicy(cfg *config.Config, operation, user string, opts *cluster.CreateOptions) error {
+	p, err := policy.Load(cfg.PolicyFile)
+	if err != nil {
+		return err
+	}
+
+	return p.Evaluate(policy.Request{
+		Operation: operation,
+		Template:  upTemplate,
+		User:      user,
+		CI:        identity.InCI(),
+		Options:   opts,
+	}).Err()
+}
+
+// checkQuotas verifies the new cluster fits the configured quotas
+func checkQuotas(cfg *config.Config, metaStore metadata.Store, owner, template string, opts *cluster.CreateOptions) error {
+	clusters, err := metaStore.List()
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+
+	req := quota.Request{
+		Owner:    owner,
+		Team:     opts.Labels[cfg.Quotas.TeamLabelKey()],
+		Template: template,
+		TTL:      opts.TTL,
+		GPU:      opts.GPU,
+	}
+	return quota.Check(cfg.Quotas, req, clusters)
+}
+
+// checkBudgets verifies the new cluster's projected cost fits the configured budgets
+func checkBudgets(cfg *config.Config, metaStore metadata.Store, owner string, opts *cluster.CreateOptions, hourly float64) error {
+	if len(cfg.Budgets) == 0 {
+		return nil
+	}
+
+	clusters, err := metaStore.List()
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %w", err)
+	}
+	deleted, err := metaStore.ListDeleted()
+	if err != nil {
+		return fmt.Errorf("failed to list deleted clusters: %w", err)
+	}
+
+	req := budget.Request{
+		Owner:  owner,
+		Labels: opts.Labels,
+		Hourly: hourly,
+		TTL:    opts.TTL,
+	}
+	return budget.Check(cfg.Budgets, req, append(clusters, deleted...), cfg.Pricing, time.Now())
+}
+
+// checkCapacity verifies the host has enough free CPU, memory and GPUs for
+// the cluster, returning a *capacity.InsufficientError if not. A host that
+// cannot be inspected, e.g. because the user may not list nodes, is not an
+// error.
+func checkCapacity(opts *cluster.CreateOptions, logger *telemetry.Logger) error {
+	nodes, err := capacity.ListNodes()
+	if err != nil {
+		logger.Warn("Could not check host capacity; creating anyway", "error", err)
+		return nil
+	}
+
+	return capacity.Check(capacity.Request{
+		CPU:           opts.CPU,
+		Memory:        opts.Memory,
+		GPU:           opts.GPU,
+		GPUType:       opts.GPUType,
+		Scheduling:    opts.Scheduling,
+		GPUNodeLabels: opts.GPUNodeLabels,
+	}, nodes)
+}
+
+// explainCapacity turns a capacity shortage into an error listing each
+// shortfall and what to do about it
+func explainCapacity(name string, err error) error {
+	var insufficient *capacity.InsufficientError
+	if !errors.As(err, &insufficient) {
+		return err
+	}
+	lines := make([]string, len(insufficient.Shortages))
+	for i, s := range insufficient.Shortages {
+		lines[i] = "  " + s.String()
+	}
+	return fmt.Errorf("the host does not have room for cluster %q:\n%s\n\nRun 'ghostctl capacity' to see free capacity, use --queue to create it once there is room, or use --skip-capacity-check if nodes are added on demand",
+		name, strings.Join(lines, "\n"))
+}
+
+// recordFailedCreate keeps a cluster that could not be created in the
+// deleted-cluster history, so 'ghostctl history' can explain what happened
+func recordFailedCreate(metaStore metadata.Store, meta *metadata.ClusterMetadata, cause error) {
+	failed := metadata.NewEvent(metadata.EventFailed, meta.Owner, cause.Error())
+	meta.CreatedAt = failed.Time
+	meta.DeletedAt = &failed.Time
+	meta.Events = []metadata.Event{failed}
+	if err := metaStore.Import(nil, []*metadata.ClusterMetadata{meta}, nil); err != nil {
+		telemetry.GetLogger().Warn("Failed to record cluster history", "name", meta.Name, "error", err)
+	}
+}
+
+// recordEvent appends an event to a cluster's history. History is best
+// effort and never fails the command.
+func recordEvent(metaStore metadata.Store, name string, event metadata.Event) {
+	if err := metaStore.RecordEvent(name, event); err != nil {
+		telemetry.GetLogger().Warn("Failed to record cluster history", "name", name, "event", event.Type, "error", err)
+	}
+}
+
+// parseLabelFlags parses key=value label flags
+func parseLabelFlags(values []string) (map[string]string, error) {
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", value)
+		}
+		labels[key] = strings.TrimSpace(val)
+	}
+	return labels, nil
+}
+
+// Host-side label and annotation keys recording who created a vCluster
+const (
+	ownerLabelKey           = "ghostctl.io/owner"
+	ownerAnnotationKey      = "ghostctl.io/owner"
+	ownerEmailAnnotationKey = "ghostctl.io/owner-email"
+	ownerHostAnnotationKey  = "ghostctl.io/owner-host"
+	ciActorAnnotationKey    = "ghostctl.io/ci-actor"
+)
+
+// ownerLabels returns the host-side labels identifying the creator
+func ownerLabels(id identity.Identity) map[string]string {
+	value := vcluster.SanitizeLabelValue(id.Name())
+	if value == "" {
+		return nil
+	}
+	return map[string]string{ownerLabelKey: value}
+}
+
+// ownerAnnotations returns the host-side annotations describing the creator
+func ownerAnnotations(id identity.Identity) map[string]string {
+	annotations := map[string]string{ownerAnnotationKey: id.Name()}
+	if id.Email != "" {
+		annotations[ownerEmailAnnotationKey] = id.Email
+	}
+	if id.Hostname != "" {
+		annotations[ownerHostAnnotationKey] = id.Hostname
+	}
+	if id.CIActor != "" {
+		annotations[ciActorAnnotationKey] = id.CIActor
+	}
+	return annotations
+}
+
+// displayCostEstimate shows the hourly cost of a cluster and its cost over the TTL
+func displayCostEstimate(hourly float64, ttl string, pricing config.Pricing) {
+	fmt.Printf("Estimated cost: %s/hour", cost.Format(hourly, pricing))
+	if projected := cost.Projected(hourly, ttl); projected > 0 {
+		fmt.Printf(" (%s over %s TTL)", cost.Format(projected, pricing), ttl)
+	}
+	fmt.Println()
+}
+
 // displayCreationSummary shows a summary of the created cluster
 func displayCreationSummary(clusterName string, opts *cluster.CreateOptions) {
 	fmt.Printf("\n✓ Cluster '%s' is ready!\n", clusterName)
@@ -235,6 +729,18 @@ func displayCreationSummary(clusterName string, opts *cluster.CreateOptions) {
 	if opts.TTL != "" {
 		fmt.Printf("  TTL:     %s\n", opts.TTL)
 	}
+	if lines := isolationSummary(opts.Security); len(lines) > 0 {
+		fmt.Println("\nIsolation:")
+		for _, line := range lines {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	if lines := schedulingSummary(opts.Scheduling, opts.GPUNodeLabels); len(lines) > 0 {
+		fmt.Println("\nScheduling:")
+		for _, line := range lines {
+			fmt.Printf("  %s\n", line)
+		}
+	}
 
 	fmt.Println("\nUseful commands:")
 	fmt.Printf("  ghostctl status %s               # Check cluster status\n", clusterName)
@@ -243,3 +749,46 @@ func displayCreationSummary(clusterName string, opts *cluster.CreateOptions) {
 	fmt.Printf("  ghostctl disconnect              # Return to parent cluster\n")
 	fmt.Printf("  ghostctl down %s                 # Destroy cluster\n", clusterName)
 }
+
+// applyGPUNodeLabels looks up the node labels of the cluster's GPU type in
+// the configured gpuNodeLabels table
+func applyGPUNodeLabels(cfg *config.Config, opts *cluster.CreateOptions, logger *telemetry.Logger) {
+	if opts.GPU == 0 || opts.GPUType == "" {
+		return
+	}
+	labels, ok := cfg.GPUNodeLabels[opts.GPUType]
+	if !ok {
+		logger.Warn("GPU type has no node labels in gpuNodeLabels; pods may run on any node", "gpuType", opts.GPUType)
+		return
+	}
+	opts.GPUNodeLabels = labels
+}
+
+// schedulingSummary describes where a cluster's pods may run, one
+// constraint per line
+func schedulingSummary(s cluster.Scheduling, gpuLabels map[string]string) []string {
+	var lines []string
+	if s.Architecture != "" {
+		lines = append(lines, "Architecture: "+s.Architecture)
+	}
+	if len(s.NodeSelector) > 0 {
+		lines = append(lines, "Node selector: "+formatLabels(s.NodeSelector))
+	}
+	if len(gpuLabels) > 0 {
+		lines = append(lines, "GPU nodes: "+formatLabels(gpuLabels))
+	}
+	if len(s.Tolerations) > 0 {
+		tolerations := make([]string, len(s.Tolerations))
+		for i, t := range s.Tolerations {
+			tolerations[i] = t.String()
+		}
+		lines = append(lines, "Tolerations: "+strings.Join(tolerations, ", "))
+	}
+	if len(s.Affinity) > 0 {
+		lines = append(lines, "Affinity: "+strings.Join(sortedKeys(s.Affinity), ", ")+" (control plane)")
+	}
+	if s.PriorityClassName != "" {
+		lines = append(lines, "Priority class: "+s.PriorityClassName+" (control plane)")
+	}
+	return lines
+}
@@ -6,7 +6,7 @@ set -e
 echo "=== Cleanup: Removing all ghostctl clusters ==="
 
 # Get list of all clusters
-CLUSTERS=$(ghostctl list --output table | tail -n +2 | awk '{print $1}')
+CLUSTERS=$(ghostctl list --no-probe -o name)
 
 if [ -z "$CLUSTERS" ]; then
     echo "No clusters found"
@@ -28,7 +28,7 @@ fi
 while IFS= read -r cluster; do
     if [ -n "$cluster" ]; then
         echo "Deleting cluster: $cluster"
-        ghostctl down "$cluster" --force
+        ghostctl down "$cluster" --force --ignore-owner
     fi
 done <<< "$CLUSTERS"
 
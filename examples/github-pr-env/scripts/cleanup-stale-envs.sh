@@ -21,7 +21,7 @@ fi
 
 # List all PR environments
 echo "Listing PR environments..."
-PR_CLUSTERS=$(ghostctl list | grep '^pr-' | awk '{print $1}' || true)
+PR_CLUSTERS=$(ghostctl list --no-probe -o name | grep '^pr-' || true)
 
 if [ -z "$PR_CLUSTERS" ]; then
     echo "No PR environments found."
@@ -50,7 +50,7 @@ for CLUSTER in $PR_CLUSTERS; do
                 echo "  → [DRY RUN] Would delete: $CLUSTER"
             else
                 echo "  → Deleting: $CLUSTER"
-                ghostctl down "$CLUSTER" || echo "  → Failed to delete $CLUSTER"
+                ghostctl down "$CLUSTER" --force --ignore-owner || echo "  → Failed to delete $CLUSTER"
             fi
         else
             echo "  → PR #$PR_NUM is still open ($PR_STATE)"
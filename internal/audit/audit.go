@@ -0,0 +1,155 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultFileName is the audit log kept in the ghostctl directory
+	DefaultFileName = "audit.log"
+
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Entry records one invocation of a mutating command
+type Entry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	User       string    `json:"user"`
+	Host       string    `json:"host,omitempty"`
+	Command    string    `json:"command"`
+	Args       []string  `json:"args,omitempty"`
+	Cluster    string    `json:"cluster,omitempty"`
+	Result     string    `json:"result"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// Duration returns how long the command ran
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+// Sink receives audit entries
+type Sink interface {
+	Write(entry Entry) error
+}
+
+// Source returns previously recorded audit entries
+type Source interface {
+	Read() ([]Entry, error)
+}
+
+// FileLog is an append-only JSON-lines audit log on local disk
+type FileLog struct {
+	path string
+}
+
+// NewFileLog returns the audit log at path
+func NewFileLog(path string) *FileLog {
+	return &FileLog{path: path}
+}
+
+// DefaultPath returns $HOME/.ghost/audit.log
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".ghost", DefaultFileName), nil
+}
+
+// Write appends an entry to the log as a single JSON line
+func (l *FileLog) Write(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	// O_APPEND keeps concurrent writers from interleaving within a line
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Read returns every entry in the log. Lines that cannot be parsed, such as
+// a line truncated by a crash, are skipped.
+func (l *FileLog) Read() ([]Entry, error) {
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// Filter selects audit entries. Empty fields match everything.
+type Filter struct {
+	Cluster string
+	User    string
+	Command string
+	Since   time.Time
+}
+
+// Match reports whether the entry passes the filter
+func (f Filter) Match(e Entry) bool {
+	switch {
+	case f.Cluster != "" && e.Cluster != f.Cluster:
+		return false
+	case f.User != "" && e.User != f.User:
+		return false
+	case f.Command != "" && e.Command != f.Command:
+		return false
+	case !f.Since.IsZero() && e.Timestamp.Before(f.Since):
+		return false
+	}
+	return true
+}
+
+// Query returns the entries matching the filter, oldest first
+func Query(entries []Entry, f Filter) []Entry {
+	var result []Entry
+	for _, e := range entries {
+		if f.Match(e) {
+			result = append(result, e)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
@@ -0,0 +1,206 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+)
+
+// DefaultConfigMapName is the host ConfigMap used by the shared audit sink
+const DefaultConfigMapName = "ghostctl-audit"
+
+const (
+	// maxConfigMapBytes is the size of the entries at which the oldest are
+	// deleted, well below the 1MiB limit on Kubernetes objects
+	maxConfigMapBytes = 768 * 1024
+
+	// trimConfigMapBytes is the size the entries are trimmed down to, so
+	// that trimming does not happen on every write
+	trimConfigMapBytes = 512 * 1024
+)
+
+// ConfigMapLog stores audit entries as keys of a ConfigMap in the host
+// namespace so that everyone sharing the host sees the same history. Each
+// entry is added with a merge patch, so concurrent writers never overwrite
+// each other. The oldest entries are deleted as the ConfigMap nears the
+// Kubernetes object size limit.
+type ConfigMapLog struct {
+	namespace string
+	name      string
+}
+
+// NewConfigMapLog returns the audit ConfigMap name in namespace
+func NewConfigMapLog(namespace, name string) *ConfigMapLog {
+	if name == "" {
+		name = DefaultConfigMapName
+	}
+	return &ConfigMapLog{namespace: namespace, name: name}
+}
+
+// entryKey returns a unique, time-ordered ConfigMap key for an entry
+func entryKey(e Entry) string {
+	key := e.Timestamp.UTC().Format("20060102T150405.000000000Z") + "-" + e.Command
+	if e.Cluster != "" {
+		key += "-" + e.Cluster
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
+
+// Write adds the entry to the ConfigMap, creating the ConfigMap if needed
+func (l *ConfigMapLog) Write(entry Entry) error {
+	if !shell.CommandExists("kubectl") {
+		return fmt.Errorf("kubectl not found in PATH")
+	}
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	patch, err := json.Marshal(map[string]map[string]string{
+		"data": {entryKey(entry): string(value)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build audit patch: %w", err)
+	}
+
+	patchArgs := []string{"patch", "configmap", l.name, "-n", l.namespace, "--type", "merge", "-p", string(patch), "-o", "json"}
+	result, err := shell.ExecuteCommandSplit("", "kubectl", patchArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if result.ExitCode == 0 {
+		return l.trim(result.Stdout)
+	}
+	if !strings.Contains(result.Stderr, "NotFound") {
+		return fmt.Errorf("failed to write audit entry (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+
+	// First entry on this host: create the ConfigMap and retry
+	result, err = shell.ExecuteCommandSplit("", "kubectl", "create", "configmap", l.name, "-n", l.namespace)
+	if err != nil {
+		return fmt.Errorf("failed to create audit ConfigMap: %w", err)
+	}
+	if result.ExitCode != 0 && !strings.Contains(result.Stderr, "AlreadyExists") {
+		return fmt.Errorf("failed to create audit ConfigMap (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+
+	result, err = shell.ExecuteCommandSplit("", "kubectl", patchArgs...)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to write audit entry (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	return l.trim(result.Stdout)
+}
+
+// trim deletes the oldest entries of the ConfigMap, as returned by the
+// patch that wrote the latest entry, once it has grown too large
+func (l *ConfigMapLog) trim(object string) error {
+	var cm struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(object), &cm); err != nil {
+		return fmt.Errorf("failed to parse audit ConfigMap: %w", err)
+	}
+	keys := oldestKeys(cm.Data, maxConfigMapBytes, trimConfigMapBytes)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	// A merge patch deleting the keys leaves entries written meanwhile alone
+	remove := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		remove[key] = nil
+	}
+	patch, err := json.Marshal(map[string]interface{}{"data": remove})
+	if err != nil {
+		return fmt.Errorf("failed to build audit patch: %w", err)
+	}
+	result, err := shell.ExecuteCommandSplit("", "kubectl", "patch", "configmap", l.name, "-n", l.namespace, "--type", "merge", "-p", string(patch))
+	if err != nil {
+		return fmt.Errorf("failed to trim audit ConfigMap: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to trim audit ConfigMap (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	return nil
+}
+
+// oldestKeys returns the keys to delete, oldest first, to shrink data to
+// at most target bytes once it is larger than max. Keys sort by time.
+func oldestKeys(data map[string]string, max, target int) []string {
+	size := 0
+	for key, value := range data {
+		size += len(key) + len(value)
+	}
+	if size <= max {
+		return nil
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var oldest []string
+	for _, key := range keys {
+		if size <= target {
+			break
+		}
+		size -= len(key) + len(data[key])
+		oldest = append(oldest, key)
+	}
+	return oldest
+}
+
+// Read returns every entry stored in the ConfigMap
+func (l *ConfigMapLog) Read() ([]Entry, error) {
+	if !shell.CommandExists("kubectl") {
+		return nil, fmt.Errorf("kubectl not found in PATH")
+	}
+
+	result, err := shell.ExecuteCommandSplit("", "kubectl", "get", "configmap", l.name, "-n", l.namespace, "-o", "json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit ConfigMap: %w", err)
+	}
+	if result.ExitCode != 0 {
+		if strings.Contains(result.Stderr, "NotFound") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read audit ConfigMap (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+
+	return parseConfigMapEntries([]byte(result.Stdout))
+}
+
+// parseConfigMapEntries decodes the entries held in a ConfigMap's data
+func parseConfigMapEntries(data []byte) ([]Entry, error) {
+	var cm struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &cm); err != nil {
+		return nil, fmt.Errorf("failed to parse audit ConfigMap: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(cm.Data))
+	for _, value := range cm.Data {
+		var entry Entry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces secret values in recorded arguments
+const Redacted = "[REDACTED]"
+
+// secretWords mark a flag or key whose value must not be recorded
+var secretWords = []string{
+	"password", "passwd", "secret", "token", "apikey", "api-key", "api_key",
+	"credential", "private-key", "private_key", "authorization",
+}
+
+func isSecretName(name string) bool {
+	name = strings.ToLower(strings.TrimLeft(name, "-"))
+	for _, word := range secretWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactArgs returns a copy of args with secret values replaced. It handles
+// "--token value", "--token=value", "KEY=value" pairs such as
+// "--from-literal=password=value", and passwords in URLs.
+func RedactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	redactNext := false
+	for i, arg := range args {
+		if redactNext && !strings.HasPrefix(arg, "-") {
+			redacted[i] = Redacted
+			redactNext = false
+			continue
+		}
+		redactNext = false
+
+		if strings.HasPrefix(arg, "-") && !strings.Contains(arg, "=") && isSecretName(arg) {
+			redacted[i] = arg
+			redactNext = true
+			continue
+		}
+
+		redacted[i] = redactArg(arg)
+	}
+	return redacted
+}
+
+// redactArg redacts everything after the first secret key in an
+// "a=b=c" style argument, and URL passwords
+func redactArg(arg string) string {
+	parts := strings.Split(arg, "=")
+	for i := 0; i < len(parts)-1; i++ {
+		if isSecretName(parts[i]) {
+			return strings.Join(parts[:i+1], "=") + "=" + Redacted
+		}
+	}
+
+	if strings.Contains(arg, "://") {
+		if u, err := url.Parse(arg); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), "xxxxx")
+				return strings.Replace(u.String(), "xxxxx", Redacted, 1)
+			}
+		}
+	}
+	return arg
+}
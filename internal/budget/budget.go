@@ -0,0 +1,215 @@
+package budget
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+)
+
+const (
+	WindowDaily   = "daily"
+	WindowMonthly = "monthly"
+)
+
+// Request describes a cluster about to be created
+type Request struct {
+	Owner  string
+	Labels map[string]string
+	Hourly float64 // estimated hourly cost of the cluster
+	TTL    string  // empty if the cluster runs until it is deleted
+}
+
+// Projected returns the cost of the request in a budget window ending at
+// end: over its TTL, or until the window ends if it has no valid TTL
+func (r Request) Projected(now, end time.Time) float64 {
+	if r.TTL != "" {
+		if _, err := utils.ParseDuration(r.TTL); err == nil {
+			return cost.Projected(r.Hourly, r.TTL)
+		}
+	}
+	if !end.After(now) {
+		return 0
+	}
+	return r.Hourly * end.Sub(now).Hours()
+}
+
+// Status is the consumption of one budget for one scope
+type Status struct {
+	Budget      config.Budget `json:"budget"`
+	Scope       string        `json:"scope"`
+	WindowStart time.Time     `json:"windowStart"`
+	Spent       float64       `json:"spent"`
+	Committed   float64       `json:"committed,omitempty"` // still to accrue in the window by running clusters
+	Remaining   float64       `json:"remaining"`
+}
+
+// ExceededError is returned when a request would overrun one or more budgets
+type ExceededError struct {
+	Projected float64
+	Exceeded  []Status
+	Pricing   config.Pricing
+}
+
+func (e *ExceededError) Error() string {
+	var parts []string
+	for _, s := range e.Exceeded {
+		committed := ""
+		if s.Committed > 0 {
+			committed = fmt.Sprintf(", %s committed to running clusters", cost.Format(s.Committed, e.Pricing))
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s %s: %s of %s spent%s, %s remaining)",
+			s.Budget.Name, s.Scope, s.Budget.Window,
+			cost.Format(s.Spent, e.Pricing), cost.Format(s.Budget.Limit, e.Pricing),
+			committed, cost.Format(s.Remaining, e.Pricing)))
+	}
+	return fmt.Sprintf("projected cost %s exceeds budget: %s",
+		cost.Format(e.Projected, e.Pricing), strings.Join(parts, "; "))
+}
+
+// Temporary reports whether the request can pass once spending in the
+// window falls, as running clusters end or the window starts over: its
+// projected cost fits within the limit of every budget it exceeds
+func (e *ExceededError) Temporary() bool {
+	for _, s := range e.Exceeded {
+		if e.Projected > s.Budget.Limit {
+			return false
+		}
+	}
+	return true
+}
+
+// WindowStart returns the start of the budget window containing now
+func WindowStart(window string, now time.Time) (time.Time, error) {
+	switch window {
+	case WindowDaily:
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), nil
+	case WindowMonthly, "":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid budget window %q (supported: daily, monthly)", window)
+	}
+}
+
+// WindowEnd returns the end of the budget window containing now
+func WindowEnd(window string, now time.Time) (time.Time, error) {
+	start, err := WindowStart(window, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if window == WindowDaily {
+		return start.AddDate(0, 0, 1), nil
+	}
+	return start.AddDate(0, 1, 0), nil
+}
+
+// Scope describes what a budget applies to, resolving "*" to the given user
+func Scope(b config.Budget, user string) string {
+	switch {
+	case b.User == "*":
+		return "user=" + user
+	case b.User != "":
+		return "user=" + b.User
+	case b.Label != "":
+		return "label " + b.Label
+	default:
+		return "global"
+	}
+}
+
+// Applies reports whether a budget covers a cluster with the given owner and labels.
+// user is the owner a "*" budget is being evaluated for.
+func Applies(b config.Budget, user, owner string, labels map[string]string) bool {
+	switch {
+	case b.User == "*":
+		return owner == user
+	case b.User != "":
+		return owner == b.User
+	case b.Label != "":
+		key, value, hasValue := strings.Cut(b.Label, "=")
+		actual, exists := labels[key]
+		return exists && (!hasValue || actual == value)
+	default:
+		return true
+	}
+}
+
+// Consumption computes how much of a budget has been spent in its current window.
+// clusters should include deleted clusters so that their cost is counted.
+func Consumption(b config.Budget, user string, clusters []*metadata.ClusterMetadata, pricing config.Pricing, now time.Time) (Status, error) {
+	start, err := WindowStart(b.Window, now)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{Budget: b, Scope: Scope(b, user), WindowStart: start}
+	for _, meta := range clusters {
+		if !Applies(b, user, meta.OwnerName(), meta.Labels) {
+			continue
+		}
+		status.Spent += cost.Accrued(meta, pricing, start, now)
+	}
+	status.Remaining = b.Limit - status.Spent
+
+	return status, nil
+}
+
+// committed returns what a running cluster will still cost between now and
+// the window end: until its TTL runs out, or until end if it has none.
+// Unclaimed pool members commit nothing; claiming one checks the budgets.
+func committed(meta *metadata.ClusterMetadata, pricing config.Pricing, now, end time.Time) float64 {
+	if meta.DeletedAt != nil || meta.Unclaimed() {
+		return 0
+	}
+	if expiresAt, ok := meta.ExpiresAt(); ok && expiresAt.Before(end) {
+		end = expiresAt
+	}
+	if !end.After(now) {
+		return 0
+	}
+	return cost.ClusterHourly(meta, pricing) * end.Sub(now).Hours()
+}
+
+// Check verifies that a request fits within every budget that applies to it,
+// on top of what has been spent and what running clusters will still cost
+// before the window ends. A request without a TTL is projected to run until
+// the window ends.
+func Check(budgets []config.Budget, req Request, clusters []*metadata.ClusterMetadata, pricing config.Pricing, now time.Time) error {
+	var exceeded []Status
+	var projected float64
+	for _, b := range budgets {
+		if !Applies(b, req.Owner, req.Owner, req.Labels) {
+			continue
+		}
+
+		status, err := Consumption(b, req.Owner, clusters, pricing, now)
+		if err != nil {
+			return fmt.Errorf("budget %s: %w", b.Name, err)
+		}
+		end, err := WindowEnd(b.Window, now)
+		if err != nil {
+			return fmt.Errorf("budget %s: %w", b.Name, err)
+		}
+		for _, meta := range clusters {
+			if Applies(b, req.Owner, meta.OwnerName(), meta.Labels) {
+				status.Committed += committed(meta, pricing, now, end)
+			}
+		}
+		status.Remaining -= status.Committed
+		if p := req.Projected(now, end); p > status.Remaining {
+			exceeded = append(exceeded, status)
+			if p > projected {
+				projected = p
+			}
+		}
+	}
+
+	if len(exceeded) > 0 {
+		return &ExceededError{Projected: projected, Exceeded: exceeded, Pricing: pricing}
+	}
+	return nil
+}
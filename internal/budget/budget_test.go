@@ -0,0 +1,112 @@
+package budget
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+)
+
+// TestWindowStart tests daily and monthly windows
+func TestWindowStart(t *testing.T) {
+	now := time.Date(2026, 3, 17, 15, 30, 0, 0, time.UTC)
+
+	daily, err := WindowStart(WindowDaily, now)
+	if err != nil || !daily.Equal(time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("WindowStart(daily) = %v, %v", daily, err)
+	}
+
+	monthly, err := WindowStart(WindowMonthly, now)
+	if err != nil || !monthly.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("WindowStart(monthly) = %v, %v", monthly, err)
+	}
+
+	if _, err := WindowStart("weekly", now); err == nil {
+		t.Error("WindowStart() expected error for unsupported window")
+	}
+
+	end, err := WindowEnd(WindowDaily, now)
+	if err != nil || !end.Equal(time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("WindowEnd(daily) = %v, %v", end, err)
+	}
+	end, err = WindowEnd(WindowMonthly, now)
+	if err != nil || !end.Equal(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("WindowEnd(monthly) = %v, %v", end, err)
+	}
+}
+
+// TestCheck tests budget enforcement for user, label and global budgets
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 3, 17, 12, 0, 0, 0, time.UTC)
+	clusters := []*metadata.ClusterMetadata{
+		// 10h at $2/h today for alice on the ml team
+		{Name: "a", Owner: "alice", CreatedAt: now.Add(-10 * time.Hour), TTL: "10h", HourlyCost: 2.0, Labels: map[string]string{"team": "ml"}},
+		// 2h at $1/h for bob
+		{Name: "b", Owner: "bob", CreatedAt: now.Add(-2 * time.Hour), TTL: "2h", HourlyCost: 1.0},
+		// 1h at $2/h for dave so far, and 10h more before its TTL runs out
+		{Name: "d", Owner: "dave", CreatedAt: now.Add(-time.Hour), TTL: "11h", HourlyCost: 2.0},
+	}
+
+	budgets := []config.Budget{
+		{Name: "per-user", User: "*", Window: WindowDaily, Limit: 25},
+		{Name: "ml", Label: "team=ml", Window: WindowMonthly, Limit: 30},
+		{Name: "global", Window: WindowMonthly, Limit: 100},
+	}
+
+	tests := []struct {
+		name     string
+		req      Request
+		exceeded []string
+	}{
+		{"within all budgets", Request{Owner: "bob", Hourly: 5, TTL: "1h"}, nil},
+		{"over user budget", Request{Owner: "alice", Hourly: 6, TTL: "1h"}, []string{"per-user"}},
+		{"over team budget", Request{Owner: "bob", Labels: map[string]string{"team": "ml"}, Hourly: 11, TTL: "1h"}, []string{"ml"}},
+		{"running clusters commit their remaining TTL", Request{Owner: "dave", Hourly: 4, TTL: "1h"}, []string{"per-user"}},
+		{"over global budget", Request{Owner: "carol", Hourly: 90, TTL: "1h"}, []string{"per-user", "global"}},
+		// Without a TTL bob's $2/h cluster costs $24 by the end of the day
+		// and $698 by the end of the month
+		{"no TTL runs until the window ends", Request{Owner: "bob", Hourly: 2}, []string{"per-user", "global"}},
+		{"invalid TTL runs until the window ends", Request{Owner: "bob", Hourly: 2, TTL: "soon"}, []string{"per-user", "global"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(budgets, tt.req, clusters, config.Pricing{}, now)
+			if tt.exceeded == nil {
+				if err != nil {
+					t.Fatalf("Check() err = %v, want nil", err)
+				}
+				return
+			}
+
+			var exceededErr *ExceededError
+			if !errors.As(err, &exceededErr) {
+				t.Fatalf("Check() err = %v, want ExceededError", err)
+			}
+			if len(exceededErr.Exceeded) != len(tt.exceeded) {
+				t.Fatalf("Check() exceeded %d budgets, want %v", len(exceededErr.Exceeded), tt.exceeded)
+			}
+			for i, name := range tt.exceeded {
+				if exceededErr.Exceeded[i].Budget.Name != name {
+					t.Errorf("exceeded[%d] = %s, want %s", i, exceededErr.Exceeded[i].Budget.Name, name)
+				}
+			}
+		})
+	}
+}
+
+// TestExceededTemporary tests that only requests that fit within a whole
+// budget can wait for spending to fall
+func TestExceededTemporary(t *testing.T) {
+	monthly := Status{Budget: config.Budget{Name: "monthly", Limit: 100}}
+	daily := Status{Budget: config.Budget{Name: "daily", Limit: 10}}
+
+	if e := (&ExceededError{Projected: 8, Exceeded: []Status{monthly, daily}}); !e.Temporary() {
+		t.Error("Temporary() = false for a request within every limit")
+	}
+	if e := (&ExceededError{Projected: 20, Exceeded: []Status{monthly, daily}}); e.Temporary() {
+		t.Error("Temporary() = true for a request over a whole budget")
+	}
+}
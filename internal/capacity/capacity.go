@@ -0,0 +1,507 @@
+// Package capacity reports the free resources of host nodes and checks
+// that a new cluster fits in them
+package capacity
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/internal/vcluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+)
+
+// Resources are amounts of CPU, memory and extended resources such as GPUs
+type Resources struct {
+	CPUMillis   int64            `json:"cpuMillis"`
+	MemoryBytes int64            `json:"memoryBytes"`
+	GPUs        map[string]int64 `json:"gpus,omitempty"` // by resource name, e.g. nvidia.com/gpu
+}
+
+// Taint is a node taint
+type Taint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+// Node is a host node with its allocatable resources and the requests of
+// the pods running on it
+type Node struct {
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Taints      []Taint           `json:"taints,omitempty"`
+	Schedulable bool              `json:"schedulable"` // ready and not cordoned
+	Allocatable Resources         `json:"allocatable"`
+	Requested   Resources         `json:"requested"`
+}
+
+// Free returns the node's allocatable resources not yet requested
+func (n *Node) Free() Resources {
+	free := Resources{
+		CPUMillis:   n.Allocatable.CPUMillis - n.Requested.CPUMillis,
+		MemoryBytes: n.Allocatable.MemoryBytes - n.Requested.MemoryBytes,
+		GPUs:        make(map[string]int64, len(n.Allocatable.GPUs)),
+	}
+	for name, count := range n.Allocatable.GPUs {
+		free.GPUs[name] = count - n.Requested.GPUs[name]
+	}
+	if free.CPUMillis < 0 {
+		free.CPUMillis = 0
+	}
+	if free.MemoryBytes < 0 {
+		free.MemoryBytes = 0
+	}
+	return free
+}
+
+// Arch returns the node's architecture
+func (n *Node) Arch() string {
+	return n.Labels[cluster.ArchLabel]
+}
+
+// Matches reports whether the node has every label in selector
+func (n *Node) Matches(selector map[string]string) bool {
+	for k, v := range selector {
+		if n.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Tolerates reports whether pods with the given tolerations may be
+// scheduled on the node
+func (n *Node) Tolerates(tolerations []cluster.Toleration) bool {
+	for _, taint := range n.Taints {
+		if taint.Effect == "PreferNoSchedule" {
+			continue
+		}
+		tolerated := false
+		for _, t := range tolerations {
+			if tolerates(t, taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+func tolerates(t cluster.Toleration, taint Taint) bool {
+	if t.Effect != "" && t.Effect != taint.Effect {
+		return false
+	}
+	if t.Operator == "Exists" {
+		return t.Key == "" || t.Key == taint.Key
+	}
+	return t.Key == taint.Key && t.Value == taint.Value
+}
+
+// GPUType returns the GPU type whose node labels the node has, or "" if
+// it matches none in table
+func (n *Node) GPUType(table map[string]map[string]string) string {
+	types := make([]string, 0, len(table))
+	for gpuType := range table {
+		types = append(types, gpuType)
+	}
+	sort.Strings(types)
+	for _, gpuType := range types {
+		if len(table[gpuType]) > 0 && n.Matches(table[gpuType]) {
+			return gpuType
+		}
+	}
+	return ""
+}
+
+// Request is the resources a new cluster needs and where it may run
+type Request struct {
+	CPU        string
+	Memory     string
+	GPU        int
+	GPUType    string
+	Scheduling cluster.Scheduling
+	// GPUNodeLabels select the nodes with the requested GPU type
+	GPUNodeLabels map[string]string
+}
+
+// Shortage is a resource a request needs more of than is free
+type Shortage struct {
+	Resource  string `json:"resource"`
+	Requested string `json:"requested"`
+	Free      string `json:"free"`
+	Nodes     int    `json:"nodes"` // nodes the resource was looked for on
+	Selector  string `json:"selector,omitempty"`
+}
+
+func (s Shortage) String() string {
+	where := fmt.Sprintf("%d eligible nodes", s.Nodes)
+	if s.Nodes == 1 {
+		where = "1 eligible node"
+	}
+	if s.Selector != "" {
+		where += " matching " + s.Selector
+	}
+	return fmt.Sprintf("%s: requested %s, free %s on %s", s.Resource, s.Requested, s.Free, where)
+}
+
+// InsufficientError is returned when the host cannot fit a request
+type InsufficientError struct {
+	Shortages []Shortage
+}
+
+func (e *InsufficientError) Error() string {
+	parts := make([]string, len(e.Shortages))
+	for i, s := range e.Shortages {
+		parts[i] = s.String()
+	}
+	return "insufficient host capacity: " + strings.Join(parts, "; ")
+}
+
+// Check verifies that the nodes have enough free CPU, memory and GPUs of
+// the requested type for a request. Only schedulable nodes the request's
+// node selector, architecture and tolerations allow are counted; GPUs are
+// also limited to nodes with the GPU node labels. Capacity is summed over
+// nodes, since a cluster's pods spread across them.
+func Check(req Request, nodes []Node) error {
+	selector := make(map[string]string, len(req.Scheduling.NodeSelector)+1)
+	for k, v := range req.Scheduling.NodeSelector {
+		selector[k] = v
+	}
+	if req.Scheduling.Architecture != "" {
+		selector[cluster.ArchLabel] = req.Scheduling.Architecture
+	}
+
+	var eligible []Node
+	for _, n := range nodes {
+		if n.Schedulable && n.Matches(selector) && n.Tolerates(req.Scheduling.Tolerations) {
+			eligible = append(eligible, n)
+		}
+	}
+	selectorText := formatSelector(selector)
+
+	var shortages []Shortage
+	if req.CPU != "" {
+		cores, err := utils.ParseCPU(req.CPU)
+		if err != nil {
+			return err
+		}
+		requested := int64(cores*1000 + 0.5)
+		var free int64
+		for i := range eligible {
+			free += eligible[i].Free().CPUMillis
+		}
+		if requested > free {
+			shortages = append(shortages, Shortage{
+				Resource: "CPU", Requested: FormatCPU(requested), Free: FormatCPU(free),
+				Nodes: len(eligible), Selector: selectorText,
+			})
+		}
+	}
+
+	if req.Memory != "" {
+		requested, err := utils.ParseMemory(req.Memory)
+		if err != nil {
+			return err
+		}
+		var free int64
+		for i := range eligible {
+			free += eligible[i].Free().MemoryBytes
+		}
+		if requested > free {
+			shortages = append(shortages, Shortage{
+				Resource: "memory", Requested: utils.FormatBytes(requested), Free: utils.FormatBytes(free),
+				Nodes: len(eligible), Selector: selectorText,
+			})
+		}
+	}
+
+	if req.GPU > 0 {
+		resource := vcluster.GPUResourceName(req.GPUType)
+		gpuSelector := make(map[string]string, len(selector)+len(req.GPUNodeLabels))
+		for k, v := range selector {
+			gpuSelector[k] = v
+		}
+		for k, v := range req.GPUNodeLabels {
+			gpuSelector[k] = v
+		}
+
+		var free int64
+		gpuNodes := 0
+		for i := range eligible {
+			if !eligible[i].Matches(gpuSelector) {
+				continue
+			}
+			gpuNodes++
+			if n := eligible[i].Free().GPUs[resource]; n > 0 {
+				free += n
+			}
+		}
+		if int64(req.GPU) > free {
+			name := "GPU"
+			if req.GPUType != "" {
+				name += " " + req.GPUType
+			}
+			shortages = append(shortages, Shortage{
+				Resource: fmt.Sprintf("%s (%s)", name, resource), Requested: fmt.Sprintf("%d", req.GPU), Free: fmt.Sprintf("%d", free),
+				Nodes: gpuNodes, Selector: formatSelector(gpuSelector),
+			})
+		}
+	}
+
+	if len(shortages) > 0 {
+		return &InsufficientError{Shortages: shortages}
+	}
+	return nil
+}
+
+func formatSelector(selector map[string]string) string {
+	pairs := make([]string, 0, len(selector))
+	for k, v := range selector {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// FormatCPU renders millicores as cores, e.g. 2.5
+func FormatCPU(millis int64) string {
+	if millis%1000 == 0 {
+		return fmt.Sprintf("%d", millis/1000)
+	}
+	return strings.TrimRight(fmt.Sprintf("%.3f", float64(millis)/1000), "0")
+}
+
+// ListNodes returns the host's nodes with the resources requested by the
+// pods running on them. It needs permission to list nodes and pods in all
+// namespaces.
+func ListNodes() ([]Node, error) {
+	if !shell.CommandExists("kubectl") {
+		return nil, fmt.Errorf("kubectl not found in PATH")
+	}
+
+	var nodeList struct {
+		Items []struct {
+			Metadata struct {
+				Name   string            `json:"name"`
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+			Spec struct {
+				Unschedulable bool    `json:"unschedulable"`
+				Taints        []Taint `json:"taints"`
+			} `json:"spec"`
+			Status struct {
+				Allocatable map[string]string `json:"allocatable"`
+				Conditions  []struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+				} `json:"conditions"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := kubectlJSON(&nodeList, "get", "nodes", "-o", "json"); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var podList struct {
+		Items []struct {
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+			Spec struct {
+				NodeName       string            `json:"nodeName"`
+				Containers     []podContainer    `json:"containers"`
+				InitContainers []podContainer    `json:"initContainers"`
+				Overhead       map[string]string `json:"overhead"`
+			} `json:"spec"`
+		} `json:"items"`
+	}
+	if err := kubectlJSON(&podList, "get", "pods", "--all-namespaces", "-o", "json",
+		"--field-selector", "status.phase!=Succeeded,status.phase!=Failed"); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	nodes := make([]Node, 0, len(nodeList.Items))
+	index := make(map[string]int, len(nodeList.Items))
+	for _, item := range nodeList.Items {
+		allocatable, err := parseResources(item.Status.Allocatable)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", item.Metadata.Name, err)
+		}
+		ready := false
+		for _, c := range item.Status.Conditions {
+			if c.Type == "Ready" {
+				ready = c.Status == "True"
+			}
+		}
+		index[item.Metadata.Name] = len(nodes)
+		nodes = append(nodes, Node{
+			Name:        item.Metadata.Name,
+			Labels:      item.Metadata.Labels,
+			Taints:      item.Spec.Taints,
+			Schedulable: ready && !item.Spec.Unschedulable,
+			Allocatable: allocatable,
+			Requested:   Resources{GPUs: make(map[string]int64)},
+		})
+	}
+
+	// A pod whose requests cannot be read is left out rather than failing
+	// the whole check; the API server accepted it, so this is rare
+	var skipped int
+	var skipErr error
+	for _, pod := range podList.Items {
+		i, ok := index[pod.Spec.NodeName]
+		if !ok {
+			continue // pending, or on a node that just went away
+		}
+		requests, err := podRequests(pod.Spec.Containers, pod.Spec.InitContainers, pod.Spec.Overhead)
+		if err != nil {
+			skipped++
+			skipErr = fmt.Errorf("pod %s/%s: %w", pod.Metadata.Namespace, pod.Metadata.Name, err)
+			continue
+		}
+		nodes[i].Requested.add(requests)
+	}
+	if skipped > 0 {
+		telemetry.GetLogger().Warn("Ignoring the requests of pods that could not be read; free capacity may be overstated",
+			"pods", skipped, "error", skipErr)
+	}
+	return nodes, nil
+}
+
+type podContainer struct {
+	Resources struct {
+		Requests map[string]string `json:"requests"`
+	} `json:"resources"`
+}
+
+// podRequests returns the resources a pod reserves on its node: the larger
+// of its containers' summed requests and any single init container's,
+// plus the pod overhead
+func podRequests(containers, initContainers []podContainer, overhead map[string]string) (Resources, error) {
+	total := Resources{GPUs: make(map[string]int64)}
+	for _, c := range containers {
+		r, err := parseResources(c.Resources.Requests)
+		if err != nil {
+			return Resources{}, err
+		}
+		total.add(r)
+	}
+	for _, c := range initContainers {
+		r, err := parseResources(c.Resources.Requests)
+		if err != nil {
+			return Resources{}, err
+		}
+		total.max(r)
+	}
+	extra, err := parseResources(overhead)
+	if err != nil {
+		return Resources{}, err
+	}
+	total.add(extra)
+	return total, nil
+}
+
+// parseResources reads CPU, memory and GPU quantities from a resource list
+func parseResources(list map[string]string) (Resources, error) {
+	r := Resources{GPUs: make(map[string]int64)}
+	for name, quantity := range list {
+		if name != "cpu" && name != "memory" && !isGPUResource(name) {
+			continue
+		}
+		value, err := parseQuantity(quantity)
+		if err != nil {
+			return Resources{}, fmt.Errorf("invalid %s quantity: %s", name, quantity)
+		}
+		switch name {
+		case "cpu":
+			r.CPUMillis = int64(math.Round(value * 1000))
+		case "memory":
+			r.MemoryBytes = int64(math.Round(value))
+		default:
+			r.GPUs[name] = int64(math.Round(value))
+		}
+	}
+	return r, nil
+}
+
+// quantitySuffixes are the multipliers of Kubernetes quantity suffixes
+var quantitySuffixes = map[string]float64{
+	"n": 1e-9, "u": 1e-6, "m": 1e-3, "": 1,
+	"k": 1e3, "M": 1e6, "G": 1e9, "T": 1e12, "P": 1e15, "E": 1e18,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40, "Pi": 1 << 50, "Ei": 1 << 60,
+}
+
+// quantityPattern matches a Kubernetes quantity: a signed decimal number
+// followed by a suffix or a decimal exponent, e.g. 500m, 4Gi, 64k or 129e6
+var quantityPattern = regexp.MustCompile(`^([+-]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+))(?:([eE][+-]?[0-9]+)|([numkMGTPE]|[KMGTPE]i)?)$`)
+
+// parseQuantity parses a Kubernetes resource quantity in any of the forms
+// the API server accepts
+func parseQuantity(quantity string) (float64, error) {
+	m := quantityPattern.FindStringSubmatch(strings.TrimSpace(quantity))
+	if m == nil {
+		return 0, fmt.Errorf("invalid quantity: %s", quantity)
+	}
+	value, err := strconv.ParseFloat(m[1]+m[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity: %s", quantity)
+	}
+	return value * quantitySuffixes[m[3]], nil
+}
+
+func isGPUResource(name string) bool {
+	for _, resource := range vcluster.GPUResourceNames() {
+		if name == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resources) add(o Resources) {
+	r.CPUMillis += o.CPUMillis
+	r.MemoryBytes += o.MemoryBytes
+	for name, count := range o.GPUs {
+		r.GPUs[name] += count
+	}
+}
+
+func (r *Resources) max(o Resources) {
+	if o.CPUMillis > r.CPUMillis {
+		r.CPUMillis = o.CPUMillis
+	}
+	if o.MemoryBytes > r.MemoryBytes {
+		r.MemoryBytes = o.MemoryBytes
+	}
+	for name, count := range o.GPUs {
+		if count > r.GPUs[name] {
+			r.GPUs[name] = count
+		}
+	}
+}
+
+func kubectlJSON(v interface{}, args ...string) error {
+	result, err := shell.ExecuteCommandSplit("", "kubectl", args...)
+	if err != nil {
+		return err
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("exit code %d: %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	if err := json.Unmarshal([]byte(result.Stdout), v); err != nil {
+		return fmt.Errorf("failed to parse kubectl output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package capacity
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+)
+
+func node(name string, cpu, memory int64, labels map[string]string) Node {
+	return Node{
+		Name:        name,
+		Labels:      labels,
+		Schedulable: true,
+		Allocatable: Resources{CPUMillis: cpu, MemoryBytes: memory << 30, GPUs: map[string]int64{}},
+		Requested:   Resources{GPUs: map[string]int64{}},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	a := node("a", 4000, 16, map[string]string{cluster.ArchLabel: "amd64"})
+	a.Requested.CPUMillis = 3000
+	b := node("b", 2000, 8, map[string]string{cluster.ArchLabel: "arm64"})
+	gpu := node("gpu", 8000, 64, map[string]string{cluster.ArchLabel: "amd64", "accelerator": "t4"})
+	gpu.Taints = []Taint{{Key: "nvidia.com/gpu", Effect: "NoSchedule"}}
+	gpu.Allocatable.GPUs["nvidia.com/gpu"] = 2
+	gpu.Requested.GPUs["nvidia.com/gpu"] = 1
+	cordoned := node("cordoned", 64000, 256, nil)
+	cordoned.Schedulable = false
+	nodes := []Node{a, b, gpu, cordoned}
+
+	tests := []struct {
+		name string
+		req  Request
+		want []string // shortages, empty if the request fits
+	}{
+		{name: "fits across nodes", req: Request{CPU: "3", Memory: "20Gi"}},
+		{name: "tainted and cordoned nodes do not count", req: Request{CPU: "4"}, want: []string{"CPU: requested 4, free 3 on 2 eligible nodes"}},
+		{name: "architecture", req: Request{Memory: "10Gi", Scheduling: cluster.Scheduling{Architecture: "arm64"}},
+			want: []string{"memory: requested 10.00 GB, free 8.00 GB on 1 eligible node matching kubernetes.io/arch=arm64"}},
+		{name: "free GPU on a tolerated node", req: Request{
+			GPU: 1, GPUType: "nvidia-t4", GPUNodeLabels: map[string]string{"accelerator": "t4"},
+			Scheduling: cluster.Scheduling{Tolerations: []cluster.Toleration{{Key: "nvidia.com/gpu", Operator: "Exists"}}},
+		}},
+		{name: "GPUs in use", req: Request{
+			GPU: 2, GPUType: "nvidia-t4", GPUNodeLabels: map[string]string{"accelerator": "t4"},
+			Scheduling: cluster.Scheduling{Tolerations: []cluster.Toleration{{Key: "nvidia.com/gpu", Operator: "Exists"}}},
+		}, want: []string{"GPU nvidia-t4 (nvidia.com/gpu): requested 2, free 1 on 1 eligible node matching accelerator=t4"}},
+		{name: "GPU node not tolerated", req: Request{GPU: 1, GPUType: "nvidia-t4"},
+			want: []string{"requested 1, free 0 on 2 eligible nodes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.req, nodes)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Check() error = %v", err)
+				}
+				return
+			}
+			var insufficient *InsufficientError
+			if !errors.As(err, &insufficient) || len(insufficient.Shortages) != len(tt.want) {
+				t.Fatalf("Check() error = %v, want %d shortages", err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := insufficient.Shortages[i].String(); !strings.Contains(got, want) {
+					t.Errorf("shortage = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPodRequests(t *testing.T) {
+	containers := make([]podContainer, 2)
+	containers[0].Resources.Requests = map[string]string{"cpu": "250m", "memory": "256Mi"}
+	containers[1].Resources.Requests = map[string]string{"cpu": "500m", "nvidia.com/gpu": "1", "ephemeral-storage": "1Gi"}
+	initContainers := make([]podContainer, 1)
+	initContainers[0].Resources.Requests = map[string]string{"cpu": "1", "memory": "64Mi"}
+
+	got, err := podRequests(containers, initContainers, map[string]string{"memory": "10Mi"})
+	if err != nil {
+		t.Fatalf("podRequests() error = %v", err)
+	}
+	if got.CPUMillis != 1000 || got.MemoryBytes != 266<<20 || got.GPUs["nvidia.com/gpu"] != 1 {
+		t.Errorf("podRequests() = %+v, want 1000m CPU, 266Mi memory and 1 GPU", got)
+	}
+
+	containers[0].Resources.Requests["memory"] = "lots"
+	if _, err := podRequests(containers, nil, nil); err == nil {
+		t.Error("podRequests() with an invalid quantity succeeded, want error")
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		quantity string
+		want     float64
+	}{
+		{"2", 2},
+		{"100m", 0.1},
+		{"1.5", 1.5},
+		{"64k", 64000},
+		{"129e6", 129e6},
+		{"1E3", 1000},
+		{"256Mi", 256 << 20},
+		{"1.5Gi", 1.5 * (1 << 30)},
+		{"2M", 2e6},
+		{"500u", 500e-6},
+		{".5", 0.5},
+	}
+	for _, tt := range tests {
+		got, err := parseQuantity(tt.quantity)
+		if err != nil || math.Abs(got-tt.want) > 1e-9*tt.want {
+			t.Errorf("parseQuantity(%q) = %v, %v; want %v", tt.quantity, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "lots", "1.2.3", "4GB", "Gi", "1e"} {
+		if _, err := parseQuantity(bad); err == nil {
+			t.Errorf("parseQuantity(%q) succeeded, want error", bad)
+		}
+	}
+
+	// Quantities other tools write are read as the API server would
+	r, err := parseResources(map[string]string{"cpu": "250m", "memory": "129e6"})
+	if err != nil || r.CPUMillis != 250 || r.MemoryBytes != 129000000 {
+		t.Errorf("parseResources() = %+v, %v; want 250m CPU and 129e6 bytes", r, err)
+	}
+}
@@ -35,15 +35,27 @@ type Config struct {
 
 // CreateOptions represents options for creating a cluster
 type CreateOptions struct {
-	Name      string
-	Namespace string
-	CPU       string
-	Memory    string
-	Storage   string
-	GPU       int
-	GPUType   string
-	TTL       string
-	Labels    map[string]string
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	CPU       string            `json:"cpu,omitempty"`
+	Memory    string            `json:"memory,omitempty"`
+	Storage   string            `json:"storage,omitempty"`
+	GPU       int               `json:"gpu,omitempty"`
+	GPUType   string            `json:"gpuType,omitempty"`
+	TTL       string            `json:"ttl,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+
+	Security   *Security  `json:"security,omitempty"`
+	Scheduling Scheduling `json:"scheduling,omitempty"`
+
+	// GPUNodeLabels select host nodes with the cluster's GPU type, from
+	// the configured gpuNodeLabels table
+	GPUNodeLabels map[string]string `json:"gpuNodeLabels,omitempty"`
+
+	// VClusterValues and ValuesFiles are vCluster chart values supplied by
+	// the template; see Values for how they combine
+	VClusterValues map[string]interface{} `json:"vclusterValues,omitempty"`
+	ValuesFiles    []string               `json:"valuesFiles,omitempty"`
 }
 
 // ClusterInfo represents information about a cluster
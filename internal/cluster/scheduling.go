@@ -0,0 +1,157 @@
+package cluster
+
+import "fmt"
+
+// Node architectures a cluster can be pinned to
+const (
+	ArchAMD64 = "amd64"
+	ArchARM64 = "arm64"
+)
+
+// ArchLabel is the well-known node label holding a node's architecture
+const ArchLabel = "kubernetes.io/arch"
+
+// Scheduling constrains which host nodes a cluster's pods run on
+type Scheduling struct {
+	// NodeSelector lists node labels every pod must match
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty" json:"nodeSelector,omitempty"`
+	// Tolerations let pods run on tainted nodes
+	Tolerations []Toleration `yaml:"tolerations,omitempty" json:"tolerations,omitempty"`
+	// Affinity is a Kubernetes affinity, applied to the control plane
+	Affinity map[string]interface{} `yaml:"affinity,omitempty" json:"affinity,omitempty"`
+	// PriorityClassName is the host PriorityClass of the control plane
+	PriorityClassName string `yaml:"priorityClassName,omitempty" json:"priorityClassName,omitempty"`
+	// Architecture pins pods to amd64 or arm64 nodes
+	Architecture string `yaml:"architecture,omitempty" json:"architecture,omitempty"`
+}
+
+// Toleration is a Kubernetes toleration
+type Toleration struct {
+	Key      string `yaml:"key,omitempty" json:"key,omitempty"`
+	Operator string `yaml:"operator,omitempty" json:"operator,omitempty"` // Equal (default) or Exists
+	Value    string `yaml:"value,omitempty" json:"value,omitempty"`
+	Effect   string `yaml:"effect,omitempty" json:"effect,omitempty"` // NoSchedule, PreferNoSchedule or NoExecute; empty matches all
+}
+
+// affinityFields are the kinds of affinity a pod can have
+var affinityFields = map[string]bool{"nodeAffinity": true, "podAffinity": true, "podAntiAffinity": true}
+
+// Validate checks the architecture, tolerations and affinity
+func (s Scheduling) Validate() error {
+	switch s.Architecture {
+	case "", ArchAMD64, ArchARM64:
+	default:
+		return fmt.Errorf("invalid architecture %q (expected amd64 or arm64)", s.Architecture)
+	}
+	for _, t := range s.Tolerations {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+	}
+	for key := range s.Affinity {
+		if !affinityFields[key] {
+			return fmt.Errorf("invalid affinity %q (expected nodeAffinity, podAffinity or podAntiAffinity)", key)
+		}
+	}
+	return nil
+}
+
+// Validate checks the operator and effect of a toleration
+func (t Toleration) Validate() error {
+	switch t.Operator {
+	case "", "Equal":
+		if t.Key == "" {
+			return fmt.Errorf("toleration with operator Equal needs a key")
+		}
+	case "Exists":
+		if t.Value != "" {
+			return fmt.Errorf("toleration %q: operator Exists takes no value", t.Key)
+		}
+	default:
+		return fmt.Errorf("toleration %q: invalid operator %q (expected Equal or Exists)", t.Key, t.Operator)
+	}
+	switch t.Effect {
+	case "", "NoSchedule", "PreferNoSchedule", "NoExecute":
+	default:
+		return fmt.Errorf("toleration %q: invalid effect %q (expected NoSchedule, PreferNoSchedule or NoExecute)", t.Key, t.Effect)
+	}
+	return nil
+}
+
+// String renders the toleration the way vCluster's enforceTolerations
+// expects it: key=value:effect, or key:effect for Exists
+func (t Toleration) String() string {
+	s := t.Key
+	if t.Operator != "Exists" {
+		s += "=" + t.Value
+	}
+	if t.Effect != "" {
+		s += ":" + t.Effect
+	}
+	return s
+}
+
+// object returns the toleration as a Kubernetes object
+func (t Toleration) object() map[string]interface{} {
+	obj := make(map[string]interface{})
+	for k, v := range map[string]string{"key": t.Key, "operator": t.Operator, "value": t.Value, "effect": t.Effect} {
+		if v != "" {
+			obj[k] = v
+		}
+	}
+	return obj
+}
+
+// schedulingValues returns the chart values applying the scheduling
+// constraints. The control plane gets every constraint. Workloads synced
+// from the vCluster get the node selector, including the GPU node labels,
+// and the tolerations, which vCluster enforces on every pod it syncs;
+// vCluster cannot enforce an affinity or priority class on them.
+func (o *CreateOptions) schedulingValues() map[string]interface{} {
+	values := make(map[string]interface{})
+	s := o.Scheduling
+
+	selector := make(map[string]interface{})
+	for k, v := range s.NodeSelector {
+		selector[k] = v
+	}
+	if s.Architecture != "" {
+		selector[ArchLabel] = s.Architecture
+	}
+
+	// The control plane needs no GPU, so only workloads are sent to GPU nodes
+	if len(selector) > 0 {
+		setValue(values, "controlPlane.statefulSet.scheduling.nodeSelector", copyValues(selector))
+	}
+	if len(s.Tolerations) > 0 {
+		tolerations := make([]interface{}, len(s.Tolerations))
+		for i, t := range s.Tolerations {
+			tolerations[i] = t.object()
+		}
+		setValue(values, "controlPlane.statefulSet.scheduling.tolerations", tolerations)
+	}
+	if len(s.Affinity) > 0 {
+		setValue(values, "controlPlane.statefulSet.scheduling.affinity", copyValues(s.Affinity))
+	}
+	if s.PriorityClassName != "" {
+		setValue(values, "controlPlane.statefulSet.scheduling.priorityClassName", s.PriorityClassName)
+	}
+
+	for k, v := range o.GPUNodeLabels {
+		selector[k] = v
+	}
+	if len(selector) > 0 {
+		// Syncing the selected host nodes makes vCluster set the selector
+		// on every pod it syncs to the host
+		setValue(values, "sync.fromHost.nodes.enabled", true)
+		setValue(values, "sync.fromHost.nodes.selector.labels", selector)
+	}
+	if len(s.Tolerations) > 0 {
+		enforced := make([]interface{}, len(s.Tolerations))
+		for i, t := range s.Tolerations {
+			enforced[i] = t.String()
+		}
+		setValue(values, "sync.toHost.pods.enforceTolerations", enforced)
+	}
+	return values
+}
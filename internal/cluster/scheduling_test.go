@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchedulingValues(t *testing.T) {
+	opts := &CreateOptions{
+		Scheduling: Scheduling{
+			NodeSelector:      map[string]string{"pool": "ml"},
+			Tolerations:       []Toleration{{Key: "gpu", Value: "true", Effect: "NoSchedule"}, {Key: "spot", Operator: "Exists"}},
+			Affinity:          map[string]interface{}{"podAntiAffinity": map[string]interface{}{}},
+			PriorityClassName: "batch",
+			Architecture:      ArchARM64,
+		},
+		GPUNodeLabels: map[string]string{"accelerator": "nvidia-t4"},
+	}
+
+	want := map[string]interface{}{
+		"controlPlane": map[string]interface{}{
+			"statefulSet": map[string]interface{}{
+				"scheduling": map[string]interface{}{
+					"nodeSelector": map[string]interface{}{"pool": "ml", ArchLabel: "arm64"},
+					"tolerations": []interface{}{
+						map[string]interface{}{"key": "gpu", "value": "true", "effect": "NoSchedule"},
+						map[string]interface{}{"key": "spot", "operator": "Exists"},
+					},
+					"affinity":          map[string]interface{}{"podAntiAffinity": map[string]interface{}{}},
+					"priorityClassName": "batch",
+				},
+			},
+		},
+		"sync": map[string]interface{}{
+			"fromHost": map[string]interface{}{
+				"nodes": map[string]interface{}{
+					"enabled": true,
+					"selector": map[string]interface{}{
+						"labels": map[string]interface{}{"pool": "ml", ArchLabel: "arm64", "accelerator": "nvidia-t4"},
+					},
+				},
+			},
+			"toHost": map[string]interface{}{
+				"pods": map[string]interface{}{"enforceTolerations": []interface{}{"gpu=true:NoSchedule", "spot"}},
+			},
+		},
+	}
+	if got := opts.GeneratedValues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GeneratedValues() = %v, want %v", got, want)
+	}
+
+	if got := (&CreateOptions{}).GeneratedValues(); len(got) != 0 {
+		t.Errorf("GeneratedValues() without constraints = %v, want none", got)
+	}
+}
+
+func TestSchedulingValidate(t *testing.T) {
+	valid := []Scheduling{
+		{},
+		{Architecture: ArchAMD64},
+		{Tolerations: []Toleration{{Key: "gpu", Operator: "Exists", Effect: "NoExecute"}}},
+		{Affinity: map[string]interface{}{"nodeAffinity": map[string]interface{}{}}},
+	}
+	for _, s := range valid {
+		if err := s.Validate(); err != nil {
+			t.Errorf("Validate(%+v) error = %v", s, err)
+		}
+	}
+
+	invalid := []Scheduling{
+		{Architecture: "x86"},
+		{Tolerations: []Toleration{{Value: "true"}}},
+		{Tolerations: []Toleration{{Key: "gpu", Operator: "Exists", Value: "true"}}},
+		{Tolerations: []Toleration{{Key: "gpu", Operator: "In"}}},
+		{Tolerations: []Toleration{{Key: "gpu", Value: "true", Effect: "Evict"}}},
+		{Affinity: map[string]interface{}{"nodeSelector": map[string]interface{}{}}},
+	}
+	for _, s := range invalid {
+		if err := s.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", s)
+		}
+	}
+}
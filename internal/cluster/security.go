@@ -0,0 +1,132 @@
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Pod Security Standards levels
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+)
+
+// Security describes how a cluster is isolated from the host and from
+// other clusters
+type Security struct {
+	// PodSecurity is the Pod Security Standard enforced for workloads in
+	// the vCluster: privileged, baseline or restricted
+	PodSecurity string `yaml:"podSecurity,omitempty" json:"podSecurity,omitempty"`
+	// NetworkIsolation applies host NetworkPolicies so that only the
+	// vCluster's own pods can reach its pods
+	NetworkIsolation bool `yaml:"networkIsolation,omitempty" json:"networkIsolation,omitempty"`
+	// Egress restricts outbound traffic from the vCluster's workloads
+	Egress *Egress `yaml:"egress,omitempty" json:"egress,omitempty"`
+	// Isolated enables vCluster's isolated mode: pod security (baseline
+	// unless PodSecurity is set), a resource quota, a limit range and a
+	// network policy managed by vCluster itself
+	Isolated bool `yaml:"isolated,omitempty" json:"isolated,omitempty"`
+}
+
+// Egress restricts outbound traffic
+type Egress struct {
+	// DefaultDeny blocks egress except to the vCluster itself, DNS and the
+	// Allow list
+	DefaultDeny bool `yaml:"defaultDeny,omitempty" json:"defaultDeny,omitempty"`
+	// Allow lists destinations as CIDR or CIDR:port, e.g. 10.0.0.0/8 or
+	// 0.0.0.0/0:443
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+}
+
+// EgressRule is a parsed entry of Egress.Allow
+type EgressRule struct {
+	CIDR string
+	Port int // 0 for any port
+}
+
+// ParseEgressRule parses an egress allowlist entry
+func ParseEgressRule(rule string) (EgressRule, error) {
+	cidr, port := rule, ""
+	if i := strings.LastIndex(rule, ":"); i > strings.LastIndex(rule, "/") {
+		cidr, port = rule[:i], rule[i+1:]
+	}
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		return EgressRule{}, fmt.Errorf("invalid egress rule %q: expected CIDR or CIDR:port", rule)
+	}
+
+	r := EgressRule{CIDR: cidr}
+	if port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			return EgressRule{}, fmt.Errorf("invalid egress rule %q: port must be 1-65535", rule)
+		}
+		r.Port = n
+	}
+	return r, nil
+}
+
+// Validate checks the pod security level and egress rules
+func (s *Security) Validate() error {
+	if s == nil {
+		return nil
+	}
+	switch s.PodSecurity {
+	case "", PodSecurityPrivileged, PodSecurityBaseline, PodSecurityRestricted:
+	default:
+		return fmt.Errorf("invalid podSecurity %q (expected privileged, baseline or restricted)", s.PodSecurity)
+	}
+	if s.Egress != nil {
+		for _, rule := range s.Egress.Allow {
+			if _, err := ParseEgressRule(rule); err != nil {
+				return err
+			}
+		}
+		if len(s.Egress.Allow) > 0 && !s.Egress.DefaultDeny {
+			return fmt.Errorf("egress.allow has no effect without egress.defaultDeny")
+		}
+	}
+	return nil
+}
+
+// DenyEgress reports whether egress is denied by default
+func (s *Security) DenyEgress() bool {
+	return s != nil && s.Egress != nil && s.Egress.DefaultDeny
+}
+
+// HostPolicies reports whether the cluster needs host NetworkPolicies
+func (s *Security) HostPolicies() bool {
+	return s != nil && (s.NetworkIsolation || s.DenyEgress())
+}
+
+// EffectivePodSecurity returns the Pod Security Standard the vCluster
+// enforces, or "" if none
+func (s *Security) EffectivePodSecurity() string {
+	if s == nil {
+		return ""
+	}
+	if s.PodSecurity == "" && s.Isolated {
+		return PodSecurityBaseline
+	}
+	return s.PodSecurity
+}
+
+// values returns the chart values implementing the pod security level and
+// isolated mode
+func (s *Security) values() map[string]interface{} {
+	values := make(map[string]interface{})
+	if s == nil {
+		return values
+	}
+	if level := s.EffectivePodSecurity(); level != "" {
+		setValue(values, "policies.podSecurityStandard", level)
+	}
+	if s.Isolated {
+		setValue(values, "policies.resourceQuota.enabled", true)
+		setValue(values, "policies.limitRange.enabled", true)
+		setValue(values, "policies.networkPolicy.enabled", true)
+	}
+	return values
+}
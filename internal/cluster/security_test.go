@@ -0,0 +1,74 @@
+package cluster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEgressRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    EgressRule
+		wantErr bool
+	}{
+		{"10.0.0.0/8", EgressRule{CIDR: "10.0.0.0/8"}, false},
+		{"0.0.0.0/0:443", EgressRule{CIDR: "0.0.0.0/0", Port: 443}, false},
+		{"fd00::/8", EgressRule{CIDR: "fd00::/8"}, false},
+		{"fd00::/8:5432", EgressRule{CIDR: "fd00::/8", Port: 5432}, false},
+		{"10.0.0.1", EgressRule{}, true},
+		{"example.com:443", EgressRule{}, true},
+		{"10.0.0.0/8:0", EgressRule{}, true},
+		{"10.0.0.0/8:https", EgressRule{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseEgressRule(tt.rule)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEgressRule(%q) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseEgressRule(%q) = %+v, want %+v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestSecurityValues(t *testing.T) {
+	tests := []struct {
+		name string
+		sec  *Security
+		want map[string]interface{}
+	}{
+		{"none", nil, map[string]interface{}{}},
+		{"pod security", &Security{PodSecurity: PodSecurityRestricted}, map[string]interface{}{
+			"policies": map[string]interface{}{"podSecurityStandard": "restricted"},
+		}},
+		{"isolated mode defaults to baseline", &Security{Isolated: true}, map[string]interface{}{
+			"policies": map[string]interface{}{
+				"podSecurityStandard": "baseline",
+				"resourceQuota":       map[string]interface{}{"enabled": true},
+				"limitRange":          map[string]interface{}{"enabled": true},
+				"networkPolicy":       map[string]interface{}{"enabled": true},
+			},
+		}},
+		{"network isolation is applied on the host", &Security{NetworkIsolation: true}, map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &CreateOptions{Security: tt.sec}
+			if got := opts.GeneratedValues(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GeneratedValues() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	invalid := []*Security{
+		{PodSecurity: "strict"},
+		{Egress: &Egress{Allow: []string{"10.0.0.0/8"}}},
+		{Egress: &Egress{DefaultDeny: true, Allow: []string{"internal"}}},
+	}
+	for _, sec := range invalid {
+		if err := sec.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", sec)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package cluster
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Values returns the vCluster chart values for the cluster. Later sources
+// override earlier ones, with nested mappings deep-merged:
+//
+//  1. values generated from the cluster's settings (GeneratedValues)
+//  2. ValuesFiles, in order
+//  3. VClusterValues
+func (o *CreateOptions) Values() (map[string]interface{}, error) {
+	values := o.GeneratedValues()
+	for _, path := range o.ValuesFiles {
+		fileValues, err := LoadValuesFile(path)
+		if err != nil {
+			return nil, err
+		}
+		MergeValues(values, fileValues)
+	}
+	MergeValues(values, copyValues(o.VClusterValues))
+	return values, nil
+}
+
+// GeneratedValues returns the chart values ghostctl derives from the
+// cluster's settings. Storage is not among them: it limits the volumes the
+// cluster's workloads claim, not the control plane's own volume.
+func (o *CreateOptions) GeneratedValues() map[string]interface{} {
+	values := make(map[string]interface{})
+	MergeValues(values, o.Security.values())
+	MergeValues(values, o.schedulingValues())
+	return values
+}
+
+// OptionsHash returns a hash of everything the cluster is created with but
+// its name, namespace, TTL and labels, with its values rendered, so that
+// clusters created alike have the same hash even if a values file changed
+// name, and different hashes if it changed content
+func (o *CreateOptions) OptionsHash() (string, error) {
+	values, err := o.Values()
+	if err != nil {
+		return "", err
+	}
+	created := *o
+	created.Name, created.Namespace, created.TTL, created.Labels = "", "", "", nil
+	created.VClusterValues, created.ValuesFiles = values, nil
+	data, err := json.Marshal(created)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash cluster options: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// LoadValuesFile reads a vCluster values file
+func LoadValuesFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read values file: %w", err)
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse values file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// MergeValues deep-merges src into dst. Mappings are merged key by key;
+// any other value in src, including lists, replaces the one in dst.
+func MergeValues(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			MergeValues(dstMap, srcMap)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// setValue sets the value at a dotted path, creating mappings as needed
+func setValue(values map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	m := values
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := m[k].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[k] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = value
+}
+
+// copyValues deep-copies the mappings of values so merging into the copy
+// leaves the original untouched
+func copyValues(values map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = copyValues(nested)
+		}
+		copied[k] = v
+	}
+	return copied
+}
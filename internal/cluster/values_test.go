@@ -0,0 +1,119 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValuesPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	override := filepath.Join(dir, "override.yaml")
+	files := map[string]string{
+		base: `sync:
+  toHost:
+    ingresses:
+      enabled: true
+controlPlane:
+  statefulSet:
+    persistence:
+      volumeClaim:
+        size: 5Gi
+        storageClass: fast
+`,
+		override: "sync:\n  toHost:\n    ingresses:\n      enabled: false\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	inline := map[string]interface{}{
+		"sync":         map[string]interface{}{"toHost": map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}}},
+		"experimental": map[string]interface{}{"isolatedControlPlane": map[string]interface{}{"enabled": true}},
+	}
+	opts := &CreateOptions{Storage: "20Gi", ValuesFiles: []string{base, override}, VClusterValues: inline}
+
+	values, err := opts.Values()
+	if err != nil {
+		t.Fatalf("Values() error = %v", err)
+	}
+	want := map[string]interface{}{
+		"controlPlane": map[string]interface{}{"statefulSet": map[string]interface{}{"persistence": map[string]interface{}{
+			// Storage does not size the control plane's volume; the
+			// values file does
+			"volumeClaim": map[string]interface{}{"size": "5Gi", "storageClass": "fast"},
+		}}},
+		// Inline values win over both files
+		"sync":         map[string]interface{}{"toHost": map[string]interface{}{"ingresses": map[string]interface{}{"enabled": true}}},
+		"experimental": map[string]interface{}{"isolatedControlPlane": map[string]interface{}{"enabled": true}},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("Values() = %v, want %v", values, want)
+	}
+
+	// Merging must not modify the template's own values
+	if _, ok := inline["controlPlane"]; ok {
+		t.Error("Values() modified VClusterValues")
+	}
+
+	if got := (&CreateOptions{Storage: "20Gi"}).GeneratedValues(); len(got) != 0 {
+		t.Errorf("GeneratedValues() with storage = %v, want none", got)
+	}
+
+	opts.ValuesFiles = []string{filepath.Join(dir, "missing.yaml")}
+	if _, err := opts.Values(); err == nil {
+		t.Error("Values() with a missing values file should fail")
+	}
+}
+
+func TestOptionsHash(t *testing.T) {
+	dir := t.TempDir()
+	values := filepath.Join(dir, "values.yaml")
+	moved := filepath.Join(dir, "moved.yaml")
+	for _, path := range []string{values, moved} {
+		if err := os.WriteFile(path, []byte("sync:\n  toHost:\n    ingresses:\n      enabled: true\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash := func(opts *CreateOptions) string {
+		t.Helper()
+		h, err := opts.OptionsHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	base := CreateOptions{CPU: "2", Memory: "4Gi", ValuesFiles: []string{values}}
+	want := hash(&base)
+
+	same := base
+	same.Name, same.Namespace, same.TTL, same.Labels = "other", "ns", "8h", map[string]string{"team": "ml"}
+	same.ValuesFiles = []string{moved}
+	if got := hash(&same); got != want {
+		t.Errorf("OptionsHash() changed with name, namespace, TTL, labels or values file path")
+	}
+
+	for name, change := range map[string]func(o *CreateOptions){
+		"cpu":            func(o *CreateOptions) { o.CPU = "4" },
+		"scheduling":     func(o *CreateOptions) { o.Scheduling.PriorityClassName = "high" },
+		"gpuNodeLabels":  func(o *CreateOptions) { o.GPUNodeLabels = map[string]string{"gpu": "a100"} },
+		"vclusterValues": func(o *CreateOptions) { o.VClusterValues = map[string]interface{}{"x": 1} },
+	} {
+		changed := base
+		change(&changed)
+		if hash(&changed) == want {
+			t.Errorf("OptionsHash() did not change with %s", name)
+		}
+	}
+
+	if err := os.WriteFile(values, []byte("sync: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if hash(&base) == want {
+		t.Errorf("OptionsHash() did not change with the values file's content")
+	}
+}
@@ -15,15 +15,149 @@ const (
 
 // Config represents the ghostctl configuration structure
 type Config struct {
-	APIServer       string            `yaml:"apiServer"`
-	AuthToken       string            `yaml:"authToken"`
-	DefaultTemplate string            `yaml:"defaultTemplate"`
-	DefaultTTL      string            `yaml:"defaultTTL"`
-	Namespace       string            `yaml:"namespace"`
-	LogLevel        string            `yaml:"logLevel"`
-	CloudProvider   string            `yaml:"cloudProvider"`
-	ProjectID       string            `yaml:"projectID"`
-	Metadata        map[string]string `yaml:"metadata"`
+	APIServer         string                       `yaml:"apiServer"`
+	AuthToken         string                       `yaml:"authToken"`
+	DefaultTemplate   string                       `yaml:"defaultTemplate"`
+	DefaultTTL        string                       `yaml:"defaultTTL"`
+	Namespace         string                       `yaml:"namespace"`
+	NamespaceStrategy string                       `yaml:"namespaceStrategy"` // shared (default), per-cluster or per-owner
+	LogLevel          string                       `yaml:"logLevel"`
+	CloudProvider     string                       `yaml:"cloudProvider"`
+	ProjectID         string                       `yaml:"projectID"`
+	Metadata          map[string]string            `yaml:"metadata"`
+	Pricing           Pricing                      `yaml:"pricing"`
+	Budgets           []Budget                     `yaml:"budgets"`
+	Quotas            Quotas                       `yaml:"quotas"`
+	PolicyFile        string                       `yaml:"policyFile"` // default: $HOME/.ghost/policy.yaml
+	Audit             Audit                        `yaml:"audit"`
+	Store             Store                        `yaml:"store"`
+	History           History                      `yaml:"history"`
+	TemplatePath      []string                     `yaml:"templatePath"` // template directories, highest priority first; GHOSTCTL_TEMPLATE_PATH overrides
+	TemplateSources   []TemplateSource             `yaml:"templateSources"`
+	GPUNodeLabels     map[string]map[string]string `yaml:"gpuNodeLabels"` // GPU type -> labels of the host nodes that have it
+	Queue             Queue                        `yaml:"queue"`
+	Pools             []Pool                       `yaml:"pools"`
+}
+
+// Namespace strategies: where on the host each vCluster is created
+const (
+	NamespaceShared     = "shared"      // every cluster in the configured namespace
+	NamespacePerCluster = "per-cluster" // <namespace>-<cluster>
+	NamespacePerOwner   = "per-owner"   // <namespace>-<owner>
+)
+
+// TemplateSource is a remote template catalog, fetched into a local cache.
+// Exactly one of URL and Git is set.
+type TemplateSource struct {
+	Name    string `yaml:"name"`
+	URL     string `yaml:"url"`     // HTTP(S) index listing template files and their sha256 checksums
+	SHA256  string `yaml:"sha256"`  // url: expected checksum of the index itself (optional)
+	Git     string `yaml:"git"`     // repository to clone
+	Ref     string `yaml:"ref"`     // git: branch, tag or commit to check out
+	Path    string `yaml:"path"`    // git: directory holding the templates (default: repository root)
+	Refresh string `yaml:"refresh"` // how often to refresh the cache, e.g. "12h" (default "24h"); "0" refreshes only on 'templates update'
+}
+
+// History configures how long deleted clusters are remembered
+type History struct {
+	Retention string `yaml:"retention"` // e.g. "90d"; empty keeps deleted clusters forever
+}
+
+// Queue configures how 'ghostctl queue run' creates queued clusters
+type Queue struct {
+	Order        string `yaml:"order"`        // fifo (default) or fair-share
+	ReserveAfter string `yaml:"reserveAfter"` // e.g. "1h" (default); how long a request waits for host room before the queue holds for it
+}
+
+// Queue orders: which queued cluster is created first once there is room
+const (
+	QueueFIFO      = "fifo"       // oldest request first
+	QueueFairShare = "fair-share" // owners with the fewest active clusters first, then oldest
+)
+
+// Pool keeps clusters of a template created ahead of time, so that
+// 'ghostctl up' can claim one instead of waiting for a new cluster
+type Pool struct {
+	Template string `yaml:"template"`
+	Size     int    `yaml:"size"` // unclaimed clusters to keep ready
+}
+
+// Pool returns the warm pool of a template, or nil
+func (c *Config) Pool(template string) *Pool {
+	for i := range c.Pools {
+		if c.Pools[i].Template == template {
+			return &c.Pools[i]
+		}
+	}
+	return nil
+}
+
+// Store selects where cluster metadata is kept
+type Store struct {
+	Backend   string `yaml:"backend"`   // file (default), configmap, secret or sqlite
+	Path      string `yaml:"path"`      // file: directory (default $HOME/.ghost); sqlite: database file (default $HOME/.ghost/clusters.db)
+	Namespace string `yaml:"namespace"` // configmap/secret: host namespace (default: namespace)
+	Name      string `yaml:"name"`      // configmap/secret: object name (default "ghostctl-metadata")
+}
+
+// Audit configures the audit log of mutating commands
+type Audit struct {
+	Disabled  bool   `yaml:"disabled"`
+	Path      string `yaml:"path"`      // default: $HOME/.ghost/audit.log
+	HostSink  bool   `yaml:"hostSink"`  // also record entries in a ConfigMap on the host
+	ConfigMap string `yaml:"configMap"` // host ConfigMap name (default "ghostctl-audit")
+	Namespace string `yaml:"namespace"` // host namespace for the ConfigMap (default: namespace)
+}
+
+// Quotas limit what users and teams may run at the same time.
+// Zero values mean no limit.
+type Quotas struct {
+	MaxClustersPerUser int                 `yaml:"maxClustersPerUser"`
+	MaxGPUsPerUser     int                 `yaml:"maxGPUsPerUser"`
+	MaxGPUsPerTeam     int                 `yaml:"maxGPUsPerTeam"`
+	TeamLabel          string              `yaml:"teamLabel"`        // label naming a cluster's team (default "team")
+	MaxTTL             map[string]string   `yaml:"maxTTL"`           // template name -> longest allowed TTL
+	AllowedTemplates   map[string][]string `yaml:"allowedTemplates"` // team -> templates the team may use; "*" for unlisted teams
+}
+
+// TeamLabelKey returns the label that identifies a cluster's team
+func (q Quotas) TeamLabelKey() string {
+	if q.TeamLabel == "" {
+		return "team"
+	}
+	return q.TeamLabel
+}
+
+// Budget limits the cost of clusters created within a daily or monthly window.
+// A budget with neither user nor label set applies to all clusters.
+type Budget struct {
+	Name   string  `yaml:"name"`
+	User   string  `yaml:"user"`   // owner the budget applies to; "*" gives every user their own budget
+	Label  string  `yaml:"label"`  // label the budget applies to, e.g. "team=ml"
+	Window string  `yaml:"window"` // "daily" or "monthly"
+	Limit  float64 `yaml:"limit"`
+}
+
+// Pricing holds the hourly rates used to estimate cluster cost
+type Pricing struct {
+	Currency       string             `yaml:"currency"`       // e.g. "USD"
+	CPUHour        float64            `yaml:"cpuHour"`        // per vCPU-hour
+	MemoryGiBHour  float64            `yaml:"memoryGiBHour"`  // per GiB of memory per hour
+	StorageGiBHour float64            `yaml:"storageGiBHour"` // per GiB of storage per hour
+	GPUHour        map[string]float64 `yaml:"gpuHour"`        // per GPU-hour by type; "default" covers unlisted types
+}
+
+// Configured reports whether any rate is set
+func (p Pricing) Configured() bool {
+	return p.CPUHour > 0 || p.MemoryGiBHour > 0 || p.StorageGiBHour > 0 || len(p.GPUHour) > 0
+}
+
+// GPURate returns the hourly rate for a GPU type, falling back to the "default" rate
+func (p Pricing) GPURate(gpuType string) float64 {
+	if rate, ok := p.GPUHour[gpuType]; ok {
+		return rate
+	}
+	return p.GPUHour["default"]
 }
 
 // GetConfigPath returns the path to the config file
@@ -60,6 +194,18 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	// The required settings default when the file leaves them out
+	defaults := DefaultConfig()
+	if config.APIServer == "" {
+		config.APIServer = defaults.APIServer
+	}
+	if config.Namespace == "" {
+		config.Namespace = defaults.Namespace
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", configPath, err)
+	}
+
 	return config, nil
 }
 
@@ -111,5 +257,31 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("namespace is required")
 	}
 
+	switch c.NamespaceStrategy {
+	case "", NamespaceShared, NamespacePerCluster, NamespacePerOwner:
+	default:
+		return fmt.Errorf("invalid namespaceStrategy %q (expected shared, per-cluster or per-owner)", c.NamespaceStrategy)
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range c.Pools {
+		if p.Template == "" {
+			return fmt.Errorf("pools: template is required")
+		}
+		if seen[p.Template] {
+			return fmt.Errorf("pools: template %q has more than one pool", p.Template)
+		}
+		seen[p.Template] = true
+		if p.Size < 0 {
+			return fmt.Errorf("pools: size of %q must not be negative", p.Template)
+		}
+	}
+
+	switch c.Queue.Order {
+	case "", QueueFIFO, QueueFairShare:
+	default:
+		return fmt.Errorf("invalid queue.order %q (expected fifo or fair-share)", c.Queue.Order)
+	}
+
 	return nil
 }
@@ -42,6 +42,16 @@ func TestValidate(t *testing.T) {
 			&Config{APIServer: "localhost:8080"},
 			true,
 		},
+		{
+			"warm pools",
+			&Config{APIServer: "localhost:8080", Namespace: "default", Pools: []Pool{{Template: "default", Size: 3}, {Template: "pr", Size: 1}}},
+			false,
+		},
+		{
+			"two pools of a template",
+			&Config{APIServer: "localhost:8080", Namespace: "default", Pools: []Pool{{Template: "default", Size: 3}, {Template: "default", Size: 1}}},
+			true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -75,3 +85,77 @@ func TestGetConfigPath(t *testing.T) {
 		t.Errorf("GetConfigPath() path doesn't contain expected directory: %s in %s", expectedDir, path)
 	}
 }
+
+// TestLoadPricing tests loading the pricing table from the config file
+func TestLoadPricing(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	data := `namespace: ghostcluster
+pricing:
+  currency: USD
+  cpuHour: 0.04
+  memoryGiBHour: 0.005
+  gpuHour:
+    nvidia-t4: 0.35
+    default: 1.0
+`
+	if err := os.MkdirAll(filepath.Join(home, ConfigDirName), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ConfigDirName, ConfigFileName), []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() err = %v", err)
+	}
+
+	if cfg.Pricing.CPUHour != 0.04 || cfg.Pricing.MemoryGiBHour != 0.005 {
+		t.Errorf("unexpected pricing rates: %+v", cfg.Pricing)
+	}
+	if cfg.Pricing.GPURate("nvidia-t4") != 0.35 || cfg.Pricing.GPURate("nvidia-a100") != 1.0 {
+		t.Errorf("unexpected GPU rates: %+v", cfg.Pricing.GPUHour)
+	}
+}
+
+// TestLoadValidates tests that Load refuses settings Validate rejects,
+// rather than falling back to defaults
+func TestLoadValidates(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"required settings default", "defaultTTL: 2h\n", ""},
+		{"misspelled queue order", "queue:\n  order: fairshare\n", "queue.order"},
+		{"duplicate pools", "pools:\n  - template: default\n    size: 1\n  - template: default\n    size: 2\n", "more than one pool"},
+		{"negative pool size", "pools:\n  - template: default\n    size: -1\n", "must not be negative"},
+		{"unknown namespace strategy", "namespaceStrategy: per-team\n", "namespaceStrategy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			if err := os.MkdirAll(filepath.Join(home, ConfigDirName), 0700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(home, ConfigDirName, ConfigFileName), []byte(tt.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Load()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() err = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() err = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
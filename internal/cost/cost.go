@@ -0,0 +1,137 @@
+package cost
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+)
+
+const gib = 1024 * 1024 * 1024
+
+// Resources describes the resources a cluster is billed for
+type Resources struct {
+	CPU     string
+	Memory  string
+	Storage string
+	GPU     int
+	GPUType string
+}
+
+// Estimate is an hourly cost broken down by resource
+type Estimate struct {
+	CPU     float64 `json:"cpu"`
+	Memory  float64 `json:"memory"`
+	Storage float64 `json:"storage"`
+	GPU     float64 `json:"gpu"`
+	Total   float64 `json:"total"`
+}
+
+// Hourly estimates the hourly cost of the given resources
+func Hourly(res Resources, pricing config.Pricing) (Estimate, error) {
+	var est Estimate
+
+	if res.CPU != "" {
+		cores, err := utils.ParseCPU(res.CPU)
+		if err != nil {
+			return Estimate{}, err
+		}
+		est.CPU = cores * pricing.CPUHour
+	}
+
+	if res.Memory != "" {
+		bytes, err := utils.ParseMemory(res.Memory)
+		if err != nil {
+			return Estimate{}, err
+		}
+		est.Memory = float64(bytes) / gib * pricing.MemoryGiBHour
+	}
+
+	if res.Storage != "" {
+		bytes, err := utils.ParseMemory(res.Storage)
+		if err != nil {
+			return Estimate{}, fmt.Errorf("invalid storage value: %s", res.Storage)
+		}
+		est.Storage = float64(bytes) / gib * pricing.StorageGiBHour
+	}
+
+	if res.GPU > 0 {
+		est.GPU = float64(res.GPU) * pricing.GPURate(res.GPUType)
+	}
+
+	est.Total = est.CPU + est.Memory + est.Storage + est.GPU
+	return est, nil
+}
+
+// ClusterHourly returns the hourly cost of a cluster. The rate recorded at
+// creation time is preferred so later pricing changes don't rewrite history.
+func ClusterHourly(meta *metadata.ClusterMetadata, pricing config.Pricing) float64 {
+	if meta.HourlyCost > 0 {
+		return meta.HourlyCost
+	}
+
+	est, err := Hourly(Resources{
+		CPU:     meta.CPU,
+		Memory:  meta.Memory,
+		Storage: meta.Storage,
+		GPU:     meta.GPU,
+		GPUType: meta.GPUType,
+	}, pricing)
+	if err != nil {
+		return 0
+	}
+	return est.Total
+}
+
+// Accrued returns the cost a cluster has accrued between from and to.
+// Only the part of the window during which the cluster existed is billed;
+// a zero from means since creation. A claimed pool member is billed from
+// when it was claimed, not for its time waiting in the pool.
+func Accrued(meta *metadata.ClusterMetadata, pricing config.Pricing, from, to time.Time) float64 {
+	start := meta.CreatedAt
+	if meta.ClaimedAt != nil {
+		start = *meta.ClaimedAt
+	}
+	if start.Before(from) {
+		start = from
+	}
+
+	end := to
+	if meta.DeletedAt != nil && meta.DeletedAt.Before(end) {
+		end = *meta.DeletedAt
+	}
+
+	if !end.After(start) {
+		return 0
+	}
+	return ClusterHourly(meta, pricing) * end.Sub(start).Hours()
+}
+
+// Projected returns the cost of running at the given hourly rate for the TTL.
+// An empty or invalid TTL projects no cost.
+func Projected(hourly float64, ttl string) float64 {
+	if ttl == "" {
+		return 0
+	}
+	d, err := utils.ParseDuration(ttl)
+	if err != nil {
+		return 0
+	}
+	return hourly * d.Hours()
+}
+
+// Format renders an amount in the configured currency
+func Format(amount float64, pricing config.Pricing) string {
+	switch pricing.Currency {
+	case "", "USD":
+		return fmt.Sprintf("$%.2f", amount)
+	case "EUR":
+		return fmt.Sprintf("€%.2f", amount)
+	case "GBP":
+		return fmt.Sprintf("£%.2f", amount)
+	default:
+		return fmt.Sprintf("%.2f %s", amount, pricing.Currency)
+	}
+}
@@ -0,0 +1,126 @@
+package cost
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+)
+
+var testPricing = config.Pricing{
+	CPUHour:        0.04,
+	MemoryGiBHour:  0.005,
+	StorageGiBHour: 0.0001,
+	GPUHour:        map[string]float64{"nvidia-a100": 3.00, "default": 0.35},
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// TestHourly tests hourly cost estimation
+func TestHourly(t *testing.T) {
+	est, err := Hourly(Resources{CPU: "4", Memory: "16Gi", Storage: "50Gi", GPU: 2, GPUType: "nvidia-t4"}, testPricing)
+	if err != nil {
+		t.Fatalf("Hourly() err = %v", err)
+	}
+
+	want := 4*0.04 + 16*0.005 + 50*0.0001 + 2*0.35
+	if !almostEqual(est.Total, want) {
+		t.Errorf("Hourly() total = %v, want %v", est.Total, want)
+	}
+	if !almostEqual(est.GPU, 0.70) {
+		t.Errorf("Hourly() GPU = %v, want 0.70 (default GPU rate)", est.GPU)
+	}
+
+	if _, err := Hourly(Resources{CPU: "lots"}, testPricing); err == nil {
+		t.Error("Hourly() expected error for invalid CPU")
+	}
+}
+
+// TestAccrued tests accrued cost over clusters' lifetimes and windows
+func TestAccrued(t *testing.T) {
+	created := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	deleted := created.Add(3 * time.Hour)
+	meta := &metadata.ClusterMetadata{Name: "pr-1", CreatedAt: created, HourlyCost: 2.0, DeletedAt: &deleted}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     float64
+	}{
+		{"whole lifetime", time.Time{}, created.Add(10 * time.Hour), 6.0},
+		{"window start", created.Add(time.Hour), created.Add(10 * time.Hour), 4.0},
+		{"window end", time.Time{}, created.Add(30 * time.Minute), 1.0},
+		{"after deletion", deleted.Add(time.Hour), deleted.Add(2 * time.Hour), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Accrued(meta, testPricing, tt.from, tt.to); !almostEqual(got, tt.want) {
+				t.Errorf("Accrued() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A claimed pool member is billed from the claim
+	claimed := created.Add(2 * time.Hour)
+	meta.ClaimedAt = &claimed
+	if got := Accrued(meta, testPricing, time.Time{}, deleted); !almostEqual(got, 2.0) {
+		t.Errorf("Accrued() of a claimed pool member = %v, want 2.0", got)
+	}
+}
+
+// TestProjected tests cost projection over a TTL
+func TestProjected(t *testing.T) {
+	if got := Projected(1.5, "2h"); !almostEqual(got, 3.0) {
+		t.Errorf("Projected() = %v, want 3.0", got)
+	}
+	if got := Projected(1.5, ""); got != 0 {
+		t.Errorf("Projected() without TTL = %v, want 0", got)
+	}
+}
+
+// TestReport tests grouping active and deleted clusters
+func TestReport(t *testing.T) {
+	now := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	deletedAt := now.Add(-2 * time.Hour)
+	longAgo := now.Add(-72 * time.Hour)
+
+	clusters := []*metadata.ClusterMetadata{
+		{Name: "a", Template: "gpu", CreatedAt: now.Add(-4 * time.Hour), HourlyCost: 1.0, Labels: map[string]string{"team": "ml"}},
+		{Name: "b", Template: "gpu", CreatedAt: now.Add(-4 * time.Hour), HourlyCost: 1.0, DeletedAt: &deletedAt, Labels: map[string]string{"team": "ml"}},
+		{Name: "c", Template: "default", CreatedAt: now.Add(-1 * time.Hour), HourlyCost: 0.5},
+		{Name: "d", Template: "default", CreatedAt: longAgo.Add(-time.Hour), HourlyCost: 0.5, DeletedAt: &longAgo},
+	}
+
+	report, err := Report(clusters, testPricing, "template", now.Add(-24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("Report() err = %v", err)
+	}
+
+	if len(report) != 2 {
+		t.Fatalf("Report() returned %d groups, want 2", len(report))
+	}
+	gpu := report[0]
+	if gpu.Group != "gpu" || gpu.Clusters != 2 || gpu.Active != 1 || !almostEqual(gpu.Accrued, 6.0) || !almostEqual(gpu.Hourly, 1.0) {
+		t.Errorf("unexpected gpu group: %+v", gpu)
+	}
+	if def := report[1]; def.Clusters != 1 || !almostEqual(def.Accrued, 0.5) {
+		t.Errorf("unexpected default group: %+v", def)
+	}
+
+	byLabel, err := Report(clusters, testPricing, "label:team", now.Add(-24*time.Hour), now)
+	if err != nil {
+		t.Fatalf("Report() err = %v", err)
+	}
+	if byLabel[0].Group != "ml" || byLabel[1].Group != Ungrouped {
+		t.Errorf("unexpected label groups: %+v", byLabel)
+	}
+
+	if _, err := Report(clusters, testPricing, "color", now, now); err == nil {
+		t.Error("Report() expected error for unsupported grouping")
+	}
+}
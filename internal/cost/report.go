@@ -0,0 +1,92 @@
+package cost
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+)
+
+// Ungrouped is the group name for clusters without a value for the group key
+const Ungrouped = "(none)"
+
+// GroupCost is the cost of a group of clusters over a reporting window
+type GroupCost struct {
+	Group    string  `json:"group"`
+	Clusters int     `json:"clusters"`
+	Active   int     `json:"active"`
+	Hourly   float64 `json:"hourly"`
+	Accrued  float64 `json:"accrued"`
+}
+
+// GroupKey returns the group a cluster belongs to. groupBy is one of
+// "template", "owner", "cluster" or "label:<key>".
+func GroupKey(meta *metadata.ClusterMetadata, groupBy string) (string, error) {
+	var key string
+	switch {
+	case groupBy == "template":
+		key = meta.Template
+	case groupBy == "owner":
+		key = meta.OwnerName()
+	case groupBy == "cluster":
+		key = meta.Name
+	case strings.HasPrefix(groupBy, "label:"):
+		key = meta.Labels[strings.TrimPrefix(groupBy, "label:")]
+	default:
+		return "", fmt.Errorf("unsupported grouping: %s (supported: template, owner, cluster, label:<key>)", groupBy)
+	}
+
+	if key == "" {
+		return Ungrouped, nil
+	}
+	return key, nil
+}
+
+// Report groups clusters and totals their cost between from and to.
+// Deleted clusters are billed up to their deletion time; clusters that did
+// not exist during the window are left out.
+func Report(clusters []*metadata.ClusterMetadata, pricing config.Pricing, groupBy string, from, to time.Time) ([]GroupCost, error) {
+	groups := make(map[string]*GroupCost)
+	for _, meta := range clusters {
+		if meta.DeletedAt != nil && meta.DeletedAt.Before(from) {
+			continue
+		}
+		if meta.CreatedAt.After(to) {
+			continue
+		}
+
+		key, err := GroupKey(meta, groupBy)
+		if err != nil {
+			return nil, err
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &GroupCost{Group: key}
+			groups[key] = group
+		}
+
+		group.Clusters++
+		if meta.DeletedAt == nil {
+			group.Active++
+			group.Hourly += ClusterHourly(meta, pricing)
+		}
+		group.Accrued += Accrued(meta, pricing, from, to)
+	}
+
+	result := make([]GroupCost, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Accrued != result[j].Accrued {
+			return result[i].Accrued > result[j].Accrued
+		}
+		return result[i].Group < result[j].Group
+	})
+
+	return result, nil
+}
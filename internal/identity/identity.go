@@ -0,0 +1,100 @@
+package identity
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+)
+
+// Identity describes who is running ghostctl
+type Identity struct {
+	User     string // OS user
+	Email    string // git user.email, if configured
+	Hostname string
+	CIActor  string // user that triggered the CI job, if running in CI
+}
+
+// Name returns the identity recorded as a cluster's owner: the CI actor
+// when running in CI, otherwise the git email, otherwise the OS user
+func (id Identity) Name() string {
+	switch {
+	case id.CIActor != "":
+		return id.CIActor
+	case id.Email != "":
+		return id.Email
+	default:
+		return id.User
+	}
+}
+
+// Current returns the identity of the user running ghostctl
+func Current() Identity {
+	id := Identity{
+		User:    osUser(),
+		Email:   gitEmail(),
+		CIActor: ciActor(),
+	}
+	if host, err := os.Hostname(); err == nil {
+		id.Hostname = host
+	}
+	return id
+}
+
+// CurrentUser returns the name of the user running ghostctl
+func CurrentUser() string {
+	return Current().Name()
+}
+
+func osUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+func gitEmail() string {
+	output, err := exec.Command("git", "config", "--get", "user.email").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// ciActorEnvVars are environment variables naming the user behind a CI job
+var ciActorEnvVars = []string{
+	"GITHUB_ACTOR",            // GitHub Actions
+	"GITLAB_USER_LOGIN",       // GitLab CI
+	"BUILDKITE_BUILD_CREATOR", // Buildkite
+	"CIRCLE_USERNAME",         // CircleCI
+	"BUILD_REQUESTEDFOR",      // Azure Pipelines
+	"BUILD_USER_ID",           // Jenkins (build-user-vars plugin)
+}
+
+func ciActor() string {
+	if !InCI() {
+		return ""
+	}
+	for _, name := range ciActorEnvVars {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// ciEnvVars are environment variables set by common CI systems
+var ciEnvVars = []string{"CI", "GITHUB_ACTIONS", "GITLAB_CI", "BUILDKITE", "JENKINS_URL", "CIRCLECI", "TF_BUILD"}
+
+// InCI reports whether ghostctl is running in a CI system
+func InCI() bool {
+	for _, name := range ciEnvVars {
+		if value := os.Getenv(name); value != "" && value != "false" && value != "0" {
+			return true
+		}
+	}
+	return false
+}
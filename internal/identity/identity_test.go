@@ -0,0 +1,36 @@
+package identity
+
+import "testing"
+
+func TestIdentityName(t *testing.T) {
+	tests := []struct {
+		name string
+		id   Identity
+		want string
+	}{
+		{"ci actor wins", Identity{User: "runner", Email: "ci@example.com", CIActor: "octocat"}, "octocat"},
+		{"email over os user", Identity{User: "alice", Email: "alice@example.com"}, "alice@example.com"},
+		{"os user fallback", Identity{User: "alice"}, "alice"},
+	}
+	for _, tt := range tests {
+		if got := tt.id.Name(); got != tt.want {
+			t.Errorf("%s: Name() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCIActorOutsideCI(t *testing.T) {
+	for _, name := range ciEnvVars {
+		t.Setenv(name, "")
+	}
+	t.Setenv("GITHUB_ACTOR", "octocat")
+
+	if got := ciActor(); got != "" {
+		t.Errorf("ciActor() = %q outside CI, want empty", got)
+	}
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	if got := ciActor(); got != "octocat" {
+		t.Errorf("ciActor() = %q in CI, want %q", got, "octocat")
+	}
+}
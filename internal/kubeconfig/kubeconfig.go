@@ -14,7 +14,7 @@ import (
 
 // Manager manages kubeconfig files for vClusters
 type Manager struct {
-	metaStore *metadata.Store
+	metaStore metadata.Store
 }
 
 // NewManager creates a new kubeconfig manager
@@ -0,0 +1,221 @@
+package metadata
+
+import (
+	"fmt"
+	"time"
+)
+
+// documentBackend loads and saves the whole metadata document. Backends
+// that store a single document implement the Store operations through
+// docStore.
+type documentBackend interface {
+	// view runs fn with the current document
+	view(fn func(doc *document) error) error
+	// update runs fn with the current document and saves it if fn succeeds.
+	// Concurrent updates must not be lost.
+	update(fn func(doc *document) error) error
+}
+
+// docStore implements Store on top of a documentBackend
+type docStore struct {
+	backend documentBackend
+}
+
+// Add adds a new cluster to the store
+func (s *docStore) Add(meta *ClusterMetadata) error {
+	return s.backend.update(func(doc *document) error {
+		meta.CreatedAt = time.Now()
+		doc.Clusters[meta.Name] = meta
+		return nil
+	})
+}
+
+// Get retrieves a cluster from the store
+func (s *docStore) Get(name string) (*ClusterMetadata, error) {
+	var meta *ClusterMetadata
+	err := s.backend.view(func(doc *document) error {
+		var exists bool
+		meta, exists = doc.Clusters[name]
+		if !exists {
+			return fmt.Errorf("cluster not found: %s", name)
+		}
+		return nil
+	})
+	return meta, err
+}
+
+// Remove removes a cluster from the store
+func (s *docStore) Remove(name string) error {
+	return s.backend.update(func(doc *document) error {
+		if _, exists := doc.Clusters[name]; !exists {
+			return fmt.Errorf("cluster not found: %s", name)
+		}
+		delete(doc.Clusters, name)
+		return nil
+	})
+}
+
+// List returns all clusters from the store
+func (s *docStore) List() ([]*ClusterMetadata, error) {
+	var result []*ClusterMetadata
+	err := s.backend.view(func(doc *document) error {
+		for _, meta := range doc.Clusters {
+			result = append(result, meta)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Exists checks if a cluster exists in the store
+func (s *docStore) Exists(name string) bool {
+	_, err := s.Get(name)
+	return err == nil
+}
+
+// RecordEvent appends an event to an active cluster's history
+func (s *docStore) RecordEvent(name string, event Event) error {
+	return s.backend.update(func(doc *document) error {
+		meta, exists := doc.Clusters[name]
+		if !exists {
+			return fmt.Errorf("cluster not found: %s", name)
+		}
+		meta.Events = append(meta.Events, event)
+		return nil
+	})
+}
+
+// Archive removes a cluster from the active store and records it in the
+// deleted-cluster history, so its cost can still be reported
+func (s *docStore) Archive(name string, deleted Event) error {
+	return s.backend.update(func(doc *document) error {
+		meta, exists := doc.Clusters[name]
+		if !exists {
+			return fmt.Errorf("cluster not found: %s", name)
+		}
+		deletedAt := deleted.Time
+		meta.DeletedAt = &deletedAt
+		meta.Events = append(meta.Events, deleted)
+		doc.Deleted = append(doc.Deleted, meta)
+		delete(doc.Clusters, name)
+		return nil
+	})
+}
+
+// ListDeleted returns the recorded history of deleted clusters
+func (s *docStore) ListDeleted() ([]*ClusterMetadata, error) {
+	var deleted []*ClusterMetadata
+	err := s.backend.view(func(doc *document) error {
+		deleted = doc.Deleted
+		return nil
+	})
+	return deleted, err
+}
+
+// Prune drops deleted clusters deleted before the given time
+func (s *docStore) Prune(before time.Time) (int, error) {
+	pruned := 0
+	err := s.backend.update(func(doc *document) error {
+		kept := doc.Deleted[:0]
+		for _, meta := range doc.Deleted {
+			if meta.DeletedAt != nil && meta.DeletedAt.Before(before) {
+				pruned++
+				continue
+			}
+			kept = append(kept, meta)
+		}
+		doc.Deleted = kept
+		return nil
+	})
+	return pruned, err
+}
+
+// Import copies records into the store verbatim
+func (s *docStore) Import(clusters, deleted []*ClusterMetadata, queue []*QueuedCluster) error {
+	return s.backend.update(func(doc *document) error {
+		for _, meta := range clusters {
+			doc.Clusters[meta.Name] = meta
+		}
+		doc.Deleted = append(doc.Deleted, deleted...)
+		for _, req := range queue {
+			if i := queueIndex(doc.Queue, req.Name); i >= 0 {
+				doc.Queue[i] = req
+			} else {
+				doc.Queue = append(doc.Queue, req)
+			}
+		}
+		return nil
+	})
+}
+
+// Rename replaces the record of an active cluster with meta
+func (s *docStore) Rename(oldName string, meta *ClusterMetadata) error {
+	return s.backend.update(func(doc *document) error {
+		if _, exists := doc.Clusters[oldName]; !exists {
+			return fmt.Errorf("cluster not found: %s", oldName)
+		}
+		if _, taken := doc.Clusters[meta.Name]; taken && meta.Name != oldName {
+			return fmt.Errorf("cluster %q already exists", meta.Name)
+		}
+		delete(doc.Clusters, oldName)
+		doc.Clusters[meta.Name] = meta
+		return nil
+	})
+}
+
+// Enqueue appends a request to the creation queue
+func (s *docStore) Enqueue(req *QueuedCluster) error {
+	return s.backend.update(func(doc *document) error {
+		if queueIndex(doc.Queue, req.Name) >= 0 {
+			return fmt.Errorf("cluster %q is already queued", req.Name)
+		}
+		req.QueuedAt = time.Now()
+		doc.Queue = append(doc.Queue, req)
+		return nil
+	})
+}
+
+// ListQueue returns the queued requests, oldest first
+func (s *docStore) ListQueue() ([]*QueuedCluster, error) {
+	var queue []*QueuedCluster
+	err := s.backend.view(func(doc *document) error {
+		queue = doc.Queue
+		return nil
+	})
+	return queue, err
+}
+
+// SetQueuePhase moves a queued request from one phase to another
+func (s *docStore) SetQueuePhase(name, from, to, reason string) (bool, error) {
+	ok := false
+	err := s.backend.update(func(doc *document) error {
+		i := queueIndex(doc.Queue, name)
+		if i < 0 {
+			return fmt.Errorf("cluster %q is not queued", name)
+		}
+		if doc.Queue[i].Phase != from {
+			return nil
+		}
+		if from != to {
+			now := time.Now()
+			doc.Queue[i].PhaseSince = &now
+		}
+		doc.Queue[i].Phase = to
+		doc.Queue[i].Reason = reason
+		ok = true
+		return nil
+	})
+	return ok, err
+}
+
+// Dequeue removes a request from the queue
+func (s *docStore) Dequeue(name string) error {
+	return s.backend.update(func(doc *document) error {
+		i := queueIndex(doc.Queue, name)
+		if i < 0 {
+			return fmt.Errorf("cluster %q is not queued", name)
+		}
+		doc.Queue = append(doc.Queue[:i], doc.Queue[i+1:]...)
+		return nil
+	})
+}
@@ -0,0 +1,41 @@
+package metadata
+
+import "time"
+
+// Lifecycle event types recorded in a cluster's history
+const (
+	EventQueued    = "queued"
+	EventCreated   = "created"
+	EventReady     = "ready"
+	EventClaimed   = "claimed"
+	EventConnected = "connected"
+	EventFailed    = "failed"
+	EventDeleted   = "deleted"
+)
+
+// Event is one entry in a cluster's lifecycle history
+type Event struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	User       string    `json:"user,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	DurationMs int64     `json:"durationMs,omitempty"` // e.g. how long the cluster took to become ready
+}
+
+// NewEvent returns an event of the given type happening now
+func NewEvent(eventType, user, message string) Event {
+	return Event{Type: eventType, Time: time.Now(), User: user, Message: message}
+}
+
+// Duration returns the duration recorded with the event
+func (e Event) Duration() time.Duration {
+	return time.Duration(e.DurationMs) * time.Millisecond
+}
+
+// LastEvent returns the most recent event in the cluster's history
+func (m *ClusterMetadata) LastEvent() (Event, bool) {
+	if len(m.Events) == 0 {
+		return Event{}, false
+	}
+	return m.Events[len(m.Events)-1], true
+}
@@ -0,0 +1,183 @@
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+)
+
+// fileBackend keeps the metadata document in clusters.json on local disk.
+// All access goes through an advisory lock on clusters.json.lock, so
+// concurrent ghostctl processes never lose each other's updates, and every
+// write replaces the file atomically.
+type fileBackend struct {
+	dir      string
+	path     string
+	lockPath string
+}
+
+// NewFileStore creates a metadata store kept in dir
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+	path := filepath.Join(dir, ClustersFileName)
+	return &docStore{backend: &fileBackend{dir: dir, path: path, lockPath: path + LockSuffix}}, nil
+}
+
+func (b *fileBackend) view(fn func(doc *document) error) error {
+	return b.withLock(func() error {
+		doc, err := b.load()
+		if err != nil {
+			return err
+		}
+		return fn(doc)
+	})
+}
+
+func (b *fileBackend) update(fn func(doc *document) error) error {
+	return b.withLock(func() error {
+		doc, err := b.load()
+		if err != nil {
+			return err
+		}
+
+		var previous []byte
+		if doc.existed {
+			if previous, err = doc.encode(); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(doc); err != nil {
+			return err
+		}
+		return b.save(doc, previous)
+	})
+}
+
+// withLock runs fn while holding the store's exclusive advisory lock
+func (b *fileBackend) withLock(fn func() error) error {
+	unlock, err := acquireLock(b.lockPath, lockTimeout)
+	if err != nil {
+		if errors.Is(err, ErrLocked) {
+			return fmt.Errorf("%w (%s)", ErrLocked, b.lockPath)
+		}
+		return fmt.Errorf("failed to lock metadata store: %w", err)
+	}
+	defer unlock()
+	return fn()
+}
+
+func (b *fileBackend) backupPath() string {
+	return b.path + BackupSuffix
+}
+
+// load reads the store, recovering from the backup if clusters.json is
+// corrupt. Must be called with the store lock held.
+func (b *fileBackend) load() (*document, error) {
+	data, err := os.ReadFile(b.path)
+	if os.IsNotExist(err) {
+		// Nothing written yet, but a v1 deleted.json may still need migrating
+		doc, err := decodeDocument([]byte("{}"), b.dir)
+		if err != nil {
+			return nil, err
+		}
+		doc.existed = false
+		return doc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata store: %w", err)
+	}
+
+	doc, err := decodeDocument(data, b.dir)
+	if err == nil {
+		return doc, nil
+	}
+	if isNewerSchema(data) {
+		return nil, err
+	}
+	decodeErr := err
+
+	backup, err := os.ReadFile(b.backupPath())
+	if err != nil {
+		return nil, fmt.Errorf("metadata store %s is corrupt and no backup is available: %w", b.path, decodeErr)
+	}
+	doc, err = decodeDocument(backup, b.dir)
+	if err != nil {
+		return nil, fmt.Errorf("metadata store %s and its backup are both corrupt: %w", b.path, decodeErr)
+	}
+
+	telemetry.GetLogger().Warn("Metadata store was corrupt; restored from backup",
+		"path", b.path, "backup", b.backupPath(), "error", decodeErr)
+
+	// Keep the corrupt file for inspection and put the backup in its place
+	_ = os.Rename(b.path, b.path+".corrupt")
+	if err := writeFileAtomic(b.path, backup, 0600); err != nil {
+		return nil, fmt.Errorf("failed to restore metadata store from backup: %w", err)
+	}
+	return doc, nil
+}
+
+// save writes the document atomically. The previous contents, if any, are
+// kept as the backup used for recovery. Must be called with the store lock held.
+func (b *fileBackend) save(doc *document, previous []byte) error {
+	data, err := doc.encode()
+	if err != nil {
+		return err
+	}
+
+	if previous != nil {
+		if err := writeFileAtomic(b.backupPath(), previous, 0600); err != nil {
+			return fmt.Errorf("failed to write metadata backup: %w", err)
+		}
+	}
+
+	if err := writeFileAtomic(b.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write metadata store: %w", err)
+	}
+
+	for _, path := range doc.legacyFiles {
+		_ = os.Rename(path, path+".migrated")
+	}
+	doc.legacyFiles = nil
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	cleanup := func(err error) error {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return cleanup(err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
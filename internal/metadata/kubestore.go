@@ -0,0 +1,248 @@
+package metadata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+)
+
+const (
+	// DefaultKubeStoreName is the ConfigMap or Secret holding shared metadata
+	DefaultKubeStoreName = "ghostctl-metadata"
+
+	// kubeStoreKey is the data key holding the metadata document
+	kubeStoreKey = "clusters.json"
+
+	// maxConflictRetries bounds how often an update is retried after losing
+	// a race with another writer
+	maxConflictRetries = 5
+)
+
+// maxKubeDocumentBytes bounds the stored document, leaving room for the
+// object's metadata below the 1MiB limit on Kubernetes objects. A variable
+// so tests can lower it.
+var maxKubeDocumentBytes = 960 * 1024
+
+// runKubectl runs kubectl with input on stdin, replaceable in tests
+var runKubectl = func(input string, args ...string) (*shell.CommandResult, error) {
+	if !shell.CommandExists("kubectl") {
+		return nil, fmt.Errorf("kubectl not found in PATH")
+	}
+	return shell.ExecuteCommandSplit(input, "kubectl", args...)
+}
+
+// kubeBackend keeps the metadata document in a ConfigMap or Secret in the
+// host namespace, so everyone using the host shares the same view. Updates
+// use optimistic concurrency on the object's resourceVersion: a write that
+// lost a race is retried against the fresh object.
+type kubeBackend struct {
+	kind      string // BackendConfigMap or BackendSecret
+	namespace string
+	name      string
+}
+
+// NewKubeStore creates a metadata store kept in a ConfigMap or Secret
+func NewKubeStore(kind, namespace, name string) Store {
+	if name == "" {
+		name = DefaultKubeStoreName
+	}
+	return &docStore{backend: &kubeBackend{kind: kind, namespace: namespace, name: name}}
+}
+
+// kubeObject is the subset of a ConfigMap or Secret used by the store
+type kubeObject struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Type       string            `json:"type,omitempty"`
+	Metadata   kubeObjectMeta    `json:"metadata"`
+	Data       map[string]string `json:"data,omitempty"`
+}
+
+type kubeObjectMeta struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+}
+
+func (b *kubeBackend) description() string {
+	return fmt.Sprintf("%s %s/%s", b.kind, b.namespace, b.name)
+}
+
+// get fetches the document and the resourceVersion it was read at. The
+// resourceVersion is empty if the object does not exist yet.
+func (b *kubeBackend) get() (*document, string, error) {
+	result, err := runKubectl("", "get", b.kind, b.name, "-n", b.namespace, "-o", "json")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", b.description(), err)
+	}
+	if result.ExitCode != 0 {
+		if strings.Contains(result.Stderr, "NotFound") {
+			return newDocument(), "", nil
+		}
+		return nil, "", fmt.Errorf("failed to read %s (exit code %d): %s", b.description(), result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+
+	var obj kubeObject
+	if err := json.Unmarshal([]byte(result.Stdout), &obj); err != nil {
+		return nil, "", fmt.Errorf("failed to parse %s: %w", b.description(), err)
+	}
+
+	data, ok := obj.Data[kubeStoreKey]
+	if !ok {
+		return newDocument(), obj.Metadata.ResourceVersion, nil
+	}
+	if b.kind == BackendSecret {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to decode %s: %w", b.description(), err)
+		}
+		data = string(decoded)
+	}
+
+	doc, err := decodeDocument([]byte(data), "")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse %s: %w", b.description(), err)
+	}
+	return doc, obj.Metadata.ResourceVersion, nil
+}
+
+// put writes the document. It creates the object when resourceVersion is
+// empty and otherwise replaces it only if it is unchanged. conflict is true
+// if another writer got there first.
+func (b *kubeBackend) put(doc *document, resourceVersion string) (conflict bool, err error) {
+	value, err := b.encode(doc)
+	if err != nil {
+		return false, err
+	}
+
+	obj := kubeObject{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata: kubeObjectMeta{
+			Name:            b.name,
+			Namespace:       b.namespace,
+			ResourceVersion: resourceVersion,
+			Labels:          map[string]string{"app.kubernetes.io/managed-by": "ghostctl"},
+		},
+		Data: map[string]string{kubeStoreKey: value},
+	}
+	if b.kind == BackendSecret {
+		obj.Kind = "Secret"
+		obj.Type = "Opaque"
+	}
+
+	manifest, err := json.Marshal(obj)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal %s: %w", b.description(), err)
+	}
+
+	verb := "replace"
+	if resourceVersion == "" {
+		verb = "create"
+	}
+	result, err := runKubectl(string(manifest), verb, "-f", "-")
+	if err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", b.description(), err)
+	}
+	if result.ExitCode != 0 {
+		output := result.Stderr
+		if strings.Contains(output, "AlreadyExists") || strings.Contains(output, "Conflict") ||
+			strings.Contains(output, "the object has been modified") {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to write %s (exit code %d): %s", b.description(), result.ExitCode, strings.TrimSpace(output))
+	}
+	return false, nil
+}
+
+// encode returns the document as stored in the object's data. The oldest
+// deleted-cluster history is dropped until it fits in the object; active
+// clusters and the queue never are.
+func (b *kubeBackend) encode(doc *document) (string, error) {
+	for {
+		data, err := doc.encode()
+		if err != nil {
+			return "", err
+		}
+		value := string(data)
+		if b.kind == BackendSecret {
+			value = base64.StdEncoding.EncodeToString(data)
+		}
+		if len(value) <= maxKubeDocumentBytes {
+			return value, nil
+		}
+		if len(doc.Deleted) == 0 {
+			return "", fmt.Errorf("%s cannot hold the metadata of %d clusters (%d bytes, limit %d); use the sqlite backend",
+				b.description(), len(doc.Clusters), len(value), maxKubeDocumentBytes)
+		}
+		// Drop a tenth of the history at a time rather than re-encoding
+		// the document for every record
+		doc.Deleted = dropOldest(doc.Deleted, len(doc.Deleted)/10+1)
+	}
+}
+
+// dropOldest removes the n clusters deleted longest ago from the history,
+// keeping the order of the rest
+func dropOldest(deleted []*ClusterMetadata, n int) []*ClusterMetadata {
+	order := make([]int, len(deleted))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return deletedAt(deleted[order[i]]).Before(deletedAt(deleted[order[j]]))
+	})
+	drop := make(map[int]bool, n)
+	for _, i := range order[:n] {
+		drop[i] = true
+	}
+
+	kept := make([]*ClusterMetadata, 0, len(deleted)-n)
+	for i, meta := range deleted {
+		if !drop[i] {
+			kept = append(kept, meta)
+		}
+	}
+	return kept
+}
+
+// deletedAt returns when a cluster in the history was deleted
+func deletedAt(meta *ClusterMetadata) time.Time {
+	if meta.DeletedAt == nil {
+		return meta.CreatedAt
+	}
+	return *meta.DeletedAt
+}
+
+func (b *kubeBackend) view(fn func(doc *document) error) error {
+	doc, _, err := b.get()
+	if err != nil {
+		return err
+	}
+	return fn(doc)
+}
+
+func (b *kubeBackend) update(fn func(doc *document) error) error {
+	for attempt := 0; attempt < maxConflictRetries; attempt++ {
+		doc, resourceVersion, err := b.get()
+		if err != nil {
+			return err
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+		conflict, err := b.put(doc, resourceVersion)
+		if err != nil {
+			return err
+		}
+		if !conflict {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to update %s: too many concurrent writers, try again", b.description())
+}
@@ -0,0 +1,149 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+)
+
+// fakeKubeAPI stores objects in memory and enforces resourceVersion checks
+// like the API server
+type fakeKubeAPI struct {
+	objects map[string]kubeObject
+	version int
+	// beforeReplace runs before each replace, to simulate a racing writer
+	beforeReplace func()
+}
+
+func (f *fakeKubeAPI) run(input string, args ...string) (*shell.CommandResult, error) {
+	switch args[0] {
+	case "get":
+		obj, ok := f.objects[args[1]+"/"+args[2]]
+		if !ok {
+			return &shell.CommandResult{ExitCode: 1, Stderr: "Error from server (NotFound): not found"}, nil
+		}
+		data, _ := json.Marshal(obj)
+		return &shell.CommandResult{Stdout: string(data)}, nil
+	case "create", "replace":
+		var obj kubeObject
+		if err := json.Unmarshal([]byte(input), &obj); err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(obj.Kind) + "/" + obj.Metadata.Name
+		if args[0] == "replace" && f.beforeReplace != nil {
+			f.beforeReplace()
+		}
+		current, exists := f.objects[key]
+		switch {
+		case args[0] == "create" && exists:
+			return &shell.CommandResult{ExitCode: 1, Stderr: "Error from server (AlreadyExists)"}, nil
+		case args[0] == "replace" && current.Metadata.ResourceVersion != obj.Metadata.ResourceVersion:
+			return &shell.CommandResult{ExitCode: 1, Stderr: "Error from server (Conflict): the object has been modified"}, nil
+		}
+		f.version++
+		obj.Metadata.ResourceVersion = fmt.Sprint(f.version)
+		f.objects[key] = obj
+		return &shell.CommandResult{}, nil
+	}
+	return nil, fmt.Errorf("unexpected kubectl call %v", args)
+}
+
+func withFakeKubeAPI(t *testing.T) *fakeKubeAPI {
+	t.Helper()
+	api := &fakeKubeAPI{objects: make(map[string]kubeObject)}
+	saved := runKubectl
+	runKubectl = api.run
+	t.Cleanup(func() { runKubectl = saved })
+	return api
+}
+
+func TestKubeStoreLifecycle(t *testing.T) {
+	for _, kind := range []string{BackendConfigMap, BackendSecret} {
+		t.Run(kind, func(t *testing.T) {
+			withFakeKubeAPI(t)
+			testStoreLifecycle(t, NewKubeStore(kind, "ghostcluster", ""))
+		})
+	}
+}
+
+func TestKubeStoreRetriesConflicts(t *testing.T) {
+	api := withFakeKubeAPI(t)
+	store := NewKubeStore(BackendConfigMap, "ghostcluster", "")
+	other := NewKubeStore(BackendConfigMap, "ghostcluster", "")
+
+	if err := store.Add(&ClusterMetadata{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another writer sneaks in between our read and our write, once
+	raced := false
+	api.beforeReplace = func() {
+		if raced {
+			return
+		}
+		raced = true
+		api.beforeReplace = nil
+		if err := other.Add(&ClusterMetadata{Name: "b"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.Add(&ClusterMetadata{Name: "c"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	clusters, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 3 {
+		t.Errorf("List() returned %d clusters, want 3: a concurrent update was lost", len(clusters))
+	}
+}
+
+func TestKubeStoreDropsHistoryToFit(t *testing.T) {
+	for _, kind := range []string{BackendConfigMap, BackendSecret} {
+		t.Run(kind, func(t *testing.T) {
+			withFakeKubeAPI(t)
+			saved := maxKubeDocumentBytes
+			maxKubeDocumentBytes = 2000
+			t.Cleanup(func() { maxKubeDocumentBytes = saved })
+
+			store := NewKubeStore(kind, "ghostcluster", "")
+			for i := 0; i < 20; i++ {
+				name := fmt.Sprintf("pr-%d", i)
+				if err := store.Add(&ClusterMetadata{Name: name}); err != nil {
+					t.Fatalf("Add(%s) error = %v", name, err)
+				}
+				if err := store.Archive(name, NewEvent(EventDeleted, "bob", "")); err != nil {
+					t.Fatalf("Archive(%s) error = %v", name, err)
+				}
+			}
+
+			deleted, err := store.ListDeleted()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(deleted) == 0 || len(deleted) == 20 {
+				t.Fatalf("ListDeleted() returned %d clusters, want the oldest dropped", len(deleted))
+			}
+			if last := deleted[len(deleted)-1]; last.Name != "pr-19" {
+				t.Errorf("newest history = %s, want pr-19", last.Name)
+			}
+
+			// Active clusters are never dropped
+			for i := 0; i < 50; i++ {
+				if err := store.Add(&ClusterMetadata{Name: fmt.Sprintf("live-%d", i)}); err != nil {
+					if !strings.Contains(err.Error(), "use the sqlite backend") {
+						t.Fatalf("Add() error = %v, want a size error", err)
+					}
+					return
+				}
+			}
+			t.Error("Add() never failed although the clusters cannot fit")
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package metadata
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrLocked is returned when the store lock cannot be acquired in time
+var ErrLocked = errors.New("metadata store is locked by another ghostctl process")
+
+// lockTimeout bounds how long a command waits for the store lock
+var lockTimeout = 10 * time.Second
+
+// lockRetryInterval is how often a held lock is retried
+const lockRetryInterval = 50 * time.Millisecond
@@ -1,164 +1,256 @@
 package metadata
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
 )
 
 const (
-	ClustersFileName     = "clusters.json"
-	DefaultDir           = ".ghost"
-	KubeconfigsDirName   = "kubeconfigs"
+	ClustersFileName   = "clusters.json"
+	DeletedFileName    = "deleted.json" // schema v1 only; merged into clusters.json since v2
+	BackupSuffix       = ".bak"
+	LockSuffix         = ".lock"
+	SQLiteFileName     = "clusters.db"
+	DefaultDir         = ".ghost"
+	KubeconfigsDirName = "kubeconfigs"
+
+	// OwnerLabel is the cluster label identifying who owns a cluster
+	OwnerLabel = "owner"
+
+	// PoolOwner owns warm pool members until they are claimed
+	PoolOwner = "ghostctl-pool"
 )
 
+// defaultNamespace is the host namespace of shared stores when the config
+// sets none; it matches vcluster.DefaultNamespace
+const defaultNamespace = "ghostcluster"
+
 // ClusterMetadata represents metadata about a managed cluster
 type ClusterMetadata struct {
-	Name            string            `json:"name"`
-	Namespace       string            `json:"namespace"`
-	CreatedAt       time.Time         `json:"createdAt"`
-	TTL             string            `json:"ttl,omitempty"`
-	KubeconfigPath  string            `json:"kubeconfigPath"`
-	HostCluster     string            `json:"hostCluster"`
-	Template        string            `json:"template,omitempty"`
-	CPU             string            `json:"cpu,omitempty"`
-	Memory          string            `json:"memory,omitempty"`
-	Storage         string            `json:"storage,omitempty"`
-	GPU             int               `json:"gpu,omitempty"`
-	GPUType         string            `json:"gpuType,omitempty"`
-	Labels          map[string]string `json:"labels,omitempty"`
+	Name              string            `json:"name"`
+	HostName          string            `json:"hostName,omitempty"` // vCluster name on the host, if not Name
+	Namespace         string            `json:"namespace"`
+	NamespaceStrategy string            `json:"namespaceStrategy,omitempty"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	TTL               string            `json:"ttl,omitempty"`
+	KubeconfigPath    string            `json:"kubeconfigPath"`
+	HostCluster       string            `json:"hostCluster"`
+	Template          string            `json:"template,omitempty"`
+	CPU               string            `json:"cpu,omitempty"`
+	Memory            string            `json:"memory,omitempty"`
+	Storage           string            `json:"storage,omitempty"`
+	GPU               int               `json:"gpu,omitempty"`
+	GPUType           string            `json:"gpuType,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Security          *cluster.Security `json:"security,omitempty"`
+	Owner             string            `json:"owner,omitempty"`
+	OwnerUser         string            `json:"ownerUser,omitempty"`
+	OwnerEmail        string            `json:"ownerEmail,omitempty"`
+	OwnerHost         string            `json:"ownerHost,omitempty"`
+	OwnerCIActor      string            `json:"ownerCIActor,omitempty"`
+	HourlyCost        float64           `json:"hourlyCost,omitempty"`
+	BudgetOverride    string            `json:"budgetOverride,omitempty"`
+	OptionsHash       string            `json:"optionsHash,omitempty"` // what the cluster was created with; see cluster.CreateOptions.OptionsHash
+	Pool              string            `json:"pool,omitempty"`        // template of the warm pool the cluster was created for
+	ClaimedAt         *time.Time        `json:"claimedAt,omitempty"`   // when the pool member was claimed
+	DeletedAt         *time.Time        `json:"deletedAt,omitempty"`
+	Events            []Event           `json:"events,omitempty"`
 }
 
-// Store manages the metadata store
-type Store struct {
-	path string
-}
-
-// NewStore creates a new metadata store
-func NewStore() (*Store, error) {
-	home, err := os.UserHomeDir()
+// ExpiresAt returns when the cluster's TTL runs out, counted from when it
+// was claimed for pool members. ok is false if the cluster has no valid TTL.
+func (m *ClusterMetadata) ExpiresAt() (expiresAt time.Time, ok bool) {
+	if m.TTL == "" {
+		return time.Time{}, false
+	}
+	ttl, err := utils.ParseDuration(m.TTL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return time.Time{}, false
 	}
-
-	basePath := filepath.Join(home, DefaultDir)
-	if err := os.MkdirAll(basePath, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create .ghost directory: %w", err)
+	if m.ClaimedAt != nil {
+		return m.ClaimedAt.Add(ttl), true
 	}
+	return m.CreatedAt.Add(ttl), true
+}
 
-	// Create kubeconfigs directory
-	kubeDir := filepath.Join(basePath, KubeconfigsDirName)
-	if err := os.MkdirAll(kubeDir, 0700); err != nil {
-		return nil, fmt.Errorf("failed to create kubeconfigs directory: %w", err)
+// VClusterName returns the name of the cluster's vCluster on the host,
+// which a claimed pool member keeps from before it was claimed
+func (m *ClusterMetadata) VClusterName() string {
+	if m.HostName != "" {
+		return m.HostName
 	}
-
-	path := filepath.Join(basePath, ClustersFileName)
-	return &Store{path: path}, nil
+	return m.Name
 }
 
-// GetClusterPath returns the kubeconfig path for a cluster
-func GetClusterPath(name string) (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
-	return filepath.Join(home, DefaultDir, KubeconfigsDirName, name+".yaml"), nil
+// Unclaimed reports whether the cluster is a warm pool member waiting to
+// be claimed
+func (m *ClusterMetadata) Unclaimed() bool {
+	return m.Pool != "" && m.ClaimedAt == nil
 }
 
-// all returns all clusters from the store
-func (s *Store) all() (map[string]*ClusterMetadata, error) {
-	data, err := os.ReadFile(s.path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return make(map[string]*ClusterMetadata), nil
-		}
-		return nil, fmt.Errorf("failed to read metadata store: %w", err)
-	}
+// Expired reports whether the cluster has outlived its TTL at the given time
+func (m *ClusterMetadata) Expired(now time.Time) bool {
+	expiresAt, ok := m.ExpiresAt()
+	return ok && !now.Before(expiresAt)
+}
 
-	var clusters map[string]*ClusterMetadata
-	if err := json.Unmarshal(data, &clusters); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+// OwnerName returns who the cluster belongs to: the recorded owner,
+// or the owner label for clusters created before owners were recorded
+func (m *ClusterMetadata) OwnerName() string {
+	if m.Owner != "" {
+		return m.Owner
 	}
-
-	return clusters, nil
+	return m.Labels[OwnerLabel]
 }
 
-// save persists all clusters to the store
-func (s *Store) save(clusters map[string]*ClusterMetadata) error {
-	data, err := json.MarshalIndent(clusters, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+// OwnedBy reports whether who matches any recorded form of the owner's identity
+func (m *ClusterMetadata) OwnedBy(who string) bool {
+	if who == "" {
+		return false
 	}
-
-	if err := os.WriteFile(s.path, data, 0600); err != nil {
-		return fmt.Errorf("failed to write metadata store: %w", err)
+	for _, id := range []string{m.OwnerName(), m.OwnerUser, m.OwnerEmail, m.OwnerCIActor} {
+		if id == who {
+			return true
+		}
 	}
-
-	return nil
+	return false
 }
 
-// Add adds a new cluster to the store
-func (s *Store) Add(meta *ClusterMetadata) error {
-	clusters, err := s.all()
-	if err != nil {
-		return err
-	}
+// Store persists cluster metadata. Backends are selected by the store
+// section of the config; see Open.
+type Store interface {
+	// Add records a new cluster, stamping its creation time
+	Add(meta *ClusterMetadata) error
+	// Get returns a cluster, or an error if it is not recorded
+	Get(name string) (*ClusterMetadata, error)
+	// Remove forgets a cluster without recording it as deleted
+	Remove(name string) error
+	// List returns all active clusters
+	List() ([]*ClusterMetadata, error)
+	// Exists reports whether a cluster is recorded
+	Exists(name string) bool
+	// RecordEvent appends an event to an active cluster's history
+	RecordEvent(name string, event Event) error
+	// Archive moves a cluster into the deleted-cluster history, recording
+	// the deleted event
+	Archive(name string, deleted Event) error
+	// ListDeleted returns the deleted-cluster history
+	ListDeleted() ([]*ClusterMetadata, error)
+	// Prune drops deleted clusters deleted before the given time and
+	// returns how many were dropped
+	Prune(before time.Time) (int, error)
+	// Import copies records verbatim, replacing active clusters and queued
+	// requests with the same name and appending to the deleted history
+	Import(clusters, deleted []*ClusterMetadata, queue []*QueuedCluster) error
 
-	meta.CreatedAt = time.Now()
-	clusters[meta.Name] = meta
+	// Enqueue appends a request to the creation queue, stamping when it
+	// was queued. It fails if a request of the same name is queued.
+	Enqueue(req *QueuedCluster) error
+	// ListQueue returns the queued requests, oldest first
+	ListQueue() ([]*QueuedCluster, error)
+	// SetQueuePhase moves a queued request from one phase to another and
+	// records why, and when if the phase changes. ok is false if the
+	// request is not in phase from, so only one of several racing queue
+	// runners claims a request.
+	SetQueuePhase(name, from, to, reason string) (ok bool, err error)
+	// Dequeue removes a request from the queue
+	Dequeue(name string) error
 
-	return s.save(clusters)
+	// Rename replaces the record of an active cluster with meta, which may
+	// have another name. It fails if oldName is not recorded or meta's name
+	// is taken, so only one of several racing renames of a cluster wins.
+	Rename(oldName string, meta *ClusterMetadata) error
 }
 
-// Get retrieves a cluster from the store
-func (s *Store) Get(name string) (*ClusterMetadata, error) {
-	clusters, err := s.all()
-	if err != nil {
-		return nil, err
-	}
-
-	meta, exists := clusters[name]
-	if !exists {
-		return nil, fmt.Errorf("cluster not found: %s", name)
-	}
+// Store backends
+const (
+	BackendFile      = "file"
+	BackendConfigMap = "configmap"
+	BackendSecret    = "secret"
+	BackendSQLite    = "sqlite"
+)
 
-	return meta, nil
-}
+// Backends lists the supported store backends
+var Backends = []string{BackendFile, BackendConfigMap, BackendSecret, BackendSQLite}
 
-// Remove removes a cluster from the store
-func (s *Store) Remove(name string) error {
-	clusters, err := s.all()
+// NewStore opens the metadata store configured in $HOME/.ghost/config.yaml
+func NewStore() (Store, error) {
+	cfg, err := config.Load()
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	return Open(cfg)
+}
 
-	if _, exists := clusters[name]; !exists {
-		return fmt.Errorf("cluster not found: %s", name)
+// Open opens the metadata store backend selected by cfg.Store
+func Open(cfg *config.Config) (Store, error) {
+	sc := cfg.Store
+	switch sc.Backend {
+	case BackendFile, "":
+		dir := sc.Path
+		if dir == "" {
+			var err error
+			if dir, err = defaultDir(); err != nil {
+				return nil, err
+			}
+		}
+		return NewFileStore(dir)
+	case BackendConfigMap, BackendSecret:
+		namespace := sc.Namespace
+		if namespace == "" {
+			namespace = cfg.Namespace
+		}
+		if namespace == "" {
+			namespace = defaultNamespace
+		}
+		return NewKubeStore(sc.Backend, namespace, sc.Name), nil
+	case BackendSQLite:
+		path := sc.Path
+		if path == "" {
+			dir, err := defaultDir()
+			if err != nil {
+				return nil, err
+			}
+			path = filepath.Join(dir, SQLiteFileName)
+		}
+		return NewSQLiteStore(path)
+	default:
+		return nil, fmt.Errorf("unknown metadata store backend %q (expected one of %v)", sc.Backend, Backends)
 	}
-
-	delete(clusters, name)
-	return s.save(clusters)
 }
 
-// List returns all clusters from the store
-func (s *Store) List() ([]*ClusterMetadata, error) {
-	clusters, err := s.all()
+// defaultDir returns $HOME/.ghost, creating it and its kubeconfigs directory
+func defaultDir() (string, error) {
+	home, err := os.UserHomeDir()
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	basePath := filepath.Join(home, DefaultDir)
+	if err := os.MkdirAll(basePath, 0700); err != nil {
+		return "", fmt.Errorf("failed to create .ghost directory: %w", err)
 	}
 
-	var result []*ClusterMetadata
-	for _, meta := range clusters {
-		result = append(result, meta)
+	// Create kubeconfigs directory
+	kubeDir := filepath.Join(basePath, KubeconfigsDirName)
+	if err := os.MkdirAll(kubeDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create kubeconfigs directory: %w", err)
 	}
 
-	return result, nil
+	return basePath, nil
 }
 
-// Exists checks if a cluster exists in the store
-func (s *Store) Exists(name string) bool {
-	_, err := s.Get(name)
-	return err == nil
+// GetClusterPath returns the kubeconfig path for a cluster
+func GetClusterPath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, DefaultDir, KubeconfigsDirName, name+".yaml"), nil
 }
@@ -0,0 +1,331 @@
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+)
+
+func newTestStore(t *testing.T) (Store, string) {
+	t.Helper()
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewStoreAt() error = %v", err)
+	}
+	return store, dir
+}
+
+func TestFileStoreLifecycle(t *testing.T) {
+	store, _ := newTestStore(t)
+	testStoreLifecycle(t, store)
+}
+
+// testStoreLifecycle exercises the Store contract shared by every backend
+func testStoreLifecycle(t *testing.T, store Store) {
+	t.Helper()
+
+	if clusters, err := store.List(); err != nil || len(clusters) != 0 {
+		t.Fatalf("List() on empty store = %v, %v", clusters, err)
+	}
+
+	if err := store.Add(&ClusterMetadata{Name: "a"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := store.Add(&ClusterMetadata{Name: "b"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if !store.Exists("a") || store.Exists("missing") {
+		t.Error("Exists() returned the wrong result")
+	}
+
+	if err := store.RecordEvent("a", NewEvent(EventReady, "alice", "")); err != nil {
+		t.Fatalf("RecordEvent() error = %v", err)
+	}
+	if err := store.RecordEvent("missing", NewEvent(EventReady, "alice", "")); err == nil {
+		t.Error("RecordEvent() on a missing cluster should fail")
+	}
+
+	deleted := NewEvent(EventDeleted, "bob", "")
+	if err := store.Archive("a", deleted); err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if err := store.Archive("a", deleted); err == nil {
+		t.Error("Archive() of an archived cluster should fail")
+	}
+
+	clusters, _ := store.List()
+	if len(clusters) != 1 || clusters[0].Name != "b" {
+		t.Errorf("List() = %v, want only b", clusters)
+	}
+	archived, _ := store.ListDeleted()
+	if len(archived) != 1 || archived[0].Name != "a" || archived[0].DeletedAt == nil {
+		t.Fatalf("ListDeleted() = %v, want a with DeletedAt", archived)
+	}
+	if events := archived[0].Events; len(events) != 2 || events[0].Type != EventReady || events[1].Type != EventDeleted || events[1].User != "bob" {
+		t.Errorf("archived events = %+v, want ready then deleted by bob", events)
+	}
+
+	if err := store.Remove("b"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if err := store.Remove("b"); err == nil {
+		t.Error("Remove() of a missing cluster should fail")
+	}
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := store.Import(
+		[]*ClusterMetadata{{Name: "imported", CreatedAt: created, Labels: map[string]string{"team": "o'brien"}}},
+		[]*ClusterMetadata{{Name: "old", CreatedAt: created, DeletedAt: &created}},
+		[]*QueuedCluster{{Name: "waiting", Phase: QueuePhaseQueued, QueuedAt: created}},
+	); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	imported, err := store.Get("imported")
+	if err != nil || !imported.CreatedAt.Equal(created) || imported.Labels["team"] != "o'brien" {
+		t.Errorf("Get() after Import() = %+v, %v; want records copied verbatim", imported, err)
+	}
+	archived, _ = store.ListDeleted()
+	if len(archived) != 2 || archived[1].Name != "old" {
+		t.Errorf("ListDeleted() after Import() = %v, want a then old", archived)
+	}
+	if queue, err := store.ListQueue(); err != nil || len(queue) != 1 || !queue[0].QueuedAt.Equal(created) {
+		t.Errorf("ListQueue() after Import() = %v, %v; want waiting copied verbatim", queue, err)
+	}
+	if err := store.Dequeue("waiting"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only "old" was deleted before the cutoff
+	pruned, err := store.Prune(created.Add(time.Hour))
+	if err != nil || pruned != 1 {
+		t.Fatalf("Prune() = %d, %v; want 1", pruned, err)
+	}
+	archived, _ = store.ListDeleted()
+	if len(archived) != 1 || archived[0].Name != "a" {
+		t.Errorf("ListDeleted() after Prune() = %v, want only a", archived)
+	}
+
+	if err := store.Add(&ClusterMetadata{Name: "pool-1", Pool: "default"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	claimed := &ClusterMetadata{Name: "mine", HostName: "pool-1", Pool: "default", ClaimedAt: &created}
+	if err := store.Rename("pool-1", claimed); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if err := store.Rename("pool-1", &ClusterMetadata{Name: "theirs"}); err == nil {
+		t.Error("Rename() of a renamed cluster should fail")
+	}
+	if err := store.Rename("mine", &ClusterMetadata{Name: "imported"}); err == nil {
+		t.Error("Rename() to a taken name should fail")
+	}
+	renamed, err := store.Get("mine")
+	if err != nil || renamed.VClusterName() != "pool-1" || renamed.Unclaimed() || store.Exists("pool-1") || store.Exists("theirs") {
+		t.Errorf("Get() after Rename() = %+v, %v; want mine claimed from pool-1", renamed, err)
+	}
+
+	for _, name := range []string{"q1", "q2"} {
+		req := &QueuedCluster{Name: name, Phase: QueuePhaseQueued, Options: &cluster.CreateOptions{Name: name, GPU: 1}}
+		if err := store.Enqueue(req); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+	if err := store.Enqueue(&QueuedCluster{Name: "q1", Phase: QueuePhaseQueued}); err == nil {
+		t.Error("Enqueue() of a queued name should fail")
+	}
+	queue, err := store.ListQueue()
+	if err != nil || len(queue) != 2 || queue[0].Name != "q1" || queue[1].Name != "q2" || queue[0].QueuedAt.IsZero() || queue[0].Options.GPU != 1 {
+		t.Fatalf("ListQueue() = %v, %v; want q1 then q2 with their options", queue, err)
+	}
+
+	// Only one of two runners claims a request
+	if ok, err := store.SetQueuePhase("q1", QueuePhaseQueued, QueuePhaseCreating, ""); !ok || err != nil {
+		t.Fatalf("SetQueuePhase() = %v, %v; want claimed", ok, err)
+	}
+	if ok, err := store.SetQueuePhase("q1", QueuePhaseQueued, QueuePhaseCreating, ""); ok || err != nil {
+		t.Errorf("second SetQueuePhase() = %v, %v; want not claimed", ok, err)
+	}
+	if ok, err := store.SetQueuePhase("q2", QueuePhaseQueued, QueuePhaseQueued, "no room"); !ok || err != nil {
+		t.Errorf("SetQueuePhase() with a reason = %v, %v", ok, err)
+	}
+	if _, err := store.SetQueuePhase("missing", QueuePhaseQueued, QueuePhaseCreating, ""); err == nil {
+		t.Error("SetQueuePhase() of a missing request should fail")
+	}
+	queue, _ = store.ListQueue()
+	if len(queue) != 2 || queue[0].Phase != QueuePhaseCreating || queue[1].Reason != "no room" {
+		t.Errorf("ListQueue() after SetQueuePhase() = %+v", queue)
+	}
+	// Only a change of phase is stamped
+	if len(queue) == 2 && (queue[0].PhaseSince == nil || queue[0].InPhaseSince().Before(queue[0].QueuedAt) || queue[1].PhaseSince != nil) {
+		t.Errorf("PhaseSince = %v and %v, want only q1's set", queue[0].PhaseSince, queue[1].PhaseSince)
+	}
+
+	if err := store.Dequeue("q1"); err != nil {
+		t.Fatalf("Dequeue() error = %v", err)
+	}
+	if err := store.Dequeue("q1"); err == nil {
+		t.Error("Dequeue() of a missing request should fail")
+	}
+	queue, _ = store.ListQueue()
+	if len(queue) != 1 || queue[0].Name != "q2" {
+		t.Errorf("ListQueue() after Dequeue() = %v, want only q2", queue)
+	}
+}
+
+func TestStoreConcurrentAdds(t *testing.T) {
+	store, dir := newTestStore(t)
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Separate Store values behave like separate processes
+			s, err := NewFileStore(dir)
+			if err == nil {
+				err = s.Add(&ClusterMetadata{Name: fmt.Sprintf("pr-%d", i)})
+			}
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	clusters, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(clusters) != n {
+		t.Errorf("List() returned %d clusters, want %d", len(clusters), n)
+	}
+}
+
+func TestStoreMigratesV1(t *testing.T) {
+	store, dir := newTestStore(t)
+
+	v1 := `{"old": {"name": "old", "namespace": "ghostcluster"}}`
+	deleted := `[{"name": "gone", "namespace": "ghostcluster"}]`
+	if err := os.WriteFile(filepath.Join(dir, ClustersFileName), []byte(v1), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, DeletedFileName), []byte(deleted), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if !store.Exists("old") {
+		t.Fatal("v1 cluster not found after migration")
+	}
+	gone, err := store.ListDeleted()
+	if err != nil || len(gone) != 1 || gone[0].Name != "gone" {
+		t.Fatalf("ListDeleted() = %v, %v; want the v1 deleted cluster", gone, err)
+	}
+
+	// The first write persists the migrated document
+	if err := store.Add(&ClusterMetadata{Name: "new"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ClustersFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.SchemaVersion != CurrentSchemaVersion || len(doc.Clusters) != 2 || len(doc.Deleted) != 1 {
+		t.Errorf("saved document = %+v, want v%d with 2 clusters and 1 deleted", doc, CurrentSchemaVersion)
+	}
+	if _, err := os.Stat(filepath.Join(dir, DeletedFileName)); !os.IsNotExist(err) {
+		t.Errorf("%s should be retired after migration", DeletedFileName)
+	}
+}
+
+func TestStoreRejectsNewerSchema(t *testing.T) {
+	store, dir := newTestStore(t)
+
+	newer := fmt.Sprintf(`{"schemaVersion": %d, "clusters": {}}`, CurrentSchemaVersion+1)
+	if err := os.WriteFile(filepath.Join(dir, ClustersFileName), []byte(newer), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := store.List()
+	if err == nil || !strings.Contains(err.Error(), "upgrade ghostctl") {
+		t.Errorf("List() error = %v, want an upgrade hint", err)
+	}
+}
+
+func TestStoreRecoversFromBackup(t *testing.T) {
+	store, dir := newTestStore(t)
+
+	if err := store.Add(&ClusterMetadata{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(&ClusterMetadata{Name: "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash that left a truncated file behind
+	path := filepath.Join(dir, ClustersFileName)
+	if err := os.WriteFile(path, []byte(`{"schemaVersion": 2, "clus`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	clusters, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v, want recovery from backup", err)
+	}
+	if len(clusters) != 1 || clusters[0].Name != "a" {
+		t.Errorf("List() = %v, want the state before the last write", clusters)
+	}
+	if _, err := os.Stat(path + ".corrupt"); err != nil {
+		t.Errorf("corrupt file should be kept for inspection: %v", err)
+	}
+
+	// The restored file is usable again
+	if err := store.Add(&ClusterMetadata{Name: "c"}); err != nil {
+		t.Fatalf("Add() after recovery error = %v", err)
+	}
+}
+
+func TestStoreCorruptWithoutBackup(t *testing.T) {
+	store, dir := newTestStore(t)
+
+	if err := os.WriteFile(filepath.Join(dir, ClustersFileName), []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.List(); err == nil || !strings.Contains(err.Error(), "corrupt") {
+		t.Errorf("List() error = %v, want a corruption error", err)
+	}
+}
+
+func TestStoreLockTimeout(t *testing.T) {
+	store, dir := newTestStore(t)
+
+	unlock, err := acquireLock(filepath.Join(dir, ClustersFileName+LockSuffix), time.Second)
+	if err != nil {
+		t.Fatalf("acquireLock() error = %v", err)
+	}
+	defer unlock()
+
+	saved := lockTimeout
+	lockTimeout = 100 * time.Millisecond
+	defer func() { lockTimeout = saved }()
+
+	if err := store.Add(&ClusterMetadata{Name: "a"}); !errors.Is(err, ErrLocked) {
+		t.Errorf("Add() error = %v, want ErrLocked", err)
+	}
+}
@@ -0,0 +1,51 @@
+package metadata
+
+import (
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+)
+
+// Phases of a queued cluster request
+const (
+	QueuePhaseQueued   = "queued"   // waiting for host capacity or quota
+	QueuePhaseCreating = "creating" // claimed by 'ghostctl queue run'
+)
+
+// QueuedCluster is a cluster request that 'ghostctl up --queue' could not
+// create yet, kept until the host has room for it. Options are resolved
+// when the request is queued, so later template changes don't apply.
+type QueuedCluster struct {
+	Name              string                 `json:"name"`
+	Phase             string                 `json:"phase"`
+	QueuedAt          time.Time              `json:"queuedAt"`
+	Reason            string                 `json:"reason,omitempty"` // why the request is waiting
+	Template          string                 `json:"template,omitempty"`
+	Options           *cluster.CreateOptions `json:"options"`
+	Owner             string                 `json:"owner,omitempty"`
+	OwnerUser         string                 `json:"ownerUser,omitempty"`
+	OwnerEmail        string                 `json:"ownerEmail,omitempty"`
+	OwnerHost         string                 `json:"ownerHost,omitempty"`
+	OwnerCIActor      string                 `json:"ownerCIActor,omitempty"`
+	BudgetOverride    string                 `json:"budgetOverride,omitempty"`
+	SkipCapacityCheck bool                   `json:"skipCapacityCheck,omitempty"`
+	PhaseSince        *time.Time             `json:"phaseSince,omitempty"` // when the phase last changed
+}
+
+// InPhaseSince returns when the request entered its current phase
+func (q *QueuedCluster) InPhaseSince() time.Time {
+	if q.PhaseSince != nil {
+		return *q.PhaseSince
+	}
+	return q.QueuedAt
+}
+
+// queueIndex returns the position of a queued request, or -1
+func queueIndex(queue []*QueuedCluster, name string) int {
+	for i, q := range queue {
+		if q.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,149 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CurrentSchemaVersion is the version of clusters.json written by this build.
+//
+//	v1: a bare map of cluster name to metadata, with deleted clusters in deleted.json
+//	v2: a versioned document holding both active and deleted clusters
+//	v3: adds the creation queue
+const CurrentSchemaVersion = 3
+
+// document is the on-disk layout of clusters.json
+type document struct {
+	SchemaVersion int                         `json:"schemaVersion"`
+	Clusters      map[string]*ClusterMetadata `json:"clusters"`
+	Deleted       []*ClusterMetadata          `json:"deleted,omitempty"`
+	Queue         []*QueuedCluster            `json:"queue,omitempty"`
+
+	// existed is false when the store has never been written
+	existed bool
+	// legacyFiles are v1 files folded into the document by a migration,
+	// removed once the migrated document is saved
+	legacyFiles []string
+}
+
+func newDocument() *document {
+	return &document{
+		SchemaVersion: CurrentSchemaVersion,
+		Clusters:      make(map[string]*ClusterMetadata),
+	}
+}
+
+// migration upgrades a document from version n to n+1. legacyDir is the
+// directory holding v1 side files, or empty for backends that never had them.
+type migration func(data []byte, legacyDir string, doc *document) ([]byte, error)
+
+// migrations[n-1] upgrades version n to n+1
+var migrations = []migration{
+	migrateV1ToV2,
+	migrateV2ToV3,
+}
+
+// migrateV1ToV2 wraps the bare cluster map and folds in deleted.json
+func migrateV1ToV2(data []byte, legacyDir string, doc *document) ([]byte, error) {
+	var clusters map[string]*ClusterMetadata
+	if err := json.Unmarshal(data, &clusters); err != nil {
+		return nil, err
+	}
+
+	v2 := newDocument()
+	for name, meta := range clusters {
+		v2.Clusters[name] = meta
+	}
+
+	if legacyDir == "" {
+		return json.Marshal(v2)
+	}
+
+	deletedPath := filepath.Join(legacyDir, DeletedFileName)
+	deletedData, err := os.ReadFile(deletedPath)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(deletedData, &v2.Deleted); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", DeletedFileName, err)
+		}
+		doc.legacyFiles = append(doc.legacyFiles, deletedPath)
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read %s: %w", DeletedFileName, err)
+	}
+
+	return json.Marshal(v2)
+}
+
+// migrateV2ToV3 needs no changes: v2 documents have an empty queue. The
+// version is bumped so that older ghostctl builds, which would drop the
+// queue when saving, refuse the document instead.
+func migrateV2ToV3(data []byte, legacyDir string, doc *document) ([]byte, error) {
+	return data, nil
+}
+
+// decodeDocument parses a document in any known schema version, migrating it
+// to the current version in memory
+func decodeDocument(data []byte, legacyDir string) (*document, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("metadata store is empty")
+	}
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	version := 1
+	if raw, ok := probe["schemaVersion"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("invalid schemaVersion: %w", err)
+		}
+	}
+	if version > CurrentSchemaVersion {
+		return nil, fmt.Errorf("metadata store has schema version %d, but this ghostctl only supports up to %d; upgrade ghostctl", version, CurrentSchemaVersion)
+	}
+	if version < 1 {
+		return nil, fmt.Errorf("invalid schemaVersion %d", version)
+	}
+
+	doc := &document{}
+	for ; version < CurrentSchemaVersion; version++ {
+		var err error
+		if data, err = migrations[version-1](data, legacyDir, doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate metadata store from schema v%d: %w", version, err)
+		}
+	}
+
+	legacyFiles := doc.legacyFiles
+	doc = newDocument()
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	if doc.Clusters == nil {
+		doc.Clusters = make(map[string]*ClusterMetadata)
+	}
+	doc.legacyFiles = legacyFiles
+	doc.existed = true
+	return doc, nil
+}
+
+// encode marshals a document in the current schema version
+func (doc *document) encode() ([]byte, error) {
+	doc.SchemaVersion = CurrentSchemaVersion
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	return data, nil
+}
+
+// isNewerSchema reports whether data is a document written by a newer ghostctl
+func isNewerSchema(data []byte) bool {
+	var probe struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.SchemaVersion > CurrentSchemaVersion
+}
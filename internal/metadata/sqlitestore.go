@@ -0,0 +1,391 @@
+package metadata
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	// Registers the pure-Go "sqlite" driver
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchemaVersion is stored in PRAGMA user_version
+//
+//	v1: clusters and deleted_clusters
+//	v2: adds the queue table
+const sqliteSchemaVersion = 2
+
+// sqliteTimeFormat is a fixed-width UTC format, so stored times sort as text
+const sqliteTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// sqliteSchema creates the tables used by the SQLite backend
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS clusters (
+	name TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS deleted_clusters (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	name       TEXT NOT NULL,
+	deleted_at TEXT NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS deleted_clusters_name ON deleted_clusters (name);
+CREATE INDEX IF NOT EXISTS deleted_clusters_deleted_at ON deleted_clusters (deleted_at);
+CREATE TABLE IF NOT EXISTS queue (
+	seq  INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	data TEXT NOT NULL
+);
+`
+
+// sqliteStore keeps metadata in an embedded SQLite database, one row per
+// cluster, so large deleted-cluster histories don't have to be rewritten on
+// every change. Transactions take the write lock up front and wait up to
+// 10s for other writers.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens the SQLite database at path, creating it if needed
+func NewSQLiteStore(path string) (Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create metadata directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(10000)&_txlock=immediate")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metadata database: %w", err)
+	}
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open metadata database: %w", err)
+	}
+	if version > sqliteSchemaVersion {
+		db.Close()
+		return nil, fmt.Errorf("metadata database has schema version %d, but this ghostctl only supports up to %d; upgrade ghostctl", version, sqliteSchemaVersion)
+	}
+	if version < sqliteSchemaVersion {
+		if _, err := db.Exec("PRAGMA journal_mode = WAL"); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to initialize metadata database: %w", err)
+		}
+		err := inTx(db, func(tx *sql.Tx) error {
+			if _, err := tx.Exec(sqliteSchema); err != nil {
+				return err
+			}
+			_, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", sqliteSchemaVersion))
+			return err
+		})
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to initialize metadata database: %w", err)
+		}
+	}
+	return &sqliteStore{db: db}, nil
+}
+
+// inTx runs fn in a transaction, committing it if fn succeeds
+func inTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// changed returns how many rows a statement changed
+func changed(result sql.Result, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// queryClusters runs a SELECT returning a data column of cluster metadata
+func (s *sqliteStore) queryClusters(query string, args ...interface{}) ([]*ClusterMetadata, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clusters := []*ClusterMetadata{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read metadata row: %w", err)
+		}
+		var meta ClusterMetadata
+		if err := json.Unmarshal([]byte(data), &meta); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+		clusters = append(clusters, &meta)
+	}
+	return clusters, rows.Err()
+}
+
+// insertCluster writes an active cluster, replacing one of the same name
+func insertCluster(tx *sql.Tx, meta *ClusterMetadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	_, err = tx.Exec("INSERT OR REPLACE INTO clusters (name, data) VALUES (?, ?)", meta.Name, string(data))
+	return err
+}
+
+// deletedValues returns the column values of a deleted_clusters row
+func deletedValues(meta *ClusterMetadata) ([]interface{}, error) {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	var deletedAt string
+	if meta.DeletedAt != nil {
+		deletedAt = meta.DeletedAt.UTC().Format(sqliteTimeFormat)
+	}
+	return []interface{}{meta.Name, deletedAt, string(data)}, nil
+}
+
+// Add adds a new cluster to the store
+func (s *sqliteStore) Add(meta *ClusterMetadata) error {
+	meta.CreatedAt = time.Now()
+	return inTx(s.db, func(tx *sql.Tx) error { return insertCluster(tx, meta) })
+}
+
+// Get retrieves a cluster from the store
+func (s *sqliteStore) Get(name string) (*ClusterMetadata, error) {
+	clusters, err := s.queryClusters("SELECT data FROM clusters WHERE name = ?", name)
+	if err != nil {
+		return nil, err
+	}
+	if len(clusters) == 0 {
+		return nil, fmt.Errorf("cluster not found: %s", name)
+	}
+	return clusters[0], nil
+}
+
+// Remove removes a cluster from the store
+func (s *sqliteStore) Remove(name string) error {
+	n, err := changed(s.db.Exec("DELETE FROM clusters WHERE name = ?", name))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("cluster not found: %s", name)
+	}
+	return nil
+}
+
+// List returns all clusters from the store
+func (s *sqliteStore) List() ([]*ClusterMetadata, error) {
+	return s.queryClusters("SELECT data FROM clusters ORDER BY name")
+}
+
+// Exists checks if a cluster exists in the store
+func (s *sqliteStore) Exists(name string) bool {
+	_, err := s.Get(name)
+	return err == nil
+}
+
+// RecordEvent appends an event to an active cluster's history
+func (s *sqliteStore) RecordEvent(name string, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	n, err := changed(s.db.Exec("UPDATE clusters SET data = json_insert(data, '$.events[#]', json(?)) WHERE name = ?",
+		string(data), name))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("cluster not found: %s", name)
+	}
+	return nil
+}
+
+// Archive removes a cluster from the active store and records it in the
+// deleted-cluster history, so its cost can still be reported
+func (s *sqliteStore) Archive(name string, deleted Event) error {
+	meta, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	deletedAt := deleted.Time
+	meta.DeletedAt = &deletedAt
+	meta.Events = append(meta.Events, deleted)
+
+	values, err := deletedValues(meta)
+	if err != nil {
+		return err
+	}
+	// Only record the deletion if the cluster is still active, so two
+	// concurrent archives of the same cluster record it once
+	return inTx(s.db, func(tx *sql.Tx) error {
+		n, err := changed(tx.Exec("DELETE FROM clusters WHERE name = ?", name))
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("cluster not found: %s", name)
+		}
+		_, err = tx.Exec("INSERT INTO deleted_clusters (name, deleted_at, data) VALUES (?, ?, ?)", values...)
+		return err
+	})
+}
+
+// ListDeleted returns the recorded history of deleted clusters
+func (s *sqliteStore) ListDeleted() ([]*ClusterMetadata, error) {
+	return s.queryClusters("SELECT data FROM deleted_clusters ORDER BY id")
+}
+
+// Prune drops deleted clusters deleted before the given time
+func (s *sqliteStore) Prune(before time.Time) (int, error) {
+	n, err := changed(s.db.Exec("DELETE FROM deleted_clusters WHERE deleted_at != '' AND deleted_at < ?",
+		before.UTC().Format(sqliteTimeFormat)))
+	return int(n), err
+}
+
+// Import copies records into the store verbatim
+func (s *sqliteStore) Import(clusters, deleted []*ClusterMetadata, queue []*QueuedCluster) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		for _, meta := range clusters {
+			if err := insertCluster(tx, meta); err != nil {
+				return err
+			}
+		}
+		for _, meta := range deleted {
+			values, err := deletedValues(meta)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec("INSERT INTO deleted_clusters (name, deleted_at, data) VALUES (?, ?, ?)", values...); err != nil {
+				return err
+			}
+		}
+		for _, req := range queue {
+			data, err := json.Marshal(req)
+			if err != nil {
+				return fmt.Errorf("failed to marshal queued cluster: %w", err)
+			}
+			if _, err := tx.Exec("INSERT OR REPLACE INTO queue (name, data) VALUES (?, ?)", req.Name, string(data)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Rename replaces the record of an active cluster with meta
+func (s *sqliteStore) Rename(oldName string, meta *ClusterMetadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	return inTx(s.db, func(tx *sql.Tx) error {
+		n, err := changed(tx.Exec("DELETE FROM clusters WHERE name = ?", oldName))
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("cluster not found: %s", oldName)
+		}
+		n, err = changed(tx.Exec("INSERT INTO clusters (name, data) SELECT ?, ? WHERE NOT EXISTS (SELECT 1 FROM clusters WHERE name = ?)",
+			meta.Name, string(data), meta.Name))
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("cluster %q already exists", meta.Name)
+		}
+		return nil
+	})
+}
+
+// Enqueue appends a request to the creation queue
+func (s *sqliteStore) Enqueue(req *QueuedCluster) error {
+	req.QueuedAt = time.Now()
+	data, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queued cluster: %w", err)
+	}
+	n, err := changed(s.db.Exec("INSERT INTO queue (name, data) SELECT ?, ? WHERE NOT EXISTS (SELECT 1 FROM queue WHERE name = ?)",
+		req.Name, string(data), req.Name))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("cluster %q is already queued", req.Name)
+	}
+	return nil
+}
+
+// ListQueue returns the queued requests, oldest first
+func (s *sqliteStore) ListQueue() ([]*QueuedCluster, error) {
+	rows, err := s.db.Query("SELECT data FROM queue ORDER BY seq")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	queue := []*QueuedCluster{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read queue row: %w", err)
+		}
+		var req QueuedCluster
+		if err := json.Unmarshal([]byte(data), &req); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal queued cluster: %w", err)
+		}
+		queue = append(queue, &req)
+	}
+	return queue, rows.Err()
+}
+
+// SetQueuePhase moves a queued request from one phase to another
+func (s *sqliteStore) SetQueuePhase(name, from, to, reason string) (bool, error) {
+	query := "UPDATE queue SET data = json_set(data, '$.phase', ?, '$.reason', ?) WHERE name = ? AND json_extract(data, '$.phase') = ?"
+	args := []interface{}{to, reason, name, from}
+	if from != to {
+		query = "UPDATE queue SET data = json_set(data, '$.phase', ?, '$.reason', ?, '$.phaseSince', ?) WHERE name = ? AND json_extract(data, '$.phase') = ?"
+		args = []interface{}{to, reason, time.Now().Format(time.RFC3339Nano), name, from}
+	}
+	n, err := changed(s.db.Exec(query, args...))
+	if err != nil {
+		return false, err
+	}
+	if n > 0 {
+		return true, nil
+	}
+	queue, err := s.ListQueue()
+	if err != nil {
+		return false, err
+	}
+	if queueIndex(queue, name) < 0 {
+		return false, fmt.Errorf("cluster %q is not queued", name)
+	}
+	return false, nil
+}
+
+// Dequeue removes a request from the queue
+func (s *sqliteStore) Dequeue(name string) error {
+	n, err := changed(s.db.Exec("DELETE FROM queue WHERE name = ?", name))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("cluster %q is not queued", name)
+	}
+	return nil
+}
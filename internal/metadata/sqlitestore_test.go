@@ -0,0 +1,64 @@
+package metadata
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestSQLiteStore(t *testing.T) Store {
+	t.Helper()
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), SQLiteFileName))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	return store
+}
+
+func TestSQLiteStoreLifecycle(t *testing.T) {
+	testStoreLifecycle(t, newTestSQLiteStore(t))
+}
+
+func TestSQLiteStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SQLiteFileName)
+
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Add(&ClusterMetadata{Name: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() on an existing database error = %v", err)
+	}
+	if !reopened.Exists("a") {
+		t.Error("cluster not found after reopening the database")
+	}
+}
+
+// TestSQLiteStoreQuotes tests that values are passed as parameters, not
+// spliced into the SQL
+func TestSQLiteStoreQuotes(t *testing.T) {
+	store := newTestSQLiteStore(t)
+	name := "it's'); DROP TABLE clusters; --"
+	if err := store.Add(&ClusterMetadata{Name: name, Labels: map[string]string{"note": "a 'quoted' value"}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	meta, err := store.Get(name)
+	if err != nil || meta.Labels["note"] != "a 'quoted' value" {
+		t.Fatalf("Get() = %+v, %v", meta, err)
+	}
+
+	if err := store.Enqueue(&QueuedCluster{Name: "q", Phase: QueuePhaseQueued}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SetQueuePhase("q", QueuePhaseQueued, QueuePhaseQueued, "host can't fit it"); err != nil {
+		t.Fatalf("SetQueuePhase() error = %v", err)
+	}
+	queue, err := store.ListQueue()
+	if err != nil || len(queue) != 1 || queue[0].Reason != "host can't fit it" {
+		t.Errorf("ListQueue() = %+v, %v", queue, err)
+	}
+}
@@ -0,0 +1,284 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultFileName is the policy file looked up in the ghostctl directory
+	DefaultFileName = "policy.yaml"
+
+	// Operations a policy can apply to
+	OperationUp     = "up"
+	OperationUpdate = "update"
+	OperationExtend = "extend"
+)
+
+// Policy is an ordered set of rules evaluated against cluster requests
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule applies its requirements to every request that its match selects
+type Rule struct {
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description,omitempty"`
+	Match       Match   `yaml:"match,omitempty"`
+	Require     Require `yaml:"require"`
+}
+
+// Match selects the requests a rule applies to. Empty fields match everything.
+type Match struct {
+	Operations []string          `yaml:"operations,omitempty"`
+	Templates  []string          `yaml:"templates,omitempty"`
+	Users      []string          `yaml:"users,omitempty"`
+	Labels     map[string]string `yaml:"labels,omitempty"`
+	GPU        *bool             `yaml:"gpu,omitempty"` // true: only GPU clusters, false: only non-GPU clusters
+	CI         *bool             `yaml:"ci,omitempty"`  // true: only requests made from CI
+}
+
+// Require lists the conditions a matching request must satisfy
+type Require struct {
+	Labels      []string `yaml:"labels,omitempty"`      // labels that must be set
+	MaxTTL      string   `yaml:"maxTTL,omitempty"`      // longest TTL allowed
+	NamePattern string   `yaml:"namePattern,omitempty"` // regular expression the whole name must match
+	ValidName   bool     `yaml:"validName,omitempty"`   // name must be a valid DNS label
+	MaxGPU      *int     `yaml:"maxGPU,omitempty"`
+	GPUTypes    []string `yaml:"gpuTypes,omitempty"` // allowed GPU types
+}
+
+// Request is a resolved cluster request as seen by the policy
+type Request struct {
+	Operation string
+	Template  string
+	User      string
+	CI        bool
+	Options   *cluster.CreateOptions
+}
+
+// BuiltinRules are applied before the rules of every policy
+func BuiltinRules() []Rule {
+	return []Rule{{
+		Name:        "valid-name",
+		Description: "Cluster names must be lowercase DNS labels",
+		Require:     Require{ValidName: true},
+	}}
+}
+
+// DefaultPolicy is used when no policy file exists
+func DefaultPolicy() *Policy {
+	return &Policy{Rules: BuiltinRules()}
+}
+
+// DefaultPath returns the default policy file location
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".ghost", DefaultFileName), nil
+}
+
+// Load reads a policy file. An empty path means the default location;
+// if the default file doesn't exist, DefaultPolicy is returned.
+func Load(path string) (*Policy, error) {
+	explicit := path != ""
+	if !explicit {
+		var err error
+		path, err = DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return DefaultPolicy(), nil
+		}
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// Parse parses and validates a policy document. The built-in rules come
+// first in the returned policy.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+
+	for i, rule := range p.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("policy rule %d has no name", i+1)
+		}
+		if rule.Require.MaxTTL != "" {
+			if _, err := utils.ParseDuration(rule.Require.MaxTTL); err != nil {
+				return nil, fmt.Errorf("policy rule %s: invalid maxTTL: %w", rule.Name, err)
+			}
+		}
+		if rule.Require.NamePattern != "" {
+			if _, err := regexp.Compile(rule.Require.NamePattern); err != nil {
+				return nil, fmt.Errorf("policy rule %s: invalid namePattern: %w", rule.Name, err)
+			}
+		}
+	}
+
+	p.Rules = append(BuiltinRules(), p.Rules...)
+	return &p, nil
+}
+
+// Result is the outcome of one rule
+type Result struct {
+	Rule       string   `json:"rule"`
+	Applicable bool     `json:"applicable"`
+	Passed     bool     `json:"passed"`
+	Reasons    []string `json:"reasons,omitempty"` // why the rule failed or did not apply
+}
+
+// Decision is the outcome of evaluating a whole policy
+type Decision struct {
+	Allowed bool     `json:"allowed"`
+	Results []Result `json:"results"`
+}
+
+// DeniedError is returned when a request violates the policy
+type DeniedError struct {
+	Failed []Result
+}
+
+func (e *DeniedError) Error() string {
+	parts := make([]string, len(e.Failed))
+	for i, r := range e.Failed {
+		parts[i] = fmt.Sprintf("%s: %s", r.Rule, strings.Join(r.Reasons, ", "))
+	}
+	return "request denied by policy: " + strings.Join(parts, "; ")
+}
+
+// Err returns a DeniedError if the decision does not allow the request
+func (d Decision) Err() error {
+	if d.Allowed {
+		return nil
+	}
+	var failed []Result
+	for _, r := range d.Results {
+		if r.Applicable && !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return &DeniedError{Failed: failed}
+}
+
+// Evaluate applies every rule to the request
+func (p *Policy) Evaluate(req Request) Decision {
+	decision := Decision{Allowed: true}
+	for _, rule := range p.Rules {
+		result := Result{Rule: rule.Name}
+
+		if reason := rule.Match.skipReason(req); reason != "" {
+			result.Reasons = []string{reason}
+			decision.Results = append(decision.Results, result)
+			continue
+		}
+
+		result.Applicable = true
+		result.Reasons = rule.Require.violations(req)
+		result.Passed = len(result.Reasons) == 0
+		if !result.Passed {
+			decision.Allowed = false
+		}
+		decision.Results = append(decision.Results, result)
+	}
+	return decision
+}
+
+// skipReason explains why a request is not matched, or returns "" if it is
+func (m Match) skipReason(req Request) string {
+	opts := req.Options
+	if len(m.Operations) > 0 && !utils.StringSliceContains(m.Operations, req.Operation) {
+		return fmt.Sprintf("operation %s not in %v", req.Operation, m.Operations)
+	}
+	if len(m.Templates) > 0 && !utils.StringSliceContains(m.Templates, req.Template) {
+		return fmt.Sprintf("template %s not in %v", req.Template, m.Templates)
+	}
+	if len(m.Users) > 0 && !utils.StringSliceContains(m.Users, req.User) {
+		return fmt.Sprintf("user %s not in %v", req.User, m.Users)
+	}
+	for key, value := range m.Labels {
+		if opts.Labels[key] != value {
+			return fmt.Sprintf("label %s=%s not set", key, value)
+		}
+	}
+	if m.GPU != nil && (opts.GPU > 0) != *m.GPU {
+		if *m.GPU {
+			return "no GPUs requested"
+		}
+		return "GPUs requested"
+	}
+	if m.CI != nil && req.CI != *m.CI {
+		if *m.CI {
+			return "not running in CI"
+		}
+		return "running in CI"
+	}
+	return ""
+}
+
+// violations lists the requirements the request does not meet
+func (r Require) violations(req Request) []string {
+	opts := req.Options
+	var reasons []string
+
+	for _, label := range r.Labels {
+		if opts.Labels[label] == "" {
+			reasons = append(reasons, fmt.Sprintf("label %q is required", label))
+		}
+	}
+
+	if r.MaxTTL != "" {
+		maxTTL, _ := utils.ParseDuration(r.MaxTTL)
+		if opts.TTL == "" {
+			reasons = append(reasons, fmt.Sprintf("a TTL of at most %s is required", r.MaxTTL))
+		} else if ttl, err := utils.ParseDuration(opts.TTL); err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid TTL %q", opts.TTL))
+		} else if ttl > maxTTL {
+			reasons = append(reasons, fmt.Sprintf("TTL %s exceeds maximum %s", opts.TTL, r.MaxTTL))
+		}
+	}
+
+	if r.NamePattern != "" {
+		pattern := regexp.MustCompile("^(?:" + r.NamePattern + ")$")
+		if !pattern.MatchString(opts.Name) {
+			reasons = append(reasons, fmt.Sprintf("name %q does not match %s", opts.Name, r.NamePattern))
+		}
+	}
+
+	if r.ValidName {
+		if err := utils.ValidateClusterName(opts.Name); err != nil {
+			reasons = append(reasons, err.Error())
+		}
+	}
+
+	if r.MaxGPU != nil && opts.GPU > *r.MaxGPU {
+		reasons = append(reasons, fmt.Sprintf("%d GPUs exceeds maximum %d", opts.GPU, *r.MaxGPU))
+	}
+
+	if len(r.GPUTypes) > 0 && opts.GPU > 0 && !utils.StringSliceContains(r.GPUTypes, opts.GPUType) {
+		reasons = append(reasons, fmt.Sprintf("GPU type %q not in %v", opts.GPUType, r.GPUTypes))
+	}
+
+	return reasons
+}
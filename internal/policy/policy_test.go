@@ -0,0 +1,129 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+)
+
+const testPolicy = `
+rules:
+  - name: gpu-cost-center
+    match:
+      gpu: true
+    require:
+      labels: [cost-center]
+  - name: large-ttl
+    match:
+      templates: [large]
+    require:
+      maxTTL: 8h
+  - name: ci-names
+    match:
+      ci: true
+    require:
+      namePattern: "pr-[0-9]+"
+`
+
+// TestEvaluate tests the example rules against matching and non-matching requests
+func TestEvaluate(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("Parse() err = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		req    Request
+		failed []string
+	}{
+		{
+			"plain cluster",
+			Request{Operation: OperationUp, Template: "default", Options: &cluster.CreateOptions{Name: "dev", TTL: "1h"}},
+			nil,
+		},
+		{
+			"gpu without cost center",
+			Request{Operation: OperationUp, Template: "gpu", Options: &cluster.CreateOptions{Name: "ml", GPU: 1}},
+			[]string{"gpu-cost-center"},
+		},
+		{
+			"gpu with cost center",
+			Request{Operation: OperationUp, Template: "gpu", Options: &cluster.CreateOptions{Name: "ml", GPU: 1, Labels: map[string]string{"cost-center": "42"}}},
+			nil,
+		},
+		{
+			"large with long TTL",
+			Request{Operation: OperationUp, Template: "large", Options: &cluster.CreateOptions{Name: "big", TTL: "1d"}},
+			[]string{"large-ttl"},
+		},
+		{
+			"CI with bad name",
+			Request{Operation: OperationUp, CI: true, Template: "default", Options: &cluster.CreateOptions{Name: "pr-12a"}},
+			[]string{"ci-names"},
+		},
+		{
+			"invalid name",
+			Request{Operation: OperationUp, Template: "default", Options: &cluster.CreateOptions{Name: "My_Cluster"}},
+			[]string{"valid-name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := p.Evaluate(tt.req)
+			if decision.Allowed != (len(tt.failed) == 0) {
+				t.Fatalf("Evaluate() allowed = %v, results = %+v", decision.Allowed, decision.Results)
+			}
+
+			err := decision.Err()
+			if len(tt.failed) == 0 {
+				if err != nil {
+					t.Fatalf("Err() = %v, want nil", err)
+				}
+				return
+			}
+
+			var denied *DeniedError
+			if !errors.As(err, &denied) {
+				t.Fatalf("Err() = %v, want DeniedError", err)
+			}
+			if len(denied.Failed) != len(tt.failed) || denied.Failed[0].Rule != tt.failed[0] {
+				t.Fatalf("failed rules = %+v, want %v", denied.Failed, tt.failed)
+			}
+		})
+	}
+}
+
+// TestBuiltinRulesAlwaysApply tests that a policy file cannot drop the
+// built-in name validation
+func TestBuiltinRulesAlwaysApply(t *testing.T) {
+	p, err := Parse([]byte("rules:\n  - name: anything\n    require: {}\n"))
+	if err != nil {
+		t.Fatalf("Parse() err = %v", err)
+	}
+	decision := p.Evaluate(Request{Operation: OperationUp, Options: &cluster.CreateOptions{Name: "My_Cluster"}})
+	if decision.Allowed {
+		t.Fatalf("Evaluate() allowed an invalid name, results = %+v", decision.Results)
+	}
+	if decision.Results[0].Rule != "valid-name" {
+		t.Errorf("first rule = %s, want valid-name", decision.Results[0].Rule)
+	}
+}
+
+// TestParseRejectsInvalidPolicies tests validation of policy documents
+func TestParseRejectsInvalidPolicies(t *testing.T) {
+	invalid := []string{
+		"rules:\n  - require:\n      validName: true\n",
+		"rules:\n  - name: x\n    require:\n      maxTTL: forever\n",
+		"rules:\n  - name: x\n    require:\n      namePattern: \"pr-[\"\n",
+		"rules:\n  - name: x\n    require:\n      lables: [team]\n",
+	}
+
+	for _, doc := range invalid {
+		if _, err := Parse([]byte(doc)); err == nil {
+			t.Errorf("Parse() expected error for %q", doc)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// JSONPath renders v with a kubectl-style JSONPath template.
+//
+// The supported subset covers field access (.a.b, ['a.b']), array indexing
+// ([0], [-1], [*]), quoted literals ({"\n"}), and {range ...}{end} blocks,
+// which is what scripts typically use, e.g.
+// {range .items[*]}{.name}{"\t"}{.status}{"\n"}{end}
+func JSONPath(w io.Writer, text string, v interface{}) error {
+	nodes, err := parseJSONPath(text)
+	if err != nil {
+		return err
+	}
+
+	generic, err := Generic(v)
+	if err != nil {
+		return err
+	}
+
+	return executeJSONPath(w, nodes, generic, generic)
+}
+
+type jsonPathNode struct {
+	text     string         // literal output
+	path     []pathStep     // field expression, nil for literals
+	fromRoot bool           // path starts at $ rather than the current element
+	body     []jsonPathNode // set for range blocks
+	isRange  bool
+}
+
+type pathStep struct {
+	field    string
+	index    int
+	wildcard bool
+	isIndex  bool
+}
+
+func parseJSONPath(text string) ([]jsonPathNode, error) {
+	nodes, _, err := parseJSONPathNodes(text, false)
+	return nodes, err
+}
+
+// parseJSONPathNodes parses until the end of text, or until the matching
+// {end} when inRange is set, and returns the text left unparsed
+func parseJSONPathNodes(text string, inRange bool) ([]jsonPathNode, string, error) {
+	var nodes []jsonPathNode
+	for text != "" {
+		open := strings.Index(text, "{")
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{text: text})
+			text = ""
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{text: text[:open]})
+		}
+
+		end := closingBrace(text, open)
+		if end < 0 {
+			return nil, "", fmt.Errorf("invalid jsonpath template: unclosed {")
+		}
+		expr := strings.TrimSpace(text[open+1 : end])
+		text = text[end+1:]
+
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("invalid jsonpath template: {end} without {range}")
+			}
+			return nodes, text, nil
+		case strings.HasPrefix(expr, "range "):
+			path, fromRoot, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			body, rest, err := parseJSONPathNodes(text, true)
+			if err != nil {
+				return nil, "", err
+			}
+			text = rest
+			nodes = append(nodes, jsonPathNode{path: path, fromRoot: fromRoot, body: body, isRange: true})
+		case strings.HasPrefix(expr, `"`) || strings.HasPrefix(expr, "'"):
+			literal, err := unquoteLiteral(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{text: literal})
+		default:
+			path, fromRoot, err := parsePath(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{path: path, fromRoot: fromRoot})
+		}
+	}
+
+	if inRange {
+		return nil, "", fmt.Errorf("invalid jsonpath template: {range} without {end}")
+	}
+	return nodes, "", nil
+}
+
+// closingBrace finds the brace closing the one at open, skipping quoted text
+func closingBrace(text string, open int) int {
+	var quote byte
+	for i := open + 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+func unquoteLiteral(expr string) (string, error) {
+	if strings.HasPrefix(expr, "'") {
+		if len(expr) < 2 || !strings.HasSuffix(expr, "'") {
+			return "", fmt.Errorf("invalid jsonpath literal: %s", expr)
+		}
+		return expr[1 : len(expr)-1], nil
+	}
+	literal, err := strconv.Unquote(expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid jsonpath literal %s: %w", expr, err)
+	}
+	return literal, nil
+}
+
+// parsePath parses expressions such as .items[*].name, $.items[0] or @.name
+func parsePath(expr string) ([]pathStep, bool, error) {
+	fromRoot := false
+	switch {
+	case strings.HasPrefix(expr, "$"):
+		fromRoot = true
+		expr = expr[1:]
+	case strings.HasPrefix(expr, "@"):
+		expr = expr[1:]
+	}
+
+	var steps []pathStep
+	for expr != "" {
+		switch expr[0] {
+		case '.':
+			expr = expr[1:]
+			n := strings.IndexAny(expr, ".[")
+			if n < 0 {
+				n = len(expr)
+			}
+			if n == 0 {
+				return nil, false, fmt.Errorf("invalid jsonpath expression: empty field name")
+			}
+			steps = append(steps, pathStep{field: expr[:n]})
+			expr = expr[n:]
+		case '[':
+			end := strings.Index(expr, "]")
+			if end < 0 {
+				return nil, false, fmt.Errorf("invalid jsonpath expression: unclosed [")
+			}
+			inner := strings.TrimSpace(expr[1:end])
+			expr = expr[end+1:]
+
+			switch {
+			case inner == "*":
+				steps = append(steps, pathStep{wildcard: true})
+			case strings.HasPrefix(inner, "'") || strings.HasPrefix(inner, `"`):
+				field, err := unquoteLiteral(inner)
+				if err != nil {
+					return nil, false, err
+				}
+				steps = append(steps, pathStep{field: field})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, false, fmt.Errorf("unsupported jsonpath subscript [%s]", inner)
+				}
+				steps = append(steps, pathStep{index: index, isIndex: true})
+			}
+		default:
+			return nil, false, fmt.Errorf("invalid jsonpath expression near %q", expr)
+		}
+	}
+
+	return steps, fromRoot, nil
+}
+
+// evaluatePath applies the path steps to a value and returns all matches
+func evaluatePath(steps []pathStep, value interface{}) []interface{} {
+	current := []interface{}{value}
+	for _, step := range steps {
+		var next []interface{}
+		for _, v := range current {
+			switch {
+			case step.wildcard:
+				switch typed := v.(type) {
+				case []interface{}:
+					next = append(next, typed...)
+				case map[string]interface{}:
+					for _, item := range typed {
+						next = append(next, item)
+					}
+				}
+			case step.isIndex:
+				list, ok := v.([]interface{})
+				if !ok {
+					continue
+				}
+				index := step.index
+				if index < 0 {
+					index += len(list)
+				}
+				if index >= 0 && index < len(list) {
+					next = append(next, list[index])
+				}
+			default:
+				obj, ok := v.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if item, exists := obj[step.field]; exists {
+					next = append(next, item)
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+func executeJSONPath(w io.Writer, nodes []jsonPathNode, root, current interface{}) error {
+	for _, node := range nodes {
+		if node.path == nil && !node.isRange {
+			if _, err := io.WriteString(w, node.text); err != nil {
+				return err
+			}
+			continue
+		}
+
+		start := current
+		if node.fromRoot {
+			start = root
+		}
+		results := evaluatePath(node.path, start)
+
+		if node.isRange {
+			for _, item := range results {
+				if err := executeJSONPath(w, node.body, root, item); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		formatted := make([]string, 0, len(results))
+		for _, result := range results {
+			text, err := formatJSONPathValue(result)
+			if err != nil {
+				return err
+			}
+			formatted = append(formatted, text)
+		}
+		if _, err := io.WriteString(w, strings.Join(formatted, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatJSONPathValue(v interface{}) (string, error) {
+	switch typed := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return typed, nil
+	case bool, float64:
+		return fmt.Sprint(typed), nil
+	default:
+		data, err := json.Marshal(typed)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
@@ -0,0 +1,66 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Format identifies a parsed output format
+type Format struct {
+	Name     string // e.g. "table", "wide", "json", "jsonpath"
+	Template string // template text for jsonpath and go-template formats
+}
+
+// ParseFormat parses an output flag value such as "json", "wide",
+// "jsonpath={.items[*].name}" or "go-template={{range .items}}...".
+func ParseFormat(value string) (Format, error) {
+	name, tmpl, hasTemplate := strings.Cut(value, "=")
+	switch name {
+	case "jsonpath", "go-template":
+		if !hasTemplate || tmpl == "" {
+			return Format{}, fmt.Errorf("output format %s requires a template, e.g. %s=<template>", name, name)
+		}
+		return Format{Name: name, Template: tmpl}, nil
+	}
+
+	if hasTemplate {
+		return Format{}, fmt.Errorf("output format %q does not take a template", name)
+	}
+	return Format{Name: name}, nil
+}
+
+// Generic converts a value into the JSON object model (maps, slices and
+// scalars) so that templates address fields by their JSON names.
+func Generic(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %w", err)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal output: %w", err)
+	}
+	return generic, nil
+}
+
+// GoTemplate renders v with a Go text/template, addressing fields by JSON name
+func GoTemplate(w io.Writer, text string, v interface{}) error {
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse go-template: %w", err)
+	}
+
+	generic, err := Generic(v)
+	if err != nil {
+		return err
+	}
+
+	if err := tmpl.Execute(w, generic); err != nil {
+		return fmt.Errorf("failed to execute go-template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testItem struct {
+	Name   string            `json:"name"`
+	GPU    int               `json:"gpu,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func testItems() map[string]interface{} {
+	return map[string]interface{}{
+		"items": []testItem{
+			{Name: "pr-1", GPU: 2, Labels: map[string]string{"team": "ml"}},
+			{Name: "pr-2"},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantName string
+		wantTmpl string
+		wantErr  bool
+	}{
+		{"json", "json", "", false},
+		{"jsonpath={.items[*].name}", "jsonpath", "{.items[*].name}", false},
+		{"go-template={{.}}", "go-template", "{{.}}", false},
+		{"jsonpath", "", "", true},
+		{"json=foo", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseFormat(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (got.Name != tt.wantName || got.Template != tt.wantTmpl) {
+				t.Errorf("ParseFormat() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"wildcard", "{.items[*].name}", "pr-1 pr-2"},
+		{"index", "{.items[0].gpu}", "2"},
+		{"negative index", "{.items[-1].name}", "pr-2"},
+		{"quoted key", "{.items[0].labels['team']}", "ml"},
+		{"range", `{range .items[*]}{.name}{"\n"}{end}`, "pr-1\npr-2\n"},
+		{"root in range", `{range .items[*]}{$.items[0].name}-{@.name};{end}`, "pr-1-pr-1;pr-1-pr-2;"},
+		{"missing field", "{.items[1].gpu}", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := JSONPath(&buf, tt.tmpl, testItems()); err != nil {
+				t.Fatalf("JSONPath() err = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("JSONPath() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathInvalid(t *testing.T) {
+	for _, tmpl := range []string{"{.items", "{range .items[*]}{.name}", "{end}", "{.items[x]}"} {
+		var buf bytes.Buffer
+		if err := JSONPath(&buf, tmpl, testItems()); err == nil {
+			t.Errorf("expected error for template %q", tmpl)
+		}
+	}
+}
+
+func TestGoTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	err := GoTemplate(&buf, `{{range .items}}{{.name}} {{end}}`, testItems())
+	if err != nil {
+		t.Fatalf("GoTemplate() err = %v", err)
+	}
+	if buf.String() != "pr-1 pr-2 " {
+		t.Errorf("GoTemplate() = %q", buf.String())
+	}
+}
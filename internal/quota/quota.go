@@ -0,0 +1,194 @@
+package quota
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+)
+
+// Quota names reported in violations
+const (
+	ClustersPerUser  = "maxClustersPerUser"
+	GPUsPerUser      = "maxGPUsPerUser"
+	GPUsPerTeam      = "maxGPUsPerTeam"
+	MaxTTL           = "maxTTL"
+	AllowedTemplates = "allowedTemplates"
+)
+
+// Request describes a cluster about to be created
+type Request struct {
+	Owner    string
+	Team     string
+	Template string
+	TTL      string
+	GPU      int
+}
+
+// Violation explains a quota that a request would exceed
+type Violation struct {
+	Quota     string   `json:"quota"`
+	Scope     string   `json:"scope"`
+	Limit     string   `json:"limit"`
+	Current   string   `json:"current,omitempty"`
+	Requested string   `json:"requested"`
+	UsedBy    []string `json:"usedBy,omitempty"` // clusters currently counted against the quota
+}
+
+func (v Violation) String() string {
+	msg := fmt.Sprintf("%s for %s: limit %s", v.Quota, v.Scope, v.Limit)
+	if v.Current != "" {
+		msg += fmt.Sprintf(", in use %s", v.Current)
+	}
+	msg += fmt.Sprintf(", requested %s", v.Requested)
+	if len(v.UsedBy) > 0 {
+		msg += fmt.Sprintf(" (used by %s)", strings.Join(v.UsedBy, ", "))
+	}
+	return msg
+}
+
+// ExceededError is returned when a request violates one or more quotas
+type ExceededError struct {
+	Violations []Violation
+}
+
+func (e *ExceededError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return "quota exceeded: " + strings.Join(parts, "; ")
+}
+
+// Temporary reports whether the request can pass once other clusters are
+// deleted: every violation is of a limit on clusters or GPUs in use, not of
+// the TTL or template the request asked for
+func (e *ExceededError) Temporary() bool {
+	for _, v := range e.Violations {
+		switch v.Quota {
+		case ClustersPerUser, GPUsPerUser, GPUsPerTeam:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Check verifies a request against the configured quotas.
+// clusters are the currently active clusters.
+func Check(q config.Quotas, req Request, clusters []*metadata.ClusterMetadata) error {
+	// Leaving the team label off must not escape the team quotas
+	if req.Team == "" && (len(q.AllowedTemplates) > 0 || (q.MaxGPUsPerTeam > 0 && req.GPU > 0)) {
+		return fmt.Errorf("team quotas are configured, so the cluster needs a team: add --label %s=<team>", q.TeamLabelKey())
+	}
+
+	var violations []Violation
+
+	var owned, ownedGPU, teamGPU []string
+	userGPUs, teamGPUs := 0, 0
+	for _, c := range clusters {
+		// Idle pool members belong to nobody until they are claimed
+		if c.DeletedAt != nil || c.Unclaimed() {
+			continue
+		}
+		if c.OwnerName() == req.Owner {
+			owned = append(owned, c.Name)
+			if c.GPU > 0 {
+				userGPUs += c.GPU
+				ownedGPU = append(ownedGPU, fmt.Sprintf("%s=%d", c.Name, c.GPU))
+			}
+		}
+		if req.Team != "" && c.Labels[q.TeamLabelKey()] == req.Team && c.GPU > 0 {
+			teamGPUs += c.GPU
+			teamGPU = append(teamGPU, fmt.Sprintf("%s=%d", c.Name, c.GPU))
+		}
+	}
+	sort.Strings(owned)
+	sort.Strings(ownedGPU)
+	sort.Strings(teamGPU)
+
+	if q.MaxClustersPerUser > 0 && len(owned)+1 > q.MaxClustersPerUser {
+		violations = append(violations, Violation{
+			Quota:     ClustersPerUser,
+			Scope:     "user " + req.Owner,
+			Limit:     fmt.Sprintf("%d clusters", q.MaxClustersPerUser),
+			Current:   fmt.Sprintf("%d clusters", len(owned)),
+			Requested: "1 cluster",
+			UsedBy:    owned,
+		})
+	}
+
+	if req.GPU > 0 && q.MaxGPUsPerUser > 0 && userGPUs+req.GPU > q.MaxGPUsPerUser {
+		violations = append(violations, Violation{
+			Quota:     GPUsPerUser,
+			Scope:     "user " + req.Owner,
+			Limit:     fmt.Sprintf("%d GPUs", q.MaxGPUsPerUser),
+			Current:   fmt.Sprintf("%d GPUs", userGPUs),
+			Requested: fmt.Sprintf("%d GPUs", req.GPU),
+			UsedBy:    ownedGPU,
+		})
+	}
+
+	if req.GPU > 0 && req.Team != "" && q.MaxGPUsPerTeam > 0 && teamGPUs+req.GPU > q.MaxGPUsPerTeam {
+		violations = append(violations, Violation{
+			Quota:     GPUsPerTeam,
+			Scope:     "team " + req.Team,
+			Limit:     fmt.Sprintf("%d GPUs", q.MaxGPUsPerTeam),
+			Current:   fmt.Sprintf("%d GPUs", teamGPUs),
+			Requested: fmt.Sprintf("%d GPUs", req.GPU),
+			UsedBy:    teamGPU,
+		})
+	}
+
+	// A cluster without a TTL lives until deleted, longer than any limit
+	if limit, ok := q.MaxTTL[req.Template]; ok {
+		maxTTL, err := utils.ParseDuration(limit)
+		if err != nil {
+			return fmt.Errorf("invalid maxTTL for template %s: %w", req.Template, err)
+		}
+		exceeded, requested := req.TTL == "", "no TTL"
+		if req.TTL != "" {
+			ttl, err := utils.ParseDuration(req.TTL)
+			if err != nil {
+				return fmt.Errorf("invalid TTL: %w", err)
+			}
+			exceeded, requested = ttl > maxTTL, req.TTL
+		}
+		if exceeded {
+			violations = append(violations, Violation{
+				Quota:     MaxTTL,
+				Scope:     "template " + req.Template,
+				Limit:     limit,
+				Requested: requested,
+			})
+		}
+	}
+
+	// Teams that are not listed get the "*" entry, or no template at all
+	if len(q.AllowedTemplates) > 0 {
+		allowed, ok := q.AllowedTemplates[req.Team]
+		if !ok {
+			allowed = q.AllowedTemplates["*"]
+		}
+		if !utils.StringSliceContains(allowed, req.Template) {
+			limit := strings.Join(allowed, ", ")
+			if limit == "" {
+				limit = "no templates"
+			}
+			violations = append(violations, Violation{
+				Quota:     AllowedTemplates,
+				Scope:     "team " + req.Team,
+				Limit:     limit,
+				Requested: "template " + req.Template,
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ExceededError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package quota
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/metadata"
+)
+
+func testClusters() []*metadata.ClusterMetadata {
+	return []*metadata.ClusterMetadata{
+		{Name: "a1", Owner: "alice", GPU: 2, Labels: map[string]string{"team": "ml"}},
+		{Name: "a2", Owner: "alice"},
+		{Name: "b1", Owner: "bob", GPU: 1, Labels: map[string]string{"team": "ml"}},
+		{Name: "pool-gpu-1", Owner: metadata.PoolOwner, Pool: "gpu", GPU: 4, Labels: map[string]string{"team": "ml"}},
+	}
+}
+
+// TestCheck tests each quota in isolation
+func TestCheck(t *testing.T) {
+	quotas := config.Quotas{
+		MaxClustersPerUser: 2,
+		MaxGPUsPerUser:     3,
+		MaxGPUsPerTeam:     4,
+		MaxTTL:             map[string]string{"large": "8h"},
+		AllowedTemplates:   map[string][]string{"web": {"default", "minimal"}, "*": {"default", "large"}},
+	}
+
+	tests := []struct {
+		name      string
+		req       Request
+		quota     string
+		temporary bool
+	}{
+		{"within quotas", Request{Owner: "bob", Team: "qa", Template: "default", TTL: "1h"}, "", false},
+		{"too many clusters", Request{Owner: "alice", Team: "qa", Template: "default", TTL: "1h"}, ClustersPerUser, true},
+		{"too many user GPUs", Request{Owner: "bob", Team: "qa", Template: "default", GPU: 3}, GPUsPerUser, true},
+		{"too many team GPUs", Request{Owner: "carol", Team: "ml", Template: "default", GPU: 2}, GPUsPerTeam, true},
+		{"unclaimed pool members are not counted", Request{Owner: "carol", Team: "ml", Template: "default", GPU: 1}, "", false},
+		{"TTL too long", Request{Owner: "bob", Team: "qa", Template: "large", TTL: "1d"}, MaxTTL, false},
+		{"no TTL exceeds maxTTL", Request{Owner: "bob", Team: "qa", Template: "large"}, MaxTTL, false},
+		{"template not allowed", Request{Owner: "bob", Team: "web", Template: "gpu"}, AllowedTemplates, false},
+		{"template not allowed for unlisted teams", Request{Owner: "bob", Team: "qa", Template: "minimal"}, AllowedTemplates, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(quotas, tt.req, testClusters())
+			if tt.quota == "" {
+				if err != nil {
+					t.Fatalf("Check() err = %v, want nil", err)
+				}
+				return
+			}
+
+			var exceeded *ExceededError
+			if !errors.As(err, &exceeded) {
+				t.Fatalf("Check() err = %v, want ExceededError", err)
+			}
+			if len(exceeded.Violations) != 1 || exceeded.Violations[0].Quota != tt.quota {
+				t.Fatalf("Check() violations = %+v, want %s", exceeded.Violations, tt.quota)
+			}
+			if exceeded.Temporary() != tt.temporary {
+				t.Errorf("Temporary() = %v, want %v", exceeded.Temporary(), tt.temporary)
+			}
+		})
+	}
+}
+
+// TestAllowedTemplatesUnlistedTeam tests that a team missing from
+// allowedTemplates may use no template unless there is a "*" entry
+func TestAllowedTemplatesUnlistedTeam(t *testing.T) {
+	listed := map[string][]string{"web": {"default"}}
+	withDefault := map[string][]string{"web": {"default"}, "*": {"minimal"}}
+
+	tests := []struct {
+		name    string
+		allowed map[string][]string
+		req     Request
+		wantErr bool
+	}{
+		{"listed team", listed, Request{Owner: "bob", Team: "web", Template: "default"}, false},
+		{"unlisted team", listed, Request{Owner: "bob", Team: "nobody", Template: "default"}, true},
+		{"unlisted team with a default", withDefault, Request{Owner: "bob", Team: "nobody", Template: "minimal"}, false},
+		{"unlisted team outside the default", withDefault, Request{Owner: "bob", Team: "nobody", Template: "default"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(config.Quotas{AllowedTemplates: tt.allowed}, tt.req, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCheckRequiresTeam tests that team quotas cannot be escaped by leaving
+// the team label off
+func TestCheckRequiresTeam(t *testing.T) {
+	tests := []struct {
+		name    string
+		quotas  config.Quotas
+		req     Request
+		wantErr bool
+	}{
+		{"allowed templates", config.Quotas{AllowedTemplates: map[string][]string{"web": {"default"}}}, Request{Owner: "bob", Template: "gpu"}, true},
+		{"team GPUs", config.Quotas{MaxGPUsPerTeam: 4}, Request{Owner: "bob", GPU: 1}, true},
+		{"team GPUs without GPUs", config.Quotas{MaxGPUsPerTeam: 4}, Request{Owner: "bob"}, false},
+		{"no team quotas", config.Quotas{MaxGPUsPerUser: 4}, Request{Owner: "bob", GPU: 1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.quotas, tt.req, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			var exceeded *ExceededError
+			if errors.As(err, &exceeded) {
+				t.Errorf("Check() err = %v, want a plain error", err)
+			}
+		})
+	}
+}
+
+// TestViolationExplainsUsage tests that violations name the clusters using the quota
+func TestViolationExplainsUsage(t *testing.T) {
+	err := Check(config.Quotas{MaxGPUsPerTeam: 3}, Request{Owner: "carol", Team: "ml", GPU: 1}, testClusters())
+	if err == nil {
+		t.Fatal("Check() expected quota error")
+	}
+	if !strings.Contains(err.Error(), "used by a1=2, b1=1") {
+		t.Errorf("error does not explain usage: %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package shell
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
@@ -52,6 +53,56 @@ func ExecuteCommandWithEnv(env []string, command string, args ...string) (*Comma
 	return result, nil
 }
 
+// ExecuteCommandWithInput executes a command with input written to its stdin
+// and captures output
+func ExecuteCommandWithInput(input string, command string, args ...string) (*CommandResult, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Stdin = strings.NewReader(input)
+
+	output, err := cmd.CombinedOutput()
+	if cmd.ProcessState == nil {
+		return nil, err
+	}
+
+	result := &CommandResult{
+		Stdout:   string(output),
+		ExitCode: cmd.ProcessState.ExitCode(),
+	}
+
+	return result, nil
+}
+
+// ExecuteCommandSplit executes a command with input written to its stdin
+// and captures stdout and stderr separately, so that warnings on stderr
+// never end up in output that is parsed
+func ExecuteCommandSplit(input string, command string, args ...string) (*CommandResult, error) {
+	return ExecuteCommandSplitWithEnv(nil, input, command, args...)
+}
+
+// ExecuteCommandSplitWithEnv is ExecuteCommandSplit with the given
+// environment; a nil env inherits the current one
+func ExecuteCommandSplitWithEnv(env []string, input string, command string, args ...string) (*CommandResult, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = env
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if cmd.ProcessState == nil {
+		return nil, err
+	}
+
+	result := &CommandResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: cmd.ProcessState.ExitCode(),
+	}
+
+	return result, nil
+}
+
 // ExecuteCommandStreaming executes a command with real-time output streaming
 func ExecuteCommandStreaming(command string, args ...string) (int, error) {
 	cmd := exec.Command(command, args...)
@@ -0,0 +1,196 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Loader is implemented by stores that read template documents
+type Loader interface {
+	Load() ([]*Source, []Problem, error)
+	// LayerName names the store in template listings
+	LayerName() string
+}
+
+// Catalog combines template stores. When several stores define a template
+// with the same name, the first store's definition wins.
+type Catalog struct {
+	Loaders []Loader
+
+	// Warn, if set, is called with the problems that do not stop the
+	// catalog from answering, such as a broken file next to the requested
+	// template
+	Warn func(Problem)
+}
+
+// NewCatalog creates a catalog over the given stores, in priority order
+func NewCatalog(loaders ...Loader) *Catalog {
+	return &Catalog{Loaders: loaders}
+}
+
+func (c *Catalog) String() string {
+	names := make([]string, len(c.Loaders))
+	for i, l := range c.Loaders {
+		names[i] = fmt.Sprint(l)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Load reads the template documents of every store. Stores whose
+// directory does not exist are skipped; other store errors are reported
+// as problems so one unreachable store does not hide the others. A
+// template defined by several stores comes from the first; the others are
+// recorded in its Shadows.
+func (c *Catalog) Load() ([]*Source, []Problem, error) {
+	var sources []*Source
+	var problems []Problem
+	defined := make(map[string]*Source)
+	var errs []error
+
+	for _, l := range c.Loaders {
+		loaded, loadProblems, err := l.Load()
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				problems = append(problems, Problem{File: fmt.Sprint(l), Message: err.Error()})
+			}
+			errs = append(errs, err)
+			continue
+		}
+		problems = append(problems, loadProblems...)
+
+		layer := make(map[string]*Source)
+		for _, src := range loaded {
+			src.Layer = l.LayerName()
+			if winner, ok := defined[src.Name]; ok {
+				winner.Shadows = append(winner.Shadows, src)
+				continue
+			}
+			if _, ok := layer[src.Name]; !ok {
+				layer[src.Name] = src
+			}
+			sources = append(sources, src)
+		}
+		for name, src := range layer {
+			defined[name] = src
+		}
+	}
+
+	if len(sources) == 0 && len(problems) == 0 && len(errs) > 0 {
+		return nil, nil, errs[0]
+	}
+	return sources, problems, nil
+}
+
+// Sources returns the template documents of every store, before
+// inheritance is resolved. Files that cannot be loaded are passed to Warn
+// and left out; see Load for the problems of every file.
+func (c *Catalog) Sources() ([]*Source, error) {
+	sources, problems, err := c.Load()
+	if err != nil {
+		return nil, err
+	}
+	c.warn(problems)
+	if len(sources) == 0 {
+		if len(problems) > 0 {
+			return nil, fmt.Errorf("no template could be loaded: %s (run 'ghostctl templates validate' for details)", problems[0])
+		}
+		return nil, fmt.Errorf("no templates found")
+	}
+	return sources, nil
+}
+
+// List returns all available templates. Templates that cannot be resolved
+// are passed to Warn and left out.
+func (c *Catalog) List() ([]Template, error) {
+	sources, err := c.Sources()
+	if err != nil {
+		return nil, err
+	}
+	list, problems := ResolveAll(sources)
+	c.warn(problems)
+	return list, nil
+}
+
+// Get returns a specific template by name
+func (c *Catalog) Get(name string) (*Template, error) {
+	res, err := c.Resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return res.Template, nil
+}
+
+// Resolve returns a template with its inheritance chain applied, and where
+// each value came from
+func (c *Catalog) Resolve(name string) (*Resolution, error) {
+	sources, problems, err := c.Load()
+	if err != nil {
+		return nil, err
+	}
+	c.warn(problems)
+	return resolveLoaded(sources, problems, name)
+}
+
+// resolveLoaded resolves name, mentioning the files that could not be
+// loaded if it is not found among the ones that could
+func resolveLoaded(sources []*Source, problems []Problem, name string) (*Resolution, error) {
+	res, err := Resolve(sources, name)
+	switch {
+	case err == nil || len(problems) == 0:
+		return res, err
+	case len(problems) == 1:
+		return nil, fmt.Errorf("%w (a template file could not be loaded: %s)", err, problems[0])
+	default:
+		return nil, fmt.Errorf("%w (%d template files could not be loaded; run 'ghostctl templates validate')", err, len(problems))
+	}
+}
+
+func (c *Catalog) warn(problems []Problem) {
+	if c.Warn == nil {
+		return
+	}
+	for _, p := range problems {
+		c.Warn(p)
+	}
+}
+
+// Render resolves the named template and renders it with the given
+// parameter values. It refuses the template if it, or a template it
+// extends, is invalid; problems elsewhere in the catalog do not block it.
+func (c *Catalog) Render(name string, set map[string]string) (*Template, error) {
+	sources, loadProblems, err := c.Load()
+	if err != nil {
+		return nil, err
+	}
+	c.warn(loadProblems)
+
+	res, err := resolveLoaded(sources, loadProblems, name)
+	if err != nil {
+		return nil, err
+	}
+
+	inChain := make(map[string]bool, len(res.Chain))
+	for _, n := range res.Chain {
+		inChain[n] = true
+	}
+	var invalid []string
+	for _, p := range Validate(sources) {
+		if inChain[p.Template] {
+			invalid = append(invalid, p.String())
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("template %q is invalid:\n  %s", name, strings.Join(invalid, "\n  "))
+	}
+
+	tmpl, err := res.Render(set)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Validate(); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,149 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Marshal renders a template as the contents of a single-template file
+func Marshal(t *Template) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(t); err != nil {
+		return nil, fmt.Errorf("failed to encode template %q: %w", t.Name, err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CheckFile validates data as the new contents of the template file at
+// path. The templates it defines replace those of the same name in
+// sources, so extends chains and duplicate names are checked against the
+// rest of the catalog. Only problems in the file itself are returned.
+func CheckFile(sources []*Source, path string, data []byte) []Problem {
+	parsed, problems := ParseFile(path, data)
+	if len(problems) > 0 {
+		return problems
+	}
+
+	path = filepath.Clean(path)
+	replaced := make(map[string]bool, len(parsed))
+	for _, src := range parsed {
+		replaced[src.Name] = true
+	}
+	candidates := append([]*Source(nil), parsed...)
+	for _, src := range sources {
+		if replaced[src.Name] || filepath.Clean(src.File) == path {
+			continue
+		}
+		candidates = append(candidates, src)
+	}
+
+	var own []Problem
+	for _, p := range Validate(candidates) {
+		if filepath.Clean(p.File) == path {
+			own = append(own, p)
+		}
+	}
+	return own
+}
+
+// Dependents returns the names of the templates that extend name
+func Dependents(sources []*Source, name string) []string {
+	var names []string
+	for _, src := range sources {
+		if src.Extends() == name {
+			names = append(names, src.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save writes a template file atomically, creating its directory if needed
+func Save(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-"+filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes a template's definition: its file, or its entry in a
+// templates.yaml list. The rest of a templates.yaml file is re-encoded, so
+// its formatting may change, but comments are kept.
+func Remove(src *Source) error {
+	if filepath.Base(src.File) != "templates.yaml" {
+		if err := os.Remove(src.File); err != nil {
+			return fmt.Errorf("failed to delete template %q: %w", src.Name, err)
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(src.File)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src.File, err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", src.File, err)
+	}
+
+	var list *yaml.Node
+	if len(doc.Content) > 0 {
+		list = mappingValue(doc.Content[0], "templates")
+	}
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return fmt.Errorf("template %q not found in %s", src.Name, src.File)
+	}
+	found := false
+	for i, node := range list.Content {
+		if name := mappingValue(node, "name"); name != nil && name.Value == src.Name {
+			list.Content = append(list.Content[:i], list.Content[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("template %q not found in %s", src.Name, src.File)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", src.File, err)
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return Save(src.File, buf.Bytes())
+}
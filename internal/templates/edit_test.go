@@ -0,0 +1,116 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	tmpl := &Template{
+		Name: "review", Extends: "minimal", TTL: "4h",
+		Labels:     map[string]string{"pr": "{{ .pr }}"},
+		Parameters: []Parameter{{Name: "pr", Type: ParamInt, Required: true}},
+	}
+	data, err := Marshal(tmpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "description") {
+		t.Errorf("Marshal() wrote empty fields:\n%s", data)
+	}
+
+	sources, problems := ParseFile("review.yaml", data)
+	if len(problems) > 0 || len(sources) != 1 || sources[0].Extends() != "minimal" {
+		t.Fatalf("ParseFile() = %v, %v", sources, problems)
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.yaml"), "name: base\ncpu: \"2\"\n")
+	writeFile(t, filepath.Join(dir, "app.yaml"), "name: app\nextends: base\n")
+	sources, problems, err := NewFileStore(dir).Load()
+	if err != nil || len(problems) > 0 {
+		t.Fatalf("Load() = %v, %v", problems, err)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		wantErr string
+	}{
+		{"new template", "ml.yaml", "name: ml\nextends: base\nttl: 2h\n", ""},
+		{"replaces its own definition", "base.yaml", "name: base\ncpu: \"4\"\n", ""},
+		{"same name elsewhere is replaced", "other.yaml", "name: base\n", ""},
+		{"bad field", "ml.yaml", "name: ml\nttl: forever\n", "ttl"},
+		{"unknown parent", "ml.yaml", "name: ml\nextends: nope\n", "unknown template"},
+		{"cycle through the catalog", "base.yaml", "name: base\nextends: app\n", "cycle"},
+		{"syntax error", "ml.yaml", "name: [ml\n", "ml.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := CheckFile(sources, filepath.Join(dir, tt.file), []byte(tt.data))
+			if tt.wantErr == "" {
+				if len(problems) > 0 {
+					t.Errorf("CheckFile() = %v, want no problems", problems)
+				}
+				return
+			}
+			if len(problems) == 0 || !strings.Contains(problems[0].String(), tt.wantErr) {
+				t.Errorf("CheckFile() = %v, want %q", problems, tt.wantErr)
+			}
+		})
+	}
+
+	if deps := Dependents(sources, "base"); len(deps) != 1 || deps[0] != "app" {
+		t.Errorf("Dependents(base) = %v, want [app]", deps)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "single.yaml"), "name: single\n")
+	writeFile(t, filepath.Join(dir, "templates.yaml"), `# Shared templates
+templates:
+  - name: a
+    cpu: "1"
+  - name: b # kept
+    cpu: "2"
+`)
+	sources, _, err := NewFileStore(dir).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range sources {
+		if src.Name == "a" || src.Name == "single" {
+			if err := Remove(src); err != nil {
+				t.Fatalf("Remove(%s) error = %v", src.Name, err)
+			}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "single.yaml")); !os.IsNotExist(err) {
+		t.Error("single.yaml should be deleted")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "templates.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# kept") || !strings.Contains(string(data), "# Shared templates") {
+		t.Errorf("comments were lost:\n%s", data)
+	}
+	list, err := NewFileStore(dir).List()
+	if err != nil || len(list) != 1 || list[0].Name != "b" {
+		t.Errorf("List() after Remove() = %v, %v; want only b", list, err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,130 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+)
+
+// commitPattern matches a full commit hash
+var commitPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// GitStore reads templates from a directory of a Git repository, checked
+// out at a pinned ref. A ref that is a full commit hash is verified
+// against the commit fetched.
+type GitStore struct {
+	Repo string
+	Ref  string // branch, tag or commit
+	Path string // directory within the repository
+
+	cache
+}
+
+// NewGitStore creates a store for repo at ref, cached in cacheDir
+func NewGitStore(name, repo, ref, path, cacheDir string) *GitStore {
+	return &GitStore{
+		Repo:  repo,
+		Ref:   ref,
+		Path:  path,
+		cache: cache{name: name, dir: cacheDir, refresh: DefaultRefresh},
+	}
+}
+
+func (s *GitStore) String() string {
+	return s.name + " (" + s.Repo + "@" + s.Ref + ")"
+}
+
+// Load returns the cached templates, fetching them if needed
+func (s *GitStore) Load() ([]*Source, []Problem, error) {
+	return s.load(s.fetch)
+}
+
+// Update fetches the ref again
+func (s *GitStore) Update() (*Manifest, error) {
+	return s.update(s.fetch)
+}
+
+// List returns the source's templates
+func (s *GitStore) List() ([]Template, error) {
+	return NewCatalog(s).List()
+}
+
+// Get returns one of the source's templates
+func (s *GitStore) Get(name string) (*Template, error) {
+	return NewCatalog(s).Get(name)
+}
+
+func (s *GitStore) fetch(dir string) (*Manifest, error) {
+	if !shell.CommandExists("git") {
+		return nil, fmt.Errorf("git not found in PATH")
+	}
+
+	// Clear what a failed fetch may have left, and never leave anything
+	checkout := filepath.Join(dir, ".checkout")
+	if err := os.RemoveAll(checkout); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(checkout)
+
+	// "--" keeps a repo or ref starting with "-" from being read as an option
+	steps := [][]string{
+		{"init", "-q", "--", checkout},
+		{"-C", checkout, "fetch", "-q", "--depth", "1", "--", s.Repo, s.Ref},
+		{"-C", checkout, "checkout", "-q", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		if _, err := git(args...); err != nil {
+			return nil, err
+		}
+	}
+
+	commit, err := git("-C", checkout, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	if commitPattern.MatchString(s.Ref) && commit != s.Ref {
+		return nil, fmt.Errorf("fetched commit %s, but ref pins %s", commit, s.Ref)
+	}
+
+	root := filepath.Join(checkout, filepath.FromSlash(s.Path))
+	if rel, err := filepath.Rel(checkout, root); err != nil || strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("path %q is outside the repository", s.Path)
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", s.Path, s.Ref, err)
+	}
+
+	m := &Manifest{Source: s.Repo, Ref: s.Ref, Commit: commit, Files: make(map[string]string)}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !validTemplateFileName(entry.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if err := writeVerified(m, dir, entry.Name(), data, ""); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// git runs a git command and returns its trimmed standard output
+func git(args ...string) (string, error) {
+	// Never prompt for credentials
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	result, err := shell.ExecuteCommandSplitWithEnv(env, "", "git", args...)
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	if result.ExitCode != 0 {
+		return "", fmt.Errorf("git %s failed: %s", strings.Join(args, " "), strings.TrimSpace(result.Stderr))
+	}
+	return strings.TrimSpace(result.Stdout), nil
+}
@@ -0,0 +1,148 @@
+package templates
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxFetchSize bounds the size of an index or template file
+const maxFetchSize = 1 << 20
+
+// HTTPStore reads templates listed in an index served over HTTP(S).
+// The index lists each template file with its sha256 checksum:
+//
+//	templates:
+//	  - file: gpu.yaml        # relative to the index, or an absolute URL
+//	    sha256: 3b1f...
+type HTTPStore struct {
+	IndexURL string
+	SHA256   string // expected checksum of the index; empty skips the check
+	Client   *http.Client
+
+	cache
+}
+
+// Index is the document served at an HTTPStore's index URL
+type Index struct {
+	Templates []IndexEntry `yaml:"templates"`
+}
+
+// IndexEntry is a template file listed in an Index
+type IndexEntry struct {
+	File   string `yaml:"file"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// NewHTTPStore creates a store for the index at indexURL, cached in cacheDir
+func NewHTTPStore(name, indexURL, sha256, cacheDir string) *HTTPStore {
+	return &HTTPStore{
+		IndexURL: indexURL,
+		SHA256:   sha256,
+		cache:    cache{name: name, dir: cacheDir, refresh: DefaultRefresh},
+	}
+}
+
+func (s *HTTPStore) String() string {
+	return s.name + " (" + s.IndexURL + ")"
+}
+
+// Load returns the cached templates, fetching them if needed
+func (s *HTTPStore) Load() ([]*Source, []Problem, error) {
+	return s.load(s.fetch)
+}
+
+// Update fetches the index and its templates again
+func (s *HTTPStore) Update() (*Manifest, error) {
+	return s.update(s.fetch)
+}
+
+// List returns the source's templates
+func (s *HTTPStore) List() ([]Template, error) {
+	return NewCatalog(s).List()
+}
+
+// Get returns one of the source's templates
+func (s *HTTPStore) Get(name string) (*Template, error) {
+	return NewCatalog(s).Get(name)
+}
+
+func (s *HTTPStore) fetch(dir string) (*Manifest, error) {
+	base, err := url.Parse(s.IndexURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, fmt.Errorf("invalid index URL %q: expected http(s)", s.IndexURL)
+	}
+
+	data, err := s.get(base.String())
+	if err != nil {
+		return nil, err
+	}
+	if s.SHA256 != "" {
+		if sum := sha256Hex(data); sum != s.SHA256 {
+			return nil, fmt.Errorf("index checksum mismatch: got sha256 %s, want %s", sum, s.SHA256)
+		}
+	}
+
+	var index Index
+	if err := yaml.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse index: %w", err)
+	}
+
+	m := &Manifest{Source: s.IndexURL, Files: make(map[string]string)}
+	for _, entry := range index.Templates {
+		ref, err := url.Parse(entry.File)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index entry %q: %w", entry.File, err)
+		}
+		fileURL := base.ResolveReference(ref)
+		name := path.Base(fileURL.Path)
+		if !validTemplateFileName(name) {
+			return nil, fmt.Errorf("invalid index entry %q: expected a .yaml file", entry.File)
+		}
+		if entry.SHA256 == "" {
+			return nil, fmt.Errorf("index entry %q has no sha256 checksum", entry.File)
+		}
+		if _, dup := m.Files[name]; dup {
+			return nil, fmt.Errorf("index lists %s more than once", name)
+		}
+
+		data, err := s.get(fileURL.String())
+		if err != nil {
+			return nil, err
+		}
+		if err := writeVerified(m, dir, name, data, entry.SHA256); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (s *HTTPStore) get(u string) ([]byte, error) {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", u, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", u, err)
+	}
+	if len(data) > maxFetchSize {
+		return nil, fmt.Errorf("failed to fetch %s: larger than %d bytes", u, maxFetchSize)
+	}
+	return data, nil
+}
@@ -0,0 +1,364 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Parameter types
+const (
+	ParamString = "string"
+	ParamInt    = "int"
+	ParamBool   = "bool"
+)
+
+// Parameter is a value supplied when a template is used, with
+// `ghostctl up --template <name> --set <name>=<value>`. Template fields
+// refer to it with Go template syntax, e.g. "{{ .pr }}".
+type Parameter struct {
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Type        string   `yaml:"type,omitempty" json:"type,omitempty"` // string (default), int or bool
+	Default     string   `yaml:"default,omitempty" json:"default,omitempty"`
+	Required    bool     `yaml:"required,omitempty" json:"required,omitempty"`
+	Enum        []string `yaml:"enum,omitempty" json:"enum,omitempty"`   // allowed values
+	Regex       string   `yaml:"regex,omitempty" json:"regex,omitempty"` // pattern the whole value must match
+}
+
+// TypeName returns the parameter's type, defaulting to string
+func (p Parameter) TypeName() string {
+	if p.Type == "" {
+		return ParamString
+	}
+	return p.Type
+}
+
+// parse checks a raw value against the parameter's constraints and
+// converts it to the parameter's type
+func (p Parameter) parse(raw string) (interface{}, error) {
+	if len(p.Enum) > 0 {
+		allowed := false
+		for _, v := range p.Enum {
+			if v == raw {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("parameter %q: %q is not one of %s", p.Name, raw, strings.Join(p.Enum, ", "))
+		}
+	}
+	if p.Regex != "" {
+		re, err := regexp.Compile("^(?:" + p.Regex + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: invalid regex: %w", p.Name, err)
+		}
+		if !re.MatchString(raw) {
+			return nil, fmt.Errorf("parameter %q: %q does not match %s", p.Name, raw, p.Regex)
+		}
+	}
+
+	switch p.TypeName() {
+	case ParamString:
+		return raw, nil
+	case ParamInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %q is not an integer", p.Name, raw)
+		}
+		return n, nil
+	case ParamBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %q: %q is not a boolean", p.Name, raw)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("parameter %q: unknown type %q (expected string, int or bool)", p.Name, p.Type)
+	}
+}
+
+// ParameterValues validates the values given with --set against the
+// declared parameters and returns the typed values templates are rendered
+// with. Unset parameters take their default.
+func ParameterValues(params []Parameter, set map[string]string) (map[string]interface{}, error) {
+	declared := make(map[string]bool, len(params))
+	for _, p := range params {
+		declared[p.Name] = true
+	}
+	for name := range set {
+		if !declared[name] {
+			return nil, fmt.Errorf("unknown parameter %q%s", name, declaredHint(params))
+		}
+	}
+
+	values := make(map[string]interface{}, len(params))
+	for _, p := range params {
+		raw, ok := set[p.Name]
+		if !ok {
+			if p.Required {
+				return nil, fmt.Errorf("parameter %q is required; set it with --set %s=<value>", p.Name, p.Name)
+			}
+			if p.Default == "" && p.TypeName() == ParamString {
+				values[p.Name] = ""
+				continue
+			}
+			if p.Default == "" {
+				raw = zeroValue(p.TypeName())
+			} else {
+				raw = p.Default
+			}
+		}
+
+		v, err := p.parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		values[p.Name] = v
+	}
+	return values, nil
+}
+
+func zeroValue(typ string) string {
+	switch typ {
+	case ParamInt:
+		return "0"
+	case ParamBool:
+		return "false"
+	}
+	return ""
+}
+
+func declaredHint(params []Parameter) string {
+	if len(params) == 0 {
+		return " (the template declares no parameters)"
+	}
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Name
+	}
+	return fmt.Sprintf(" (expected one of %s)", strings.Join(names, ", "))
+}
+
+// ParseSetFlags parses repeated --set name=value flags
+func ParseSetFlags(values []string) (map[string]string, error) {
+	set := make(map[string]string, len(values))
+	for _, value := range values {
+		name, val, ok := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --set %q: expected name=value", value)
+		}
+		set[name] = val
+	}
+	return set, nil
+}
+
+// Render validates set against the template's parameters and returns the
+// template with every templated field rendered
+func (r *Resolution) Render(set map[string]string) (*Template, error) {
+	params := r.Template.Parameters
+	values, err := ParameterValues(params, set)
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %w", r.Chain[0], err)
+	}
+
+	rendered, err := renderValues(r.Values, values, false)
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %w", r.Chain[0], err)
+	}
+
+	tmpl, err := decodeTemplate(rendered.(map[string]interface{}))
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %w", r.Chain[0], err)
+	}
+	tmpl.Extends = r.Template.Extends
+	return tmpl, nil
+}
+
+// preview renders the fields that can be rendered from parameter defaults
+// alone, dropping the ones that need a value from --set
+func preview(values map[string]interface{}, params []Parameter) map[string]interface{} {
+	if len(params) == 0 {
+		return values
+	}
+
+	data := make(map[string]interface{}, len(params))
+	for _, p := range params {
+		raw := p.Default
+		if raw == "" {
+			if p.Required {
+				continue
+			}
+			raw = zeroValue(p.TypeName())
+		}
+		if v, err := p.parse(raw); err == nil {
+			data[p.Name] = v
+		}
+	}
+
+	rendered, _ := renderValues(values, data, true)
+	return rendered.(map[string]interface{})
+}
+
+// renderValues executes every string in v as a Go template. The parameter
+// declarations themselves are left alone. In lenient mode, fields that fail
+// to render are dropped instead of failing.
+func renderValues(v interface{}, data map[string]interface{}, lenient bool) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if key == "parameters" {
+				out[key] = value
+				continue
+			}
+			rendered, err := renderValues(value, data, lenient)
+			if err != nil {
+				if lenient {
+					continue
+				}
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			out[key] = rendered
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for i, value := range v {
+			rendered, err := renderValues(value, data, lenient)
+			if err != nil {
+				if lenient {
+					continue
+				}
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			out = append(out, rendered)
+		}
+		return out, nil
+	case string:
+		return renderString(v, data)
+	default:
+		return v, nil
+	}
+}
+
+// paramRef matches a string that is nothing but a reference to one
+// parameter, e.g. "{{ .replicas }}"
+var paramRef = regexp.MustCompile(`^\{\{-?\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*-?\}\}$`)
+
+// renderString executes s as a Go template. A string that only references a
+// parameter takes the parameter's type, so "{{ .replicas }}" fills chart
+// values with an integer; anything else renders to a string exactly as
+// written, so an image tag of 1.20 stays "1.20".
+func renderString(s string, data map[string]interface{}) (interface{}, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	if m := paramRef.FindStringSubmatch(s); m != nil {
+		if v, ok := data[m[1]]; ok {
+			return v, nil
+		}
+	}
+
+	t, err := template.New("").Option("missingkey=error").Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.String(), nil
+}
+
+// coerceTyped converts strings to int or bool where the field of t they fill
+// has that type, so "{{ if .big }}2{{ else }}1{{ end }}" can fill gpu. Values
+// of every other field, including labels and chart values, are left as they
+// are. v is not modified.
+func coerceTyped(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		fields := yamlFields(t)
+		out := make(map[string]interface{}, len(m))
+		for key, value := range m {
+			if ft, ok := fields[key]; ok {
+				value = coerceTyped(value, ft)
+			}
+			out[key] = value
+		}
+		return out
+	case reflect.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		out := make(map[string]interface{}, len(m))
+		for key, value := range m {
+			out[key] = coerceTyped(value, t.Elem())
+		}
+		return out
+	case reflect.Slice:
+		list, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		out := make([]interface{}, len(list))
+		for i, value := range list {
+			out[i] = coerceTyped(value, t.Elem())
+		}
+		return out
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		if s, ok := v.(string); ok {
+			if n, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+				return n
+			}
+		}
+	case reflect.Bool:
+		if s, ok := v.(string); ok {
+			if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+				return b
+			}
+		}
+	}
+	return v
+}
+
+// yamlFields returns the types of the fields of struct type t by YAML key,
+// including the fields of inlined structs
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if strings.Contains(opts, "inline") {
+			for key, ft := range yamlFields(f.Type) {
+				fields[key] = ft
+			}
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
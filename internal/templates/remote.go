@@ -0,0 +1,244 @@
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/telemetry"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+)
+
+const (
+	// manifestFileName records what a cache directory was fetched from
+	manifestFileName = ".ghostctl-source.json"
+
+	// DefaultRefresh is how often remote template sources are refreshed
+	DefaultRefresh = 24 * time.Hour
+)
+
+// Remote is a template store fetched from a remote source into a local cache
+type Remote interface {
+	Store
+	Loader
+	// SourceName returns the name of the source in the config
+	SourceName() string
+	// Update fetches the source again, replacing the cache
+	Update() (*Manifest, error)
+}
+
+// Manifest records where a cached template source came from and the
+// checksum of every file fetched
+type Manifest struct {
+	Source    string            `json:"source"`
+	Ref       string            `json:"ref,omitempty"`
+	Commit    string            `json:"commit,omitempty"`
+	FetchedAt time.Time         `json:"fetchedAt"`
+	Files     map[string]string `json:"files"` // file name -> sha256
+}
+
+// DefaultCacheDir returns $HOME/.ghost/cache/templates
+func DefaultCacheDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".ghost", "cache", "templates")
+}
+
+// OpenRemotes creates the stores for the configured template sources,
+// caching each under cacheDir/<name>
+func OpenRemotes(sources []config.TemplateSource, cacheDir string) ([]Remote, error) {
+	seen := make(map[string]bool)
+	var remotes []Remote
+	for _, src := range sources {
+		if src.Name == "" || strings.ContainsAny(src.Name, `/\`) || src.Name == "." || src.Name == ".." {
+			return nil, fmt.Errorf("template source %q: name must be set and must not contain a path separator", src.Name)
+		}
+		if seen[src.Name] {
+			return nil, fmt.Errorf("template source %q is configured twice", src.Name)
+		}
+		seen[src.Name] = true
+
+		refresh := DefaultRefresh
+		if src.Refresh == "0" {
+			refresh = 0
+		} else if src.Refresh != "" {
+			d, err := utils.ParseDuration(src.Refresh)
+			if err != nil {
+				return nil, fmt.Errorf("template source %q: invalid refresh: %w", src.Name, err)
+			}
+			refresh = d
+		}
+		dir := filepath.Join(cacheDir, src.Name)
+
+		switch {
+		case src.URL != "" && src.Git != "":
+			return nil, fmt.Errorf("template source %q: set either url or git, not both", src.Name)
+		case src.URL != "":
+			store := NewHTTPStore(src.Name, src.URL, src.SHA256, dir)
+			store.refresh = refresh
+			remotes = append(remotes, store)
+		case src.Git != "":
+			if src.Ref == "" {
+				return nil, fmt.Errorf("template source %q: git sources need a ref to pin", src.Name)
+			}
+			store := NewGitStore(src.Name, src.Git, src.Ref, src.Path, dir)
+			store.refresh = refresh
+			remotes = append(remotes, store)
+		default:
+			return nil, fmt.Errorf("template source %q: url or git is required", src.Name)
+		}
+	}
+	return remotes, nil
+}
+
+// cache holds the fetched files of a remote source
+type cache struct {
+	name    string
+	dir     string
+	refresh time.Duration
+}
+
+func (c *cache) SourceName() string {
+	return c.name
+}
+
+// LayerName names the source in template listings
+func (c *cache) LayerName() string {
+	return "source:" + c.name
+}
+
+func (c *cache) manifest() (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, manifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("template source %q: corrupt cache manifest: %w", c.name, err)
+	}
+	return &m, nil
+}
+
+// update fetches into a fresh directory and swaps it in, so a failed fetch
+// leaves the previous cache intact
+func (c *cache) update(fetch func(dir string) (*Manifest, error)) (*Manifest, error) {
+	if err := os.MkdirAll(filepath.Dir(c.dir), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create template cache: %w", err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(c.dir), c.name+".tmp-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template cache: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	m, err := fetch(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("template source %q: %w", c.name, err)
+	}
+	m.FetchedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmp, manifestFileName), data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write cache manifest: %w", err)
+	}
+
+	old := c.dir + ".old"
+	os.RemoveAll(old)
+	if err := os.Rename(c.dir, old); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to replace template cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.dir); err != nil {
+		os.Rename(old, c.dir)
+		return nil, fmt.Errorf("failed to replace template cache: %w", err)
+	}
+	os.RemoveAll(old)
+	return m, nil
+}
+
+// load returns the cached templates, fetching them first if they are not
+// cached or are due for a refresh. An unreachable source is served from
+// the cache.
+func (c *cache) load(fetch func(dir string) (*Manifest, error)) ([]*Source, []Problem, error) {
+	m, err := c.manifest()
+	switch {
+	case os.IsNotExist(err):
+		if m, err = c.update(fetch); err != nil {
+			return nil, nil, fmt.Errorf("%w (not cached yet)", err)
+		}
+	case err != nil:
+		return nil, nil, err
+	case c.refresh > 0 && time.Since(m.FetchedAt) > c.refresh:
+		if fresh, err := c.update(fetch); err != nil {
+			telemetry.GetLogger().Warn("Using cached templates; source unreachable",
+				"source", c.name, "fetchedAt", m.FetchedAt.Format(time.RFC3339), "error", err)
+		} else {
+			m = fresh
+		}
+	}
+
+	// Serve only files that still match the checksums they were fetched with
+	var sources []*Source
+	var problems []Problem
+	names := make([]string, 0, len(m.Files))
+	for name := range m.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(c.dir, name)
+		sum, err := fileSHA256(path)
+		if err != nil {
+			problems = append(problems, Problem{File: path, Message: err.Error()})
+			continue
+		}
+		if sum != m.Files[name] {
+			problems = append(problems, Problem{File: path, Message: fmt.Sprintf("checksum mismatch in cache of template source %q; run 'ghostctl templates update'", c.name)})
+			continue
+		}
+		loaded, fileProblems := LoadFile(path)
+		sources = append(sources, loaded...)
+		problems = append(problems, fileProblems...)
+	}
+	return sources, problems, nil
+}
+
+// writeVerified writes data into dir/name after checking it against want,
+// and records it in the manifest
+func writeVerified(m *Manifest, dir, name string, data []byte, want string) error {
+	sum := sha256Hex(data)
+	if want != "" && !strings.EqualFold(sum, want) {
+		return fmt.Errorf("checksum mismatch for %s: got sha256 %s, want %s", name, sum, want)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+		return err
+	}
+	m.Files[name] = sum
+	return nil
+}
+
+// validTemplateFileName reports whether name can be written into a cache
+// directory as a template file
+func validTemplateFileName(name string) bool {
+	return name == filepath.Base(name) && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, ".yaml")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func fileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return sha256Hex(data), nil
+}
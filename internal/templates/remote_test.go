@@ -0,0 +1,227 @@
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+)
+
+func sum(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func TestHTTPStore(t *testing.T) {
+	web := "name: web\ncpu: \"1\"\nmemory: 1Gi\n"
+	files := map[string]string{
+		"/catalog/index.yaml": fmt.Sprintf("templates:\n  - file: web.yaml\n    sha256: %s\n", sum(web)),
+		"/catalog/web.yaml":   web,
+	}
+	up := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !up || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, content)
+	}))
+	defer srv.Close()
+
+	cacheDir := filepath.Join(t.TempDir(), "platform")
+	store := NewHTTPStore("platform", srv.URL+"/catalog/index.yaml", "", cacheDir)
+
+	// The first use fetches into the cache
+	tmpl, err := store.Get("web")
+	if err != nil || tmpl.Memory != "1Gi" {
+		t.Fatalf("Get(web) = %+v, %v", tmpl, err)
+	}
+
+	// Unreachable sources are served from the cache, even when stale
+	up = false
+	store.refresh = time.Nanosecond
+	if _, err := store.Get("web"); err != nil {
+		t.Errorf("Get(web) while offline error = %v, want the cached template", err)
+	}
+	if _, err := store.Update(); err == nil {
+		t.Error("Update() while offline should fail")
+	}
+	up = true
+	store.refresh = 0
+
+	// A file that does not match the index checksum is rejected, and the
+	// previous cache is kept
+	files["/catalog/web.yaml"] = web + "gpu: 8\n"
+	if _, err := store.Update(); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Update() error = %v, want a checksum mismatch", err)
+	}
+	if tmpl, err := store.Get("web"); err != nil || tmpl.GPU != 0 {
+		t.Errorf("Get(web) after failed update = %+v, %v; want the cached template", tmpl, err)
+	}
+
+	// Files changed in the cache are not served
+	if err := os.WriteFile(filepath.Join(cacheDir, "web.yaml"), []byte(web+"gpu: 8\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("web"); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("Get(web) on a modified cache error = %v, want a checksum mismatch", err)
+	}
+
+	// A pinned index checksum is enforced
+	pinned := NewHTTPStore("pinned", srv.URL+"/catalog/index.yaml", sum("other"), filepath.Join(t.TempDir(), "pinned"))
+	if _, err := pinned.Update(); err == nil || !strings.Contains(err.Error(), "index checksum mismatch") {
+		t.Errorf("Update() error = %v, want an index checksum mismatch", err)
+	}
+}
+
+func TestGitStore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(repo, "templates", "ml.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "-q")
+	if err := os.MkdirAll(filepath.Join(repo, "templates"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write("name: ml\ngpu: 1\n")
+	run("add", ".")
+	run("commit", "-qm", "v1")
+	run("tag", "v1")
+	commit := run("rev-parse", "HEAD")
+
+	// A later commit does not move the pinned tag
+	write("name: ml\ngpu: 2\n")
+	run("commit", "-qam", "v2")
+
+	store := NewGitStore("ml", repo, "v1", "templates", filepath.Join(t.TempDir(), "ml"))
+	m, err := store.Update()
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if m.Commit != commit || len(m.Files) != 1 {
+		t.Errorf("manifest = %+v, want commit %s with one file", m, commit)
+	}
+	if tmpl, err := store.Get("ml"); err != nil || tmpl.GPU != 1 {
+		t.Errorf("Get(ml) = %+v, %v; want the template at v1", tmpl, err)
+	}
+
+	missingDir := filepath.Join(t.TempDir(), "missing")
+	missing := NewGitStore("ml", repo, "no-such-ref", "templates", missingDir)
+	if _, err := missing.Update(); err == nil {
+		t.Error("Update() of an unknown ref should fail")
+	}
+	_ = filepath.Walk(missingDir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.Name() == ".checkout" {
+			t.Errorf("failed Update() left %s behind", path)
+		}
+		return nil
+	})
+
+	// A repo starting with "-" is a repo, not an option
+	marker := filepath.Join(t.TempDir(), "marker")
+	option := NewGitStore("ml", "--upload-pack=touch "+marker, "v1", "templates", filepath.Join(t.TempDir(), "option"))
+	if _, err := option.Update(); err == nil {
+		t.Error("Update() of an option-like repo should fail")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("Update() passed the repo to git as an option")
+	}
+}
+
+func TestOpenRemotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []config.TemplateSource
+		wantErr string
+	}{
+		{"http and git", []config.TemplateSource{
+			{Name: "a", URL: "https://example.com/index.yaml"},
+			{Name: "b", Git: "https://example.com/t.git", Ref: "v1", Refresh: "12h"},
+		}, ""},
+		{"missing name", []config.TemplateSource{{URL: "https://example.com/index.yaml"}}, "name must be set"},
+		{"duplicate", []config.TemplateSource{{Name: "a", URL: "u"}, {Name: "a", URL: "u"}}, "configured twice"},
+		{"both kinds", []config.TemplateSource{{Name: "a", URL: "u", Git: "g", Ref: "v1"}}, "not both"},
+		{"unpinned git", []config.TemplateSource{{Name: "a", Git: "g"}}, "need a ref"},
+		{"bad refresh", []config.TemplateSource{{Name: "a", URL: "u", Refresh: "soon"}}, "invalid refresh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remotes, err := OpenRemotes(tt.sources, t.TempDir())
+			if tt.wantErr == "" {
+				if err != nil || len(remotes) != len(tt.sources) {
+					t.Errorf("OpenRemotes() = %v, %v", remotes, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("OpenRemotes() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCatalogShadowing(t *testing.T) {
+	local, shared := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(local, "gpu.yaml"):  "name: gpu\ngpu: 2\n",
+		filepath.Join(shared, "gpu.yaml"): "name: gpu\ngpu: 1\n",
+		filepath.Join(shared, "web.yaml"): "name: web\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	catalog := NewCatalog(
+		&FileStore{BaseDir: local, Layer: LayerUser},
+		&FileStore{BaseDir: shared, Layer: LayerSystem},
+		NewFileStore(filepath.Join(local, "missing")),
+	)
+	sources, err := catalog.Sources()
+	if err != nil {
+		t.Fatalf("Sources() error = %v", err)
+	}
+	for _, src := range sources {
+		if src.Name == "gpu" && (src.Layer != LayerUser || len(src.Shadows) != 1 || src.Shadows[0].Layer != LayerSystem) {
+			t.Errorf("gpu layer = %s shadowing %v, want user shadowing system", src.Layer, src.Shadows)
+		}
+	}
+
+	list, err := catalog.List()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("List() = %v, %v; want gpu and web", list, err)
+	}
+	if tmpl, _ := catalog.Get("gpu"); tmpl == nil || tmpl.GPU != 2 {
+		t.Errorf("Get(gpu) = %+v, want the first store's definition", tmpl)
+	}
+	if _, err := catalog.Render("gpu", nil); err != nil {
+		t.Errorf("Render(gpu) error = %v; shadowed definitions are not duplicates", err)
+	}
+}
@@ -0,0 +1,302 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Origin records where a resolved template value was defined
+type Origin struct {
+	Template string `json:"template" yaml:"template"`
+	File     string `json:"file" yaml:"file"`
+}
+
+// Resolution is a template with its inheritance chain applied
+type Resolution struct {
+	// Template is the resolved template. Fields using parameters are
+	// rendered with the parameter defaults; see Render.
+	Template *Template
+	// Values holds the merged fields, without extends
+	Values map[string]interface{}
+	// Origins maps each leaf field, as a dotted path such as "labels.tier",
+	// to where its value was defined
+	Origins map[string]Origin
+	// Chain lists the template and its ancestors, nearest first
+	Chain []string
+}
+
+// Resolve applies the inheritance chain of the named template. Nested
+// mappings such as labels are deep-merged; any other value in a child
+// replaces the parent's.
+func Resolve(sources []*Source, name string) (*Resolution, error) {
+	byName := make(map[string]*Source, len(sources))
+	for _, src := range sources {
+		if _, exists := byName[src.Name]; !exists {
+			byName[src.Name] = src
+		}
+	}
+
+	// Walk up the chain, detecting cycles
+	var chain []*Source
+	seen := make(map[string]bool)
+	for current := name; current != ""; {
+		src, ok := byName[current]
+		if !ok {
+			if len(chain) == 0 {
+				return nil, fmt.Errorf("template %q not found; run 'ghostctl templates' to see available templates", name)
+			}
+			return nil, fmt.Errorf("template %q extends unknown template %q", chain[len(chain)-1].Name, current)
+		}
+		if seen[current] {
+			var names []string
+			for _, s := range chain {
+				names = append(names, s.Name)
+			}
+			return nil, fmt.Errorf("template inheritance cycle: %s -> %s", strings.Join(names, " -> "), current)
+		}
+		seen[current] = true
+		chain = append(chain, src)
+		current = src.Extends()
+	}
+
+	res := &Resolution{
+		Values:  make(map[string]interface{}),
+		Origins: make(map[string]Origin),
+	}
+	for _, src := range chain {
+		res.Chain = append(res.Chain, src.Name)
+	}
+
+	// Apply from the root ancestor down to the template itself
+	for i := len(chain) - 1; i >= 0; i-- {
+		src := chain[i]
+		origin := Origin{Template: src.Name, File: src.File}
+		mergeValues(res.Values, src.Values, "", origin, res.Origins)
+	}
+	delete(res.Values, "extends")
+	delete(res.Origins, "extends")
+
+	// Fields that use parameters are filled in from their defaults
+	params, err := decodeParameters(res.Values)
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %w", name, err)
+	}
+	tmpl, err := decodeTemplate(preview(res.Values, params))
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %w", name, err)
+	}
+	tmpl.Extends = chain[0].Extends()
+	res.Template = tmpl
+	return res, nil
+}
+
+// ResolveAll resolves every template in sources. Templates that cannot be
+// resolved, e.g. because they extend a template that does not exist, are
+// left out and reported as problems.
+func ResolveAll(sources []*Source) ([]Template, []Problem) {
+	var problems []Problem
+	var templates []Template
+	seen := make(map[string]bool)
+	for _, src := range sources {
+		if seen[src.Name] {
+			continue
+		}
+		seen[src.Name] = true
+
+		res, err := Resolve(sources, src.Name)
+		if err != nil {
+			problems = append(problems, Problem{File: src.File, Template: src.Name, Message: err.Error()})
+			continue
+		}
+		templates = append(templates, *res.Template)
+	}
+	return templates, problems
+}
+
+// mergeValues deep-merges src into dst, recording the origin of every leaf
+// value taken from src
+func mergeValues(dst, src map[string]interface{}, prefix string, origin Origin, origins map[string]Origin) {
+	for key, value := range src {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		srcMap, srcIsMap := value.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeValues(dstMap, srcMap, path, origin, origins)
+			continue
+		}
+		if path == "parameters" {
+			// Parameters are merged by name so a child can add its own
+			// without redeclaring its parent's
+			dst[key] = mergeParameters(dst[key], value)
+			origins[path] = origin
+			continue
+		}
+		if path == "valuesFiles" {
+			// A child's values files apply after its parents'
+			dst[key] = mergeValuesFiles(dst[key], value, filepath.Dir(origin.File))
+			origins[path] = origin
+			continue
+		}
+
+		// The new value replaces whatever was there, including its origins
+		for p := range origins {
+			if p == path || strings.HasPrefix(p, path+".") {
+				delete(origins, p)
+			}
+		}
+		if srcIsMap {
+			copied := make(map[string]interface{}, len(srcMap))
+			dst[key] = copied
+			mergeValues(copied, srcMap, path, origin, origins)
+			continue
+		}
+		dst[key] = value
+		origins[path] = origin
+	}
+}
+
+// mergeParameters merges two parameter lists by name; entries in child
+// replace parent entries with the same name
+func mergeParameters(parent, child interface{}) interface{} {
+	parentList, ok1 := parent.([]interface{})
+	childList, ok2 := child.([]interface{})
+	if !ok1 || !ok2 {
+		return child
+	}
+
+	paramName := func(p interface{}) string {
+		m, _ := p.(map[string]interface{})
+		name, _ := m["name"].(string)
+		return name
+	}
+
+	merged := append([]interface{}(nil), parentList...)
+	for _, p := range childList {
+		replaced := false
+		for i, existing := range merged {
+			if name := paramName(p); name != "" && paramName(existing) == name {
+				merged[i] = p
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, p)
+		}
+	}
+	return merged
+}
+
+// mergeValuesFiles appends the values files of a child template to its
+// parent's, making the child's paths relative to dir absolute
+func mergeValuesFiles(parent, child interface{}, dir string) interface{} {
+	childList, ok := child.([]interface{})
+	if !ok {
+		return child
+	}
+	merged, _ := parent.([]interface{})
+	merged = append([]interface{}(nil), merged...)
+	for _, f := range childList {
+		if path, ok := f.(string); ok {
+			f = ResolveValuesFile(dir, path)
+		}
+		merged = append(merged, f)
+	}
+	return merged
+}
+
+// ResolveValuesFile returns the path of a values file referenced from a
+// template file in dir
+func ResolveValuesFile(dir, path string) string {
+	path = expandHome(path)
+	if path == "" || filepath.IsAbs(path) || strings.Contains(path, "{{") {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// decodeTemplate converts merged values into a Template
+func decodeTemplate(values map[string]interface{}) (*Template, error) {
+	data, err := yaml.Marshal(coerceTyped(values, reflect.TypeOf(Template{})))
+	if err != nil {
+		return nil, err
+	}
+	var tmpl Template
+	if err := yaml.Unmarshal(data, &tmpl); err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// decodeParameters returns the parameters declared in values
+func decodeParameters(values map[string]interface{}) ([]Parameter, error) {
+	var decoded struct {
+		Parameters []Parameter `yaml:"parameters"`
+	}
+	data, err := yaml.Marshal(map[string]interface{}{"parameters": values["parameters"]})
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("parameters: %w", err)
+	}
+	return decoded.Parameters, nil
+}
+
+// AnnotatedYAML renders the resolved values as YAML, with a comment on each
+// leaf naming the file it came from
+func (r *Resolution) AnnotatedYAML(displayPath func(string) string) ([]byte, error) {
+	if displayPath == nil {
+		displayPath = func(p string) string { return p }
+	}
+
+	var node yaml.Node
+	if err := node.Encode(r.Values); err != nil {
+		return nil, err
+	}
+	annotateOrigins(&node, "", r.Origins, displayPath)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Resolved template %s (%s)\n", r.Chain[0], strings.Join(r.Chain, " <- "))
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func annotateOrigins(node *yaml.Node, prefix string, origins map[string]Origin, displayPath func(string) string) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+		if origin, ok := origins[path]; ok {
+			comment := "# " + displayPath(origin.File)
+			if value.Kind == yaml.ScalarNode {
+				value.LineComment = comment
+			} else {
+				key.LineComment = comment
+			}
+			continue
+		}
+		annotateOrigins(value, path, origins, displayPath)
+	}
+}
@@ -0,0 +1,143 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// TemplatePathEnv overrides the configured template search path. It
+	// holds directories separated by the OS path list separator.
+	TemplatePathEnv = "GHOSTCTL_TEMPLATE_PATH"
+
+	// RepoTemplatesDir is where a repository keeps its own templates
+	RepoTemplatesDir = ".ghostctl/templates"
+)
+
+// Layer names of the default search path
+const (
+	LayerRepo    = "repo"
+	LayerUser    = "user"
+	LayerBundled = "bundled"
+	LayerSystem  = "system"
+)
+
+// Layer is a directory in the template search path
+type Layer struct {
+	Name string
+	Dir  string
+}
+
+// SearchPath returns the template directories in priority order: the
+// current repository's .ghostctl/templates, then $GHOSTCTL_TEMPLATE_PATH,
+// or else the configured path, or else the default path. Templates in
+// earlier layers override same-named templates in later ones.
+func SearchPath(configured []string) []Layer {
+	var layers []Layer
+	if cwd, err := os.Getwd(); err == nil {
+		if dir := FindRepoTemplates(cwd); dir != "" {
+			layers = append(layers, Layer{Name: LayerRepo, Dir: dir})
+		}
+	}
+
+	if env := os.Getenv(TemplatePathEnv); env != "" {
+		configured = filepath.SplitList(env)
+	}
+	if len(configured) == 0 {
+		return dedupeLayers(append(layers, DefaultSearchPath()...))
+	}
+
+	for _, dir := range configured {
+		if dir = expandHome(strings.TrimSpace(dir)); dir != "" {
+			layers = append(layers, Layer{Name: dir, Dir: dir})
+		}
+	}
+	return dedupeLayers(layers)
+}
+
+// DefaultSearchPath returns the user's templates, the templates shipped
+// next to the binary, and system-wide installations, in that order. The
+// working directory is not searched: a repository's own templates live in
+// .ghostctl/templates, and a templates/ directory in the working directory
+// is usually something else, such as a Helm chart's.
+func DefaultSearchPath() []Layer {
+	layers := []Layer{{Name: LayerUser, Dir: UserTemplatesDir()}}
+
+	// Development and release archives: templates/ next to the binary
+	if execPath, err := os.Executable(); err == nil {
+		execDir := filepath.Dir(execPath)
+		layers = append(layers,
+			Layer{Name: LayerBundled, Dir: filepath.Join(execDir, "..", "templates")},
+			Layer{Name: LayerBundled, Dir: filepath.Join(execDir, "templates")},
+		)
+	}
+
+	return append(layers,
+		Layer{Name: LayerSystem, Dir: "/opt/homebrew/share/ghostctl/templates"},
+		Layer{Name: LayerSystem, Dir: "/usr/local/share/ghostctl/templates"},
+	)
+}
+
+// UserTemplatesDir returns the directory holding the user's own templates,
+// where ghostctl templates create writes new ones
+func UserTemplatesDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".ghost", "templates")
+}
+
+// FindRepoTemplates looks for .ghostctl/templates in dir and its parents,
+// up to the root of the repository containing dir
+func FindRepoTemplates(dir string) string {
+	for {
+		candidate := filepath.Join(dir, filepath.FromSlash(RepoTemplatesDir))
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return "" // repository root
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Stores returns a FileStore for each layer
+func Stores(layers []Layer) []Loader {
+	loaders := make([]Loader, len(layers))
+	for i, l := range layers {
+		loaders[i] = &FileStore{BaseDir: l.Dir, Layer: l.Name}
+	}
+	return loaders
+}
+
+// dedupeLayers drops layers whose directory already appears earlier
+func dedupeLayers(layers []Layer) []Layer {
+	seen := make(map[string]bool)
+	var out []Layer
+	for _, l := range layers {
+		dir := filepath.Clean(l.Dir)
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			dir = resolved
+		}
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		out = append(out, Layer{Name: l.Name, Dir: dir})
+	}
+	return out
+}
+
+// expandHome expands a leading ~/ to the home directory
+func expandHome(dir string) string {
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		return filepath.Join(os.Getenv("HOME"), strings.TrimPrefix(dir, "~"))
+	}
+	return dir
+}
@@ -0,0 +1,155 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source is a single template document as written in a file, before
+// inheritance is resolved
+type Source struct {
+	Name   string
+	File   string
+	Line   int                    // line of the template in File
+	Node   *yaml.Node             // the template's mapping node
+	Values map[string]interface{} // the template's fields as written
+
+	// Layer names the search path layer or remote source the template was
+	// loaded from, and Shadows the same-named templates it overrides in
+	// later layers. Both are set by Catalog.Load.
+	Layer   string
+	Shadows []*Source
+}
+
+// Extends returns the name of the template this one inherits from
+func (s *Source) Extends() string {
+	extends, _ := s.Values["extends"].(string)
+	return extends
+}
+
+// LoadFile reads the templates in a file: a list under "templates" for a
+// file named templates.yaml, otherwise a single template whose name
+// defaults to the file name
+func LoadFile(path string) ([]*Source, []Problem) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, []Problem{{File: path, Message: err.Error()}}
+	}
+	return ParseFile(path, data)
+}
+
+// ParseFile parses the contents of a template file as LoadFile would read
+// it from path
+func ParseFile(path string, data []byte) ([]*Source, []Problem) {
+	if filepath.Base(path) == "templates.yaml" {
+		return parseTemplatesFile(path, data)
+	}
+	src, problem := parseTemplateFile(path, data)
+	if problem != nil {
+		return nil, []Problem{*problem}
+	}
+	return []*Source{src}, nil
+}
+
+// parseTemplateFile parses a file holding a single template
+func parseTemplateFile(path string, data []byte) (*Source, *Problem) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, yamlProblem(path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, &Problem{File: path, Message: "empty template file"}
+	}
+
+	src, problem := newSource(path, doc.Content[0])
+	if problem != nil {
+		return nil, problem
+	}
+	if src.Name == "" {
+		src.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		src.Values["name"] = src.Name
+	}
+	return src, nil
+}
+
+// parseTemplatesFile parses a templates.yaml file holding a list of templates
+func parseTemplatesFile(path string, data []byte) ([]*Source, []Problem) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, []Problem{*yamlProblem(path, err)}
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	root := doc.Content[0]
+	list := mappingValue(root, "templates")
+	if list == nil {
+		return nil, []Problem{{File: path, Line: root.Line, Message: "expected a list of templates under \"templates\""}}
+	}
+	if list.Kind != yaml.SequenceNode {
+		return nil, []Problem{{File: path, Line: list.Line, Message: "templates must be a list"}}
+	}
+
+	var sources []*Source
+	var problems []Problem
+	for _, node := range list.Content {
+		src, problem := newSource(path, node)
+		if problem != nil {
+			problems = append(problems, *problem)
+			continue
+		}
+		if src.Name == "" {
+			problems = append(problems, Problem{File: path, Line: node.Line, Message: "template has no name"})
+			continue
+		}
+		sources = append(sources, src)
+	}
+	return sources, problems
+}
+
+// yamlLinePattern finds the line number in a yaml.v3 error
+var yamlLinePattern = regexp.MustCompile(`line (\d+): `)
+
+// yamlProblem converts a YAML parse error into a problem
+func yamlProblem(path string, err error) *Problem {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	problem := &Problem{File: path, Message: msg}
+	if m := yamlLinePattern.FindStringSubmatchIndex(msg); m != nil {
+		problem.Line, _ = strconv.Atoi(msg[m[2]:m[3]])
+		problem.Message = msg[:m[0]] + msg[m[1]:]
+	}
+	return problem
+}
+
+func newSource(path string, node *yaml.Node) (*Source, *Problem) {
+	if node.Kind != yaml.MappingNode {
+		return nil, &Problem{File: path, Line: node.Line, Message: "template must be a mapping"}
+	}
+
+	values := make(map[string]interface{})
+	if err := node.Decode(&values); err != nil {
+		return nil, &Problem{File: path, Line: node.Line, Message: err.Error()}
+	}
+
+	name, _ := values["name"].(string)
+	return &Source{Name: name, File: path, Line: node.Line, Node: node, Values: values}, nil
+}
+
+// mappingValue returns the value node for key in a mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -6,13 +6,14 @@ import (
 	"path/filepath"
 	"strings"
 
-	"gopkg.in/yaml.v3"
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
 )
 
 // Template represents a cluster configuration template
 type Template struct {
 	Name        string            `yaml:"name"`
-	Description string            `yaml:"description"`
+	Description string            `yaml:"description,omitempty"`
+	Extends     string            `yaml:"extends,omitempty"` // template this one inherits from
 	Labels      map[string]string `yaml:"labels,omitempty"`
 
 	CPU     string `yaml:"cpu,omitempty"`     // e.g. "2"
@@ -21,6 +22,21 @@ type Template struct {
 	GPU     int    `yaml:"gpu,omitempty"`
 	GPUType string `yaml:"gpuType,omitempty"` // e.g. "nvidia-t4"
 	TTL     string `yaml:"ttl,omitempty"`     // e.g. "1h"
+
+	// Security isolates clusters created from the template
+	Security *cluster.Security `yaml:"security,omitempty"`
+
+	// Scheduling adds nodeSelector, tolerations, affinity,
+	// priorityClassName and architecture
+	cluster.Scheduling `yaml:",inline"`
+
+	// VClusterValues are vCluster chart values merged over the values
+	// ghostctl generates, and ValuesFiles name values files merged before
+	// them. Relative paths are relative to the file defining the template.
+	VClusterValues map[string]interface{} `yaml:"vclusterValues,omitempty"`
+	ValuesFiles    []string               `yaml:"valuesFiles,omitempty"`
+
+	Parameters []Parameter `yaml:"parameters,omitempty"`
 }
 
 // Store interface for template management
@@ -32,6 +48,7 @@ type Store interface {
 // FileStore implements Store by reading templates from the filesystem
 type FileStore struct {
 	BaseDir string
+	Layer   string // name of the search path layer; defaults to BaseDir
 }
 
 // TemplatesFile represents a multi-template YAML file
@@ -46,35 +63,29 @@ func NewFileStore(baseDir string) *FileStore {
 	}
 }
 
-// List returns all available templates
-func (s *FileStore) List() ([]Template, error) {
+// Load reads every template document in the directory. Files that cannot
+// be read or parsed are reported as problems rather than errors.
+func (s *FileStore) Load() ([]*Source, []Problem, error) {
 	// Check if base directory exists
 	if _, err := os.Stat(s.BaseDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("templates directory not found at %s", s.BaseDir)
+		return nil, nil, fmt.Errorf("templates directory not found at %s: %w", s.BaseDir, os.ErrNotExist)
 	}
 
-	var templates []Template
+	var sources []*Source
+	var problems []Problem
 
 	// Check for templates.yaml (multi-template file)
 	templatesFile := filepath.Join(s.BaseDir, "templates.yaml")
 	if _, err := os.Stat(templatesFile); err == nil {
-		data, err := os.ReadFile(templatesFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read templates.yaml: %w", err)
-		}
-
-		var tf TemplatesFile
-		if err := yaml.Unmarshal(data, &tf); err != nil {
-			return nil, fmt.Errorf("failed to parse templates.yaml: %w", err)
-		}
-
-		templates = append(templates, tf.Templates...)
+		multi, fileProblems := LoadFile(templatesFile)
+		sources = append(sources, multi...)
+		problems = append(problems, fileProblems...)
 	}
 
 	// Also scan for individual template files (*.yaml, excluding templates.yaml)
 	entries, err := os.ReadDir(s.BaseDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to read templates directory: %w", err)
 	}
 
 	for _, entry := range entries {
@@ -82,92 +93,49 @@ func (s *FileStore) List() ([]Template, error) {
 			continue
 		}
 
-		path := filepath.Join(s.BaseDir, entry.Name())
-		data, err := os.ReadFile(path)
-		if err != nil {
-			continue // Skip files we can't read
-		}
+		single, fileProblems := LoadFile(filepath.Join(s.BaseDir, entry.Name()))
+		sources = append(sources, single...)
+		problems = append(problems, fileProblems...)
+	}
 
-		var tmpl Template
-		if err := yaml.Unmarshal(data, &tmpl); err != nil {
-			continue // Skip malformed files
-		}
+	return sources, problems, nil
+}
 
-		// If name is not set, derive from filename
-		if tmpl.Name == "" {
-			tmpl.Name = strings.TrimSuffix(entry.Name(), ".yaml")
-		}
+func (s *FileStore) String() string {
+	return s.BaseDir
+}
 
-		templates = append(templates, tmpl)
+// LayerName returns the name of the search path layer the store provides
+func (s *FileStore) LayerName() string {
+	if s.Layer != "" {
+		return s.Layer
 	}
+	return s.BaseDir
+}
 
-	if len(templates) == 0 {
-		return nil, fmt.Errorf("no templates found in %s", s.BaseDir)
-	}
+// Sources returns the template documents in the directory, before
+// inheritance is resolved
+func (s *FileStore) Sources() ([]*Source, error) {
+	return NewCatalog(s).Sources()
+}
 
-	return templates, nil
+// List returns all available templates
+func (s *FileStore) List() ([]Template, error) {
+	return NewCatalog(s).List()
 }
 
 // Get returns a specific template by name
 func (s *FileStore) Get(name string) (*Template, error) {
-	templates, err := s.List()
-	if err != nil {
-		return nil, err
-	}
-
-	for _, tmpl := range templates {
-		if tmpl.Name == name {
-			return &tmpl, nil
-		}
-	}
-
-	return nil, fmt.Errorf("template %q not found; run 'ghostctl templates' to see available templates", name)
+	return NewCatalog(s).Get(name)
 }
 
-// GetTemplatesDir returns the default templates directory
-func GetTemplatesDir() string {
-	// Check if running from source (templates/ directory exists relative to binary)
-	execPath, err := os.Executable()
-	if err == nil {
-		execDir := filepath.Dir(execPath)
-		
-		// Try relative to executable (for development)
-		templatesDir := filepath.Join(execDir, "..", "templates")
-		if _, err := os.Stat(templatesDir); err == nil {
-			if abs, err := filepath.Abs(templatesDir); err == nil {
-				return abs
-			}
-		}
-		
-		// Try same directory as executable
-		templatesDir = filepath.Join(execDir, "templates")
-		if _, err := os.Stat(templatesDir); err == nil {
-			return templatesDir
-		}
-	}
-
-	// Try current working directory (for development)
-	if cwd, err := os.Getwd(); err == nil {
-		templatesDir := filepath.Join(cwd, "templates")
-		if _, err := os.Stat(templatesDir); err == nil {
-			return templatesDir
-		}
-	}
-
-	// Default fallback locations
-	// For installed binaries, could be /usr/local/share/ghostctl/templates
-	possibleDirs := []string{
-		"/usr/local/share/ghostctl/templates",
-		"/opt/homebrew/share/ghostctl/templates",
-		filepath.Join(os.Getenv("HOME"), ".ghost", "templates"),
-	}
-
-	for _, dir := range possibleDirs {
-		if _, err := os.Stat(dir); err == nil {
-			return dir
-		}
-	}
+// Resolve returns a template with its inheritance chain applied, and where
+// each value came from
+func (s *FileStore) Resolve(name string) (*Resolution, error) {
+	return NewCatalog(s).Resolve(name)
+}
 
-	// Return default path even if it doesn't exist (will error appropriately later)
-	return filepath.Join(os.Getenv("HOME"), ".ghost", "templates")
+// Render resolves and renders a template; see Catalog.Render
+func (s *FileStore) Render(name string, set map[string]string) (*Template, error) {
+	return NewCatalog(s).Render(name, set)
 }
@@ -3,6 +3,8 @@ package templates
 import (
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -94,3 +96,582 @@ func TestFileStoreNonExistentDir(t *testing.T) {
 		t.Error("Expected error for non-existent directory")
 	}
 }
+
+func TestResolveExtends(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"base.yaml": `name: base
+description: Base template
+labels:
+  tier: standard
+  team: platform
+cpu: "2"
+memory: 4Gi
+ttl: 1h
+`,
+		"ml.yaml": `name: ml
+extends: base
+labels:
+  tier: premium
+gpu: 1
+`,
+		"templates.yaml": `templates:
+  - name: ml-large
+    extends: ml
+    memory: 32Gi
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := NewFileStore(tmpDir)
+	res, err := store.Resolve("ml-large")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	tmpl := res.Template
+	if tmpl.Name != "ml-large" || tmpl.Extends != "ml" {
+		t.Errorf("Name/Extends = %q/%q, want ml-large/ml", tmpl.Name, tmpl.Extends)
+	}
+	if tmpl.CPU != "2" || tmpl.Memory != "32Gi" || tmpl.GPU != 1 || tmpl.TTL != "1h" {
+		t.Errorf("resolved resources = %+v", tmpl)
+	}
+	// Labels are deep-merged across the chain
+	if tmpl.Labels["tier"] != "premium" || tmpl.Labels["team"] != "platform" {
+		t.Errorf("Labels = %v, want tier from ml and team from base", tmpl.Labels)
+	}
+	if want := []string{"ml-large", "ml", "base"}; strings.Join(res.Chain, ",") != strings.Join(want, ",") {
+		t.Errorf("Chain = %v, want %v", res.Chain, want)
+	}
+
+	origins := map[string]string{
+		"memory":      "templates.yaml",
+		"gpu":         "ml.yaml",
+		"labels.tier": "ml.yaml",
+		"labels.team": "base.yaml",
+		"cpu":         "base.yaml",
+	}
+	for path, file := range origins {
+		if got := filepath.Base(res.Origins[path].File); got != file {
+			t.Errorf("origin of %s = %s, want %s", path, got, file)
+		}
+	}
+	if _, ok := res.Origins["extends"]; ok {
+		t.Error("extends should not appear in the resolved template")
+	}
+
+	// List and Get return resolved templates too
+	ml, err := store.Get("ml")
+	if err != nil || ml.Memory != "4Gi" || ml.Labels["team"] != "platform" {
+		t.Errorf("Get(ml) = %+v, %v; want inherited values", ml, err)
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"a.yaml": "name: a\nextends: b\n",
+				"b.yaml": "name: b\nextends: c\n",
+				"c.yaml": "name: c\nextends: a\n",
+			},
+			want: "cycle: a -> b -> c -> a",
+		},
+		{
+			name:  "self",
+			files: map[string]string{"a.yaml": "name: a\nextends: a\n"},
+			want:  "cycle: a -> a",
+		},
+		{
+			name:  "unknown parent",
+			files: map[string]string{"a.yaml": "name: a\nextends: missing\n"},
+			want:  `extends unknown template "missing"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := NewFileStore(tmpDir).Get("a")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Get() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderParameters(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	base := `name: base
+labels:
+  tier: standard
+cpu: "1"
+`
+	ml := `name: ml
+extends: base
+parameters:
+  - name: dataset
+    required: true
+    enum: [imagenet, coco]
+  - name: gpus
+    type: int
+    default: "1"
+  - name: tag
+    default: latest
+    regex: '[a-z0-9.]+'
+labels:
+  dataset: "{{ .dataset }}"
+  image-tag: "{{ .tag }}"
+gpu: "{{ .gpus }}"
+`
+	for name, content := range map[string]string{"base.yaml": base, "ml.yaml": ml} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := NewFileStore(tmpDir).Resolve("ml")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	// Without --set, fields needing a required parameter are left out
+	if res.Template.GPU != 1 || res.Template.Labels["dataset"] != "" || len(res.Template.Parameters) != 3 {
+		t.Errorf("preview = %+v, want defaults rendered and dataset unset", res.Template)
+	}
+
+	tmpl, err := res.Render(map[string]string{"dataset": "imagenet", "gpus": "4"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if tmpl.GPU != 4 || tmpl.CPU != "1" {
+		t.Errorf("GPU/CPU = %d/%s, want 4/1", tmpl.GPU, tmpl.CPU)
+	}
+	want := map[string]string{"tier": "standard", "dataset": "imagenet", "image-tag": "latest"}
+	for k, v := range want {
+		if tmpl.Labels[k] != v {
+			t.Errorf("Labels[%s] = %q, want %q", k, tmpl.Labels[k], v)
+		}
+	}
+
+	errTests := []struct {
+		set  map[string]string
+		want string
+	}{
+		{map[string]string{}, `parameter "dataset" is required`},
+		{map[string]string{"dataset": "mnist"}, "not one of imagenet, coco"},
+		{map[string]string{"dataset": "coco", "gpus": "two"}, "not an integer"},
+		{map[string]string{"dataset": "coco", "tag": "Bad_Tag"}, "does not match"},
+		{map[string]string{"dataset": "coco", "typo": "x"}, `unknown parameter "typo"`},
+	}
+	for _, tt := range errTests {
+		if _, err := res.Render(tt.set); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Render(%v) error = %v, want %q", tt.set, err, tt.want)
+		}
+	}
+}
+
+func TestRenderKeepsStrings(t *testing.T) {
+	tmpDir := t.TempDir()
+	pr := `name: pr
+parameters:
+  - name: image
+    required: true
+  - name: big
+    type: bool
+labels:
+  image-tag: "{{ .image }}"
+  build: "v{{ .image }}"
+gpu: '{{ if .big }}2{{ else }}1{{ end }}'
+vclusterValues:
+  image:
+    tag: "{{ .image }}"
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "pr.yaml"), []byte(pr), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := NewFileStore(tmpDir).Resolve("pr")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+
+	// Version-like values are not re-read as numbers
+	for _, image := range []string{"1.20", "1e3", "true", "007"} {
+		tmpl, err := res.Render(map[string]string{"image": image, "big": "true"})
+		if err != nil {
+			t.Fatalf("Render(%s) failed: %v", image, err)
+		}
+		if tmpl.Labels["image-tag"] != image || tmpl.Labels["build"] != "v"+image {
+			t.Errorf("Labels = %v, want image-tag %s", tmpl.Labels, image)
+		}
+		if tag := tmpl.VClusterValues["image"].(map[string]interface{})["tag"]; tag != image {
+			t.Errorf("image.tag = %#v, want %q", tag, image)
+		}
+		if tmpl.GPU != 2 {
+			t.Errorf("GPU = %d, want 2", tmpl.GPU)
+		}
+	}
+}
+
+func TestParseSetFlags(t *testing.T) {
+	set, err := ParseSetFlags([]string{"pr=42", "image=repo:tag=x"})
+	if err != nil {
+		t.Fatalf("ParseSetFlags failed: %v", err)
+	}
+	if set["pr"] != "42" || set["image"] != "repo:tag=x" {
+		t.Errorf("ParseSetFlags = %v", set)
+	}
+	if _, err := ParseSetFlags([]string{"novalue"}); err == nil {
+		t.Error("ParseSetFlags should reject values without =")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  []string // expected problems, as "file:line: substring"
+	}{
+		{
+			name: "valid",
+			files: map[string]string{
+				"base.yaml": "name: base\ncpu: 500m\nmemory: 1.5Gi\nstorage: 10Gi\ngpu: 1\ngpuType: nvidia-t4\nttl: 1h30m\n",
+				"pr.yaml":   "name: pr\nextends: base\nparameters:\n  - name: pr\n    type: int\n    required: true\nlabels:\n  pr: \"{{ .pr }}\"\n",
+			},
+		},
+		{
+			name: "units and unknown fields",
+			files: map[string]string{
+				"bad.yaml": "name: bad\ncpu: two\nmemory: 4GB\nstorage: lots\ngpu: -1\ngpuType: nvidia-h900\nttl: 1 hour\ncolour: red\n",
+			},
+			want: []string{
+				"bad.yaml:2: invalid CPU value",
+				"bad.yaml:3: invalid memory format",
+				"bad.yaml:4: invalid memory format",
+				"bad.yaml:5: invalid GPU count",
+				"bad.yaml:6: invalid GPU type",
+				"bad.yaml:7: invalid duration format",
+				`bad.yaml:8: unknown field "colour"`,
+			},
+		},
+		{
+			name: "security",
+			files: map[string]string{
+				"ok.yaml":     "name: ok\nsecurity:\n  podSecurity: restricted\n  networkIsolation: true\n  egress:\n    defaultDeny: true\n    allow: [10.0.0.0/8, 0.0.0.0/0:443]\n",
+				"bad.yaml":    "name: bad\nsecurity:\n  podSecurity: strict\n  firewall: true\n",
+				"egress.yaml": "name: egress\nsecurity:\n  egress:\n    defaultDeny: true\n    allow: [example.com]\n    ports: [443]\n",
+			},
+			want: []string{
+				`bad.yaml:3: invalid podSecurity "strict"`,
+				`bad.yaml:4: unknown security field "firewall"`,
+				`egress.yaml:3: invalid egress rule "example.com"`,
+				`egress.yaml:6: unknown egress field "ports"`,
+			},
+		},
+		{
+			name: "scheduling",
+			files: map[string]string{
+				"ok.yaml":  "name: ok\narchitecture: arm64\nnodeSelector:\n  pool: ml\ntolerations:\n  - key: gpu\n    operator: Exists\n    effect: NoSchedule\naffinity:\n  nodeAffinity: {}\npriorityClassName: batch\n",
+				"bad.yaml": "name: bad\narchitecture: x86\nnodeSelector:\n  pool: ml\ntolerations:\n  - key: gpu\n    effect: Evict\n  - key: spot\n    seconds: 30\naffinity:\n  zone: a\n",
+			},
+			want: []string{
+				`bad.yaml:2: architecture: invalid architecture "x86"`,
+				`bad.yaml:6: toleration "gpu": invalid effect "Evict"`,
+				`bad.yaml:9: unknown toleration field "seconds"`,
+				`bad.yaml:11: invalid affinity "zone"`,
+			},
+		},
+		{
+			name: "duplicate names",
+			files: map[string]string{
+				"templates.yaml": "templates:\n  - name: gpu\n    cpu: \"1\"\n",
+				"gpu.yaml":       "name: gpu\ncpu: \"2\"\n",
+			},
+			want: []string{`gpu.yaml:1: duplicate template name "gpu"`},
+		},
+		{
+			name: "parameters",
+			files: map[string]string{
+				"p.yaml": "name: p\nparameters:\n  - name: n\n    type: float\n  - name: size\n    default: huge\n    enum: [small]\n    optional: true\nlabels:\n  x: \"{{ .missing }}\"\n  y: \"{{ .size \"\n",
+			},
+			want: []string{
+				`p.yaml:3: unknown type "float"`,
+				`p.yaml:5: "huge" is not one of small`,
+				`p.yaml:8: unknown parameter field "optional"`,
+				`p.yaml:10: labels.x: undeclared parameter "missing"`,
+				"p.yaml:11: labels.y: template:",
+			},
+		},
+		{
+			name: "broken inheritance and syntax",
+			files: map[string]string{
+				"a.yaml":      "name: a\nextends: b\n",
+				"b.yaml":      "name: b\nextends: a\n",
+				"c.yaml":      "name: c\n\nextends: nope\n",
+				"broken.yaml": "name: broken\ncpu: \"1\n",
+			},
+			want: []string{
+				"a.yaml:2: template inheritance cycle",
+				"b.yaml:2: template inheritance cycle",
+				"broken.yaml:",
+				`c.yaml:3: extends unknown template "nope"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sources, problems, err := NewFileStore(tmpDir).Load()
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			problems = append(problems, Validate(sources)...)
+
+			if len(problems) != len(tt.want) {
+				t.Fatalf("got %d problems, want %d: %v", len(problems), len(tt.want), problems)
+			}
+			for _, want := range tt.want {
+				found := false
+				for _, p := range problems {
+					got := strings.TrimPrefix(p.String(), tmpDir+string(filepath.Separator))
+					file, msg, _ := strings.Cut(want, ": ")
+					if strings.HasPrefix(got, file) && strings.Contains(got, msg) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("missing problem %q in %v", want, problems)
+				}
+			}
+		})
+	}
+}
+
+func TestFileStoreRefusesInvalidTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"base.yaml":   "name: base\nmemory: 4GB\n",
+		"child.yaml":  "name: child\nextends: base\n",
+		"ok.yaml":     "name: ok\ncpu: \"1\"\nparameters:\n  - name: cpu\n",
+		"broken.yaml": "name: broken\ncpu: [1\n",
+		"orphan.yaml": "name: orphan\nextends: nowhere\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var warnings []string
+	store := NewCatalog(NewFileStore(tmpDir))
+	store.Warn = func(p Problem) { warnings = append(warnings, p.String()) }
+
+	// Broken files and templates that cannot be resolved are reported as
+	// warnings, and the others are still listed
+	list, err := store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var names []string
+	for _, tmpl := range list {
+		names = append(names, tmpl.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "base,child,ok" {
+		t.Errorf("List() = %v, want base, child and ok", names)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "broken.yaml") || !strings.Contains(warnings[1], "orphan.yaml") {
+		t.Errorf("warnings = %v, want broken.yaml and orphan.yaml", warnings)
+	}
+	if tmpl, err := store.Get("ok"); err != nil || tmpl.CPU != "1" {
+		t.Errorf("Get(ok) = %v, %v", tmpl, err)
+	}
+	if _, err := store.Resolve("broken"); err == nil || !strings.Contains(err.Error(), "could not be loaded") {
+		t.Errorf("Resolve(broken) error = %v, want the unloaded files mentioned", err)
+	}
+
+	// Invalid templates, and templates extending them, are refused
+	if _, err := store.Render("child", nil); err == nil || !strings.Contains(err.Error(), "invalid memory") {
+		t.Errorf("Render(child) error = %v, want the parent's problem", err)
+	}
+	if _, err := store.Render("missing", nil); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Render(missing) error = %v, want not found", err)
+	}
+
+	// Unrelated broken files do not block valid templates
+	if _, err := store.Render("ok", nil); err != nil {
+		t.Errorf("Render(ok) error = %v", err)
+	}
+}
+
+func TestSearchPath(t *testing.T) {
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	for _, dir := range []string{".git", ".ghostctl/templates", "src/app"} {
+		if err := os.MkdirAll(filepath.Join(repo, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repoTemplates := filepath.Join(repo, ".ghostctl", "templates")
+
+	if got := FindRepoTemplates(filepath.Join(repo, "src", "app")); got != repoTemplates {
+		t.Errorf("FindRepoTemplates() = %q, want %q", got, repoTemplates)
+	}
+	// The search stops at the repository root
+	nested := filepath.Join(repo, "vendor", "lib")
+	if err := os.MkdirAll(filepath.Join(nested, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := FindRepoTemplates(nested); got != "" {
+		t.Errorf("FindRepoTemplates() in a nested repository = %q, want none", got)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join(repo, "src", "app")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	a, b := filepath.Join(root, "a"), filepath.Join(root, "b")
+	layerDirs := func(layers []Layer) []string {
+		var dirs []string
+		for _, l := range layers {
+			dirs = append(dirs, l.Dir)
+		}
+		return dirs
+	}
+
+	// The configured path follows the repository's templates; duplicates are dropped
+	got := layerDirs(SearchPath([]string{a, b, a}))
+	if want := []string{repoTemplates, a, b}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("SearchPath(configured) = %v, want %v", got, want)
+	}
+
+	// The environment overrides the configured path
+	t.Setenv(TemplatePathEnv, b+string(os.PathListSeparator)+a)
+	got = layerDirs(SearchPath([]string{a}))
+	if want := []string{repoTemplates, b, a}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("SearchPath() with %s = %v, want %v", TemplatePathEnv, got, want)
+	}
+
+	// Without either, the default path starts with the user's templates
+	t.Setenv(TemplatePathEnv, "")
+	t.Setenv("HOME", root)
+	layers := SearchPath(nil)
+	if len(layers) < 2 || layers[0].Name != LayerRepo || layers[1].Name != LayerUser {
+		t.Errorf("SearchPath(nil) = %v, want repo then user first", layers)
+	}
+
+	// A templates/ directory in the working directory, such as a Helm
+	// chart's, is not searched
+	chart := filepath.Join(repo, "src", "app", "templates")
+	if err := os.MkdirAll(chart, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range layerDirs(SearchPath(nil)) {
+		if dir == chart {
+			t.Errorf("SearchPath(nil) includes the working directory's %s", chart)
+		}
+	}
+}
+
+func TestResolveVClusterValues(t *testing.T) {
+	shared, local := t.TempDir(), t.TempDir()
+	for _, dir := range []string{shared, local} {
+		if err := os.MkdirAll(filepath.Join(dir, "values"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "values", "extra.yaml"), []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(shared, "base.yaml"): `name: base
+valuesFiles: [values/extra.yaml]
+vclusterValues:
+  sync:
+    toHost:
+      ingresses:
+        enabled: true
+  controlPlane:
+    coredns:
+      enabled: true
+`,
+		filepath.Join(local, "pr.yaml"): `name: pr
+extends: base
+parameters:
+  - name: replicas
+    type: int
+    default: "1"
+valuesFiles: [values/extra.yaml]
+vclusterValues:
+  controlPlane:
+    coredns:
+      deployment:
+        replicas: "{{ .replicas }}"
+`,
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	catalog := NewCatalog(NewFileStore(local), NewFileStore(shared))
+	tmpl, err := catalog.Render("pr", map[string]string{"replicas": "2"})
+	if err != nil {
+		t.Fatalf("Render(pr) error = %v", err)
+	}
+
+	// Values files are relative to the template that names them, parents first
+	wantFiles := []string{filepath.Join(shared, "values", "extra.yaml"), filepath.Join(local, "values", "extra.yaml")}
+	if strings.Join(tmpl.ValuesFiles, ",") != strings.Join(wantFiles, ",") {
+		t.Errorf("ValuesFiles = %v, want %v", tmpl.ValuesFiles, wantFiles)
+	}
+
+	coredns := tmpl.VClusterValues["controlPlane"].(map[string]interface{})["coredns"].(map[string]interface{})
+	if coredns["enabled"] != true || coredns["deployment"].(map[string]interface{})["replicas"] != 2 {
+		t.Errorf("coredns values = %v, want the parent's enabled and the rendered replicas", coredns)
+	}
+	if _, ok := tmpl.VClusterValues["sync"]; !ok {
+		t.Error("inherited sync values are missing")
+	}
+
+	// A values file that does not exist is reported at its line
+	broken := filepath.Join(local, "broken.yaml")
+	if err := os.WriteFile(broken, []byte("name: broken\nvaluesFiles:\n  - values/missing.yaml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sources, _, _ := catalog.Load()
+	problems := Validate(sources)
+	if len(problems) != 1 || problems[0].Line != 3 || !strings.Contains(problems[0].Message, "missing.yaml") {
+		t.Errorf("Validate() = %v, want the missing values file at line 3", problems)
+	}
+}
@@ -0,0 +1,427 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is a validation error at a position in a template file
+type Problem struct {
+	File     string
+	Line     int
+	Template string // empty if the file could not be parsed
+	Message  string
+}
+
+func (p Problem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", p.File, p.Message)
+}
+
+// parameterFields are the fields a parameter declaration may set
+var parameterFields = map[string]bool{
+	"name": true, "description": true, "type": true, "default": true,
+	"required": true, "enum": true, "regex": true,
+}
+
+// Validate checks template documents for unknown fields, invalid resource
+// units, GPU types and TTLs, malformed parameters, duplicate names and
+// broken inheritance
+func Validate(sources []*Source) []Problem {
+	var problems []Problem
+
+	first := make(map[string]*Source, len(sources))
+	for _, src := range sources {
+		if prev, ok := first[src.Name]; ok {
+			problems = append(problems, Problem{
+				File: src.File, Line: src.Line, Template: src.Name,
+				Message: fmt.Sprintf("duplicate template name %q (also defined at %s:%d)", src.Name, prev.File, prev.Line),
+			})
+			continue
+		}
+		first[src.Name] = src
+	}
+
+	for _, src := range sources {
+		v := &validator{src: src}
+		v.checkFields()
+
+		if first[src.Name] == src {
+			res, err := Resolve(sources, src.Name)
+			if err != nil {
+				line := src.Line
+				if node := mappingValue(src.Node, "extends"); node != nil {
+					line = node.Line
+				}
+				v.add(line, "%v", err)
+			} else {
+				v.checkExpressions(res)
+			}
+		}
+		problems = append(problems, v.problems...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		return problems[i].Line < problems[j].Line
+	})
+	return problems
+}
+
+// Validate checks the fields of a rendered template
+func (t *Template) Validate() error {
+	checks := []struct{ field, value string }{
+		{"cpu", t.CPU},
+		{"memory", t.Memory},
+		{"storage", t.Storage},
+		{"gpuType", t.GPUType},
+		{"ttl", t.TTL},
+		{"architecture", t.Architecture},
+	}
+	for _, c := range checks {
+		if c.value == "" {
+			continue
+		}
+		if err := CheckField(c.field, c.value); err != nil {
+			return fmt.Errorf("template %q: %s: %w", t.Name, c.field, err)
+		}
+	}
+	if t.GPU < 0 {
+		return fmt.Errorf("template %q: gpu must not be negative", t.Name)
+	}
+	if err := t.Security.Validate(); err != nil {
+		return fmt.Errorf("template %q: security: %w", t.Name, err)
+	}
+	if err := t.Scheduling.Validate(); err != nil {
+		return fmt.Errorf("template %q: %w", t.Name, err)
+	}
+	return nil
+}
+
+// CheckField validates the value of a scalar template field such as cpu,
+// memory or ttl
+func CheckField(field, value string) error {
+	var err error
+	switch field {
+	case "cpu":
+		_, err = utils.ParseCPU(value)
+	case "memory", "storage":
+		_, err = utils.ParseMemory(value)
+	case "gpuType":
+		err = utils.ValidateGPUType(value)
+	case "ttl":
+		_, err = utils.ParseDuration(value)
+	case "architecture":
+		err = cluster.Scheduling{Architecture: value}.Validate()
+	case "gpu":
+		if n, convErr := strconv.Atoi(value); convErr != nil || n < 0 {
+			err = fmt.Errorf("invalid GPU count: %s", value)
+		}
+	}
+	return err
+}
+
+// missingKeyPattern matches text/template's error for an unknown parameter
+var missingKeyPattern = regexp.MustCompile(`map has no entry for key "([^"]*)"`)
+
+type validator struct {
+	src      *Source
+	problems []Problem
+}
+
+func (v *validator) add(line int, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		File: v.src.File, Line: line, Template: v.src.Name,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkFields checks each field as written in the file
+func (v *validator) checkFields() {
+	node := v.src.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		switch key.Value {
+		case "labels", "nodeSelector":
+			v.checkLabels(key.Value, value)
+		case "tolerations":
+			v.checkTolerations(value)
+		case "affinity":
+			v.checkAffinity(value)
+		case "parameters":
+			v.checkParameters(value)
+		case "vclusterValues":
+			if value.Kind != yaml.MappingNode {
+				v.add(value.Line, "vclusterValues must be a mapping of vCluster chart values")
+			}
+		case "valuesFiles":
+			v.checkValuesFiles(value)
+		case "security":
+			v.checkSecurity(value)
+		case "name", "description", "extends", "cpu", "memory", "storage", "gpu", "gpuType", "ttl",
+			"priorityClassName", "architecture":
+			if value.Kind != yaml.ScalarNode {
+				v.add(value.Line, "%s must be a single value", key.Value)
+				continue
+			}
+			if strings.Contains(value.Value, "{{") {
+				continue // checked once rendered
+			}
+			if err := CheckField(key.Value, value.Value); err != nil {
+				v.add(value.Line, "%s: %v", key.Value, err)
+			}
+		default:
+			v.add(key.Line, "unknown field %q", key.Value)
+		}
+	}
+}
+
+// checkLabels checks a mapping of labels, such as labels or nodeSelector
+func (v *validator) checkLabels(field string, node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		v.add(node.Line, "%s must be a mapping of key: value", field)
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i+1].Kind != yaml.ScalarNode {
+			v.add(node.Content[i+1].Line, "label %q must be a single value", node.Content[i].Value)
+		}
+	}
+}
+
+// tolerationFields are the fields of a toleration
+var tolerationFields = map[string]bool{"key": true, "operator": true, "value": true, "effect": true}
+
+func (v *validator) checkTolerations(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		v.add(node.Line, "tolerations must be a list")
+		return
+	}
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			v.add(item.Line, "toleration must be a mapping")
+			continue
+		}
+		known := true
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			if key := item.Content[i]; !tolerationFields[key.Value] {
+				v.add(key.Line, "unknown toleration field %q", key.Value)
+				known = false
+			}
+		}
+		var t cluster.Toleration
+		if err := item.Decode(&t); err != nil {
+			v.add(item.Line, "toleration: %v", strings.TrimPrefix(err.Error(), "yaml: "))
+			continue
+		}
+		if known && !strings.Contains(t.Key+t.Operator+t.Value+t.Effect, "{{") {
+			if err := t.Validate(); err != nil {
+				v.add(item.Line, "%v", err)
+			}
+		}
+	}
+}
+
+func (v *validator) checkAffinity(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		v.add(node.Line, "affinity must be a mapping")
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if err := (cluster.Scheduling{Affinity: map[string]interface{}{key.Value: nil}}).Validate(); err != nil {
+			v.add(key.Line, "%v", err)
+		}
+	}
+}
+
+// securityFields are the fields of a security section, and egressFields
+// those of its egress section
+var (
+	securityFields = map[string]bool{"podSecurity": true, "networkIsolation": true, "egress": true, "isolated": true}
+	egressFields   = map[string]bool{"defaultDeny": true, "allow": true}
+)
+
+func (v *validator) checkSecurity(node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		v.add(node.Line, "security must be a mapping")
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !securityFields[key.Value] {
+			v.add(key.Line, "unknown security field %q", key.Value)
+			continue
+		}
+		if key.Value != "egress" || value.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(value.Content); j += 2 {
+			if k := value.Content[j]; !egressFields[k.Value] {
+				v.add(k.Line, "unknown egress field %q", k.Value)
+			}
+		}
+	}
+
+	var sec cluster.Security
+	if err := node.Decode(&sec); err != nil {
+		v.add(node.Line, "security: %v", strings.TrimPrefix(err.Error(), "yaml: "))
+		return
+	}
+	if err := sec.Validate(); err != nil {
+		v.add(node.Line, "security: %v", err)
+	}
+}
+
+func (v *validator) checkValuesFiles(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		v.add(node.Line, "valuesFiles must be a list of file paths")
+		return
+	}
+	for _, item := range node.Content {
+		if item.Kind != yaml.ScalarNode || item.Value == "" {
+			v.add(item.Line, "values file must be a file path")
+			continue
+		}
+		if strings.Contains(item.Value, "{{") {
+			continue // checked once rendered
+		}
+		if _, err := os.Stat(ResolveValuesFile(filepath.Dir(v.src.File), item.Value)); err != nil {
+			v.add(item.Line, "values file %s: %v", item.Value, errors.Unwrap(err))
+		}
+	}
+}
+
+func (v *validator) checkParameters(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		v.add(node.Line, "parameters must be a list")
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range node.Content {
+		if item.Kind != yaml.MappingNode {
+			v.add(item.Line, "parameter must be a mapping")
+			continue
+		}
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			if key := item.Content[i]; !parameterFields[key.Value] {
+				v.add(key.Line, "unknown parameter field %q", key.Value)
+			}
+		}
+
+		var p Parameter
+		if err := item.Decode(&p); err != nil {
+			v.add(item.Line, "invalid parameter: %v", err)
+			continue
+		}
+		if p.Name == "" {
+			v.add(item.Line, "parameter has no name")
+			continue
+		}
+		if seen[p.Name] {
+			v.add(item.Line, "duplicate parameter %q", p.Name)
+		}
+		seen[p.Name] = true
+
+		switch p.TypeName() {
+		case ParamString, ParamInt, ParamBool:
+		default:
+			v.add(item.Line, "parameter %q: unknown type %q (expected string, int or bool)", p.Name, p.Type)
+			continue
+		}
+		if p.Regex != "" {
+			if _, err := regexp.Compile(p.Regex); err != nil {
+				v.add(item.Line, "parameter %q: invalid regex: %v", p.Name, err)
+				continue
+			}
+		}
+		if p.Default != "" {
+			if _, err := p.parse(p.Default); err != nil {
+				v.add(item.Line, "default: %v", err)
+			}
+		}
+		for _, e := range p.Enum {
+			if _, err := p.parse(e); err != nil {
+				v.add(item.Line, "enum: %v", err)
+			}
+		}
+	}
+}
+
+// checkExpressions checks the template expressions written in this
+// template against the parameters it resolves to
+func (v *validator) checkExpressions(res *Resolution) {
+	params, err := decodeParameters(res.Values)
+	if err != nil {
+		return // reported by checkParameters
+	}
+
+	// Any value of the right type will do
+	data := make(map[string]interface{}, len(params))
+	for _, p := range params {
+		raw := p.Default
+		if raw == "" && len(p.Enum) > 0 {
+			raw = p.Enum[0]
+		}
+		if parsed, err := p.parse(raw); err == nil {
+			data[p.Name] = parsed
+		} else {
+			data[p.Name], _ = p.parse(zeroValue(p.TypeName()))
+		}
+	}
+
+	var walk func(node *yaml.Node, field string)
+	walk = func(node *yaml.Node, field string) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i].Value
+				if field == "" && key == "parameters" {
+					continue
+				}
+				if field != "" {
+					key = field + "." + key
+				}
+				walk(node.Content[i+1], key)
+			}
+		case yaml.SequenceNode:
+			for _, item := range node.Content {
+				walk(item, field)
+			}
+		case yaml.ScalarNode:
+			if !strings.Contains(node.Value, "{{") {
+				return
+			}
+			t, err := template.New("").Option("missingkey=error").Parse(node.Value)
+			if err != nil {
+				v.add(node.Line, "%s: %v", field, err)
+				return
+			}
+			if err := t.Execute(&strings.Builder{}, data); err != nil {
+				if m := missingKeyPattern.FindStringSubmatch(err.Error()); m != nil {
+					v.add(node.Line, "%s: undeclared parameter %q", field, m[1])
+					return
+				}
+				v.add(node.Line, "%s: %v", field, err)
+			}
+		}
+	}
+	walk(v.src.Node, "")
+}
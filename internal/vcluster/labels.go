@@ -0,0 +1,72 @@
+package vcluster
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+)
+
+// hostResourceKinds are the host-side resources the vcluster chart creates
+// for each vCluster
+const hostResourceKinds = "statefulset,service"
+
+// Label applies labels and annotations to the host-side resources of a vCluster
+func Label(name, namespace string, labels, annotations map[string]string) error {
+	if !shell.CommandExists("kubectl") {
+		return fmt.Errorf("kubectl not found in PATH")
+	}
+
+	selector := "app=vcluster,release=" + name
+	for _, verb := range []string{"label", "annotate"} {
+		values := labels
+		if verb == "annotate" {
+			values = annotations
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		args := []string{verb, hostResourceKinds, "-n", namespace, "-l", selector, "--overwrite"}
+		args = append(args, keyValueArgs(values)...)
+
+		result, err := shell.ExecuteCommand("kubectl", args...)
+		if err != nil {
+			return fmt.Errorf("failed to %s vCluster resources: %w", verb, err)
+		}
+		if result.ExitCode != 0 {
+			return fmt.Errorf("failed to %s vCluster resources (exit code %d): %s", verb, result.ExitCode, strings.TrimSpace(result.Stdout))
+		}
+	}
+
+	return nil
+}
+
+// keyValueArgs renders a map as sorted key=value arguments
+func keyValueArgs(values map[string]string) []string {
+	args := make([]string, 0, len(values))
+	for k, v := range values {
+		args = append(args, k+"="+v)
+	}
+	sort.Strings(args)
+	return args
+}
+
+// SanitizeLabelValue converts s into a valid Kubernetes label value
+func SanitizeLabelValue(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	value := b.String()
+	if len(value) > 63 {
+		value = value[:63]
+	}
+	return strings.Trim(value, "-_.")
+}
@@ -0,0 +1,26 @@
+package vcluster
+
+import "testing"
+
+func TestSanitizeLabelValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"alice", "alice"},
+		{"alice@example.com", "alice_example.com"},
+		{"DOMAIN\\bob", "DOMAIN_bob"},
+		{"-leading-and-trailing-", "leading-and-trailing"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := SanitizeLabelValue(tt.in); got != tt.want {
+			t.Errorf("SanitizeLabelValue(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	long := SanitizeLabelValue("a-very-long-identity-that-goes-well-beyond-the-sixty-three-character-limit")
+	if len(long) > 63 {
+		t.Errorf("SanitizeLabelValue() returned %d characters, want at most 63", len(long))
+	}
+}
@@ -0,0 +1,146 @@
+package vcluster
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/config"
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+)
+
+const (
+	// ManagedByLabel marks host namespaces ghostctl created and may delete
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "ghostctl"
+
+	// NamespaceStrategyLabel records the strategy a namespace was created for
+	NamespaceStrategyLabel = "ghostctl.io/namespace-strategy"
+
+	maxNamespaceLength = 63
+)
+
+// NamespaceFor returns the host namespace for a cluster under a namespace
+// strategy. base is the configured namespace, used as is by the shared
+// strategy and as a prefix by the others.
+func NamespaceFor(strategy, base, clusterName, owner string) (string, error) {
+	if base == "" {
+		base = DefaultNamespace
+	}
+	switch strategy {
+	case "", config.NamespaceShared:
+		return base, nil
+	case config.NamespacePerCluster:
+		return namespaceName(base + "-" + clusterName), nil
+	case config.NamespacePerOwner:
+		if owner == "" {
+			return "", fmt.Errorf("namespace strategy %s needs an owner, but the current user is unknown", strategy)
+		}
+		return namespaceName(base + "-" + owner), nil
+	default:
+		return "", fmt.Errorf("invalid namespaceStrategy %q (expected shared, per-cluster or per-owner)", strategy)
+	}
+}
+
+// namespaceName converts s into a valid namespace name: lowercase
+// alphanumerics and '-', at most 63 characters. Names that have to be
+// changed end with a hash of the original so they stay distinct: "a.b"
+// and "a-b" must not share a namespace.
+func namespaceName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	name := strings.Trim(b.String(), "-")
+	if name == s && len(name) <= maxNamespaceLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(s))
+	suffix := hex.EncodeToString(sum[:])[:8]
+	if limit := maxNamespaceLength - len(suffix) - 1; len(name) > limit {
+		name = strings.TrimRight(name[:limit], "-")
+	}
+	if name == "" {
+		return suffix
+	}
+	return name + "-" + suffix
+}
+
+// EnsureNamespace creates a host namespace for ghostctl clusters, or
+// updates the labels of one it created before. A namespace that exists
+// without ghostctl's label belongs to someone else and is refused, since
+// labelling it would let ghostctl delete it.
+func EnsureNamespace(namespace string, labels map[string]string) error {
+	if !shell.CommandExists("kubectl") {
+		return fmt.Errorf("kubectl not found in PATH")
+	}
+
+	existing, err := shell.ExecuteCommandSplit("", "kubectl", "get", "namespace", namespace, "-o", "json")
+	if err != nil {
+		return fmt.Errorf("failed to read namespace %s: %w", namespace, err)
+	}
+	switch {
+	case existing.ExitCode == 0:
+		var ns struct {
+			Metadata struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal([]byte(existing.Stdout), &ns); err != nil {
+			return fmt.Errorf("failed to parse namespace %s: %w", namespace, err)
+		}
+		if ns.Metadata.Labels[ManagedByLabel] != managedByValue {
+			return fmt.Errorf("namespace %s already exists and was not created by ghostctl; set a different namespace or namespaceStrategy", namespace)
+		}
+	case !strings.Contains(existing.Stderr, "NotFound"):
+		return fmt.Errorf("failed to read namespace %s (exit code %d): %s", namespace, existing.ExitCode, strings.TrimSpace(existing.Stderr))
+	}
+
+	all := map[string]string{ManagedByLabel: managedByValue}
+	for k, v := range labels {
+		all[k] = v
+	}
+	manifest, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": namespace, "labels": all},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode namespace: %w", err)
+	}
+
+	result, err := shell.ExecuteCommandWithInput(string(manifest), "kubectl", "apply", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("failed to create namespace %s: %w", namespace, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to create namespace %s (exit code %d): %s", namespace, result.ExitCode, strings.TrimSpace(result.Stdout))
+	}
+	return nil
+}
+
+// DeleteNamespace deletes a host namespace created by EnsureNamespace,
+// without waiting for its contents to be removed. Namespaces ghostctl did
+// not create are left alone.
+func DeleteNamespace(namespace string) error {
+	if !shell.CommandExists("kubectl") {
+		return fmt.Errorf("kubectl not found in PATH")
+	}
+
+	selector := fmt.Sprintf("%s=%s,kubernetes.io/metadata.name=%s", ManagedByLabel, managedByValue, namespace)
+	result, err := shell.ExecuteCommand("kubectl", "delete", "namespace", "-l", selector, "--wait=false")
+	if err != nil {
+		return fmt.Errorf("failed to delete namespace %s: %w", namespace, err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to delete namespace %s (exit code %d): %s", namespace, result.ExitCode, strings.TrimSpace(result.Stdout))
+	}
+	return nil
+}
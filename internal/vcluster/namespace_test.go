@@ -0,0 +1,49 @@
+package vcluster
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNamespaceFor(t *testing.T) {
+	tests := []struct {
+		strategy string
+		base     string
+		owner    string
+		want     string
+		wantErr  bool
+	}{
+		{strategy: "", base: "", want: "ghostcluster"},
+		{strategy: "shared", base: "dev", want: "dev"},
+		{strategy: "per-cluster", base: "ghostcluster", want: "ghostcluster-pr-42"},
+		{strategy: "per-owner", base: "ghostcluster", owner: "Alice@Example.com", want: "ghostcluster-alice-example-com-7c158b58"},
+		{strategy: "per-owner", base: "ghostcluster", wantErr: true},
+		{strategy: "per-team", base: "ghostcluster", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NamespaceFor(tt.strategy, tt.base, "pr-42", tt.owner)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NamespaceFor(%q, %q) error = %v, wantErr %v", tt.strategy, tt.owner, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NamespaceFor(%q, %q) = %q, want %q", tt.strategy, tt.owner, got, tt.want)
+		}
+	}
+
+	long, err := NamespaceFor("per-owner", "ghostcluster", "", "a-very-long-identity-that-goes-well-beyond-the-namespace-limit@example.com")
+	if err != nil {
+		t.Fatalf("NamespaceFor() error = %v", err)
+	}
+	other, _ := NamespaceFor("per-owner", "ghostcluster", "", "a-very-long-identity-that-goes-well-beyond-the-namespace-limit@example.org")
+	if len(long) > 63 || long == other {
+		t.Errorf("NamespaceFor() = %q and %q, want distinct names of at most 63 characters", long, other)
+	}
+
+	// Names that sanitise to the same string stay distinct
+	dotted, _ := NamespaceFor("per-cluster", "ghostcluster", "a.b", "")
+	dashed, _ := NamespaceFor("per-cluster", "ghostcluster", "a-b", "")
+	if dashed != "ghostcluster-a-b" || dotted == dashed || !strings.HasPrefix(dotted, "ghostcluster-a-b-") {
+		t.Errorf("NamespaceFor(a.b) = %q and NamespaceFor(a-b) = %q, want distinct names", dotted, dashed)
+	}
+}
@@ -0,0 +1,167 @@
+package vcluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+)
+
+const (
+	// ClusterLabel marks host-side resources ghostctl creates for a vCluster
+	ClusterLabel = "ghostctl.io/cluster"
+
+	// managedByLabel is set by vCluster on the host pods it syncs
+	managedByLabel = "vcluster.loft.sh/managed-by"
+
+	// apiServerPort is the vCluster API server port, reachable from outside
+	// the vCluster even when it is isolated
+	apiServerPort = 8443
+)
+
+// NetworkPolicies returns the host NetworkPolicies implementing the
+// network isolation and egress settings of sec. Workload pods accept
+// traffic only from the vCluster's own pods; the control plane also
+// accepts connections to its API server. With egress denied, workloads
+// may only reach the vCluster itself, cluster DNS and the allowlist.
+func NetworkPolicies(name, namespace string, sec *cluster.Security) ([]map[string]interface{}, error) {
+	if !sec.HostPolicies() {
+		return nil, nil
+	}
+
+	workloads := map[string]interface{}{"matchLabels": map[string]string{managedByLabel: name}}
+	controlPlane := map[string]interface{}{"matchLabels": map[string]string{"app": "vcluster", "release": name}}
+	own := []interface{}{
+		map[string]interface{}{"podSelector": workloads},
+		map[string]interface{}{"podSelector": controlPlane},
+	}
+
+	workloadSpec := map[string]interface{}{"podSelector": workloads}
+	var policyTypes []string
+	if sec.NetworkIsolation {
+		policyTypes = append(policyTypes, "Ingress")
+		workloadSpec["ingress"] = []interface{}{map[string]interface{}{"from": own}}
+	}
+	if sec.DenyEgress() {
+		policyTypes = append(policyTypes, "Egress")
+		egress := []interface{}{
+			map[string]interface{}{"to": own},
+			map[string]interface{}{
+				"to": []interface{}{map[string]interface{}{
+					"namespaceSelector": map[string]interface{}{"matchLabels": map[string]string{"kubernetes.io/metadata.name": "kube-system"}},
+				}},
+				"ports": []interface{}{
+					map[string]interface{}{"protocol": "UDP", "port": 53},
+					map[string]interface{}{"protocol": "TCP", "port": 53},
+				},
+			},
+		}
+		for _, allow := range sec.Egress.Allow {
+			rule, err := cluster.ParseEgressRule(allow)
+			if err != nil {
+				return nil, err
+			}
+			entry := map[string]interface{}{
+				"to": []interface{}{map[string]interface{}{"ipBlock": map[string]string{"cidr": rule.CIDR}}},
+			}
+			if rule.Port != 0 {
+				entry["ports"] = []interface{}{map[string]interface{}{"protocol": "TCP", "port": rule.Port}}
+			}
+			egress = append(egress, entry)
+		}
+		workloadSpec["egress"] = egress
+	}
+	workloadSpec["policyTypes"] = policyTypes
+
+	policies := []map[string]interface{}{networkPolicy(name+"-workloads", name, namespace, workloadSpec)}
+	if sec.NetworkIsolation {
+		policies = append(policies, networkPolicy(name+"-control-plane", name, namespace, map[string]interface{}{
+			"podSelector": controlPlane,
+			"policyTypes": []string{"Ingress"},
+			"ingress": []interface{}{
+				map[string]interface{}{"from": own},
+				map[string]interface{}{"ports": []interface{}{map[string]interface{}{"protocol": "TCP", "port": apiServerPort}}},
+			},
+		}))
+	}
+	return policies, nil
+}
+
+func networkPolicy(policyName, clusterName, namespace string, spec map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind":       "NetworkPolicy",
+		"metadata": map[string]interface{}{
+			"name":      "ghostctl-" + policyName,
+			"namespace": namespace,
+			"labels":    map[string]string{ClusterLabel: clusterName},
+		},
+		"spec": spec,
+	}
+}
+
+// ApplyNetworkPolicies creates or updates the host NetworkPolicies for a
+// vCluster
+func ApplyNetworkPolicies(name, namespace string, sec *cluster.Security) error {
+	policies, err := NetworkPolicies(name, namespace, sec)
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+	if !shell.CommandExists("kubectl") {
+		return fmt.Errorf("kubectl not found in PATH")
+	}
+
+	items := make([]interface{}, len(policies))
+	for i, p := range policies {
+		items[i] = p
+	}
+	manifest, err := json.Marshal(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items})
+	if err != nil {
+		return fmt.Errorf("failed to encode network policies: %w", err)
+	}
+
+	result, err := shell.ExecuteCommandWithInput(string(manifest), "kubectl", "apply", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("failed to apply network policies: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to apply network policies (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stdout))
+	}
+	return nil
+}
+
+// ListNetworkPolicies returns the names of the host NetworkPolicies
+// ghostctl created for a vCluster
+func ListNetworkPolicies(name, namespace string) ([]string, error) {
+	if !shell.CommandExists("kubectl") {
+		return nil, fmt.Errorf("kubectl not found in PATH")
+	}
+	result, err := shell.ExecuteCommand("kubectl", "get", "networkpolicy", "-n", namespace,
+		"-l", ClusterLabel+"="+name, "-o", "jsonpath={.items[*].metadata.name}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network policies: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return nil, fmt.Errorf("failed to list network policies (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stdout))
+	}
+	return strings.Fields(result.Stdout), nil
+}
+
+// DeleteNetworkPolicies removes the host NetworkPolicies ghostctl created
+// for a vCluster
+func DeleteNetworkPolicies(name, namespace string) error {
+	if !shell.CommandExists("kubectl") {
+		return fmt.Errorf("kubectl not found in PATH")
+	}
+	result, err := shell.ExecuteCommand("kubectl", "delete", "networkpolicy", "-n", namespace,
+		"-l", ClusterLabel+"="+name, "--ignore-not-found")
+	if err != nil {
+		return fmt.Errorf("failed to delete network policies: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to delete network policies (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stdout))
+	}
+	return nil
+}
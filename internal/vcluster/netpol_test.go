@@ -0,0 +1,51 @@
+package vcluster
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cluster"
+)
+
+func TestNetworkPolicies(t *testing.T) {
+	policyTypes := func(p map[string]interface{}) string {
+		spec := p["spec"].(map[string]interface{})
+		return strings.Join(spec["policyTypes"].([]string), ",")
+	}
+
+	if policies, err := NetworkPolicies("pr-1", "ghostcluster", &cluster.Security{PodSecurity: "restricted"}); err != nil || len(policies) != 0 {
+		t.Errorf("NetworkPolicies() without network settings = %v, %v; want none", policies, err)
+	}
+
+	// Isolation covers the workloads and the control plane, but leaves
+	// egress open
+	policies, err := NetworkPolicies("pr-1", "ghostcluster", &cluster.Security{NetworkIsolation: true})
+	if err != nil || len(policies) != 2 {
+		t.Fatalf("NetworkPolicies() = %v, %v; want workloads and control plane", policies, err)
+	}
+	if got := policyTypes(policies[0]); got != "Ingress" {
+		t.Errorf("workload policy types = %s, want Ingress", got)
+	}
+	meta := policies[1]["metadata"].(map[string]interface{})
+	if meta["name"] != "ghostctl-pr-1-control-plane" || meta["labels"].(map[string]string)[ClusterLabel] != "pr-1" {
+		t.Errorf("control plane policy metadata = %v", meta)
+	}
+
+	// Denying egress alone must not block ingress
+	policies, err = NetworkPolicies("pr-1", "ghostcluster", &cluster.Security{
+		Egress: &cluster.Egress{DefaultDeny: true, Allow: []string{"10.0.0.0/8", "0.0.0.0/0:443"}},
+	})
+	if err != nil || len(policies) != 1 {
+		t.Fatalf("NetworkPolicies() = %v, %v; want the workload policy", policies, err)
+	}
+	if got := policyTypes(policies[0]); got != "Egress" {
+		t.Errorf("workload policy types = %s, want Egress", got)
+	}
+	data, _ := json.Marshal(policies[0])
+	for _, want := range []string{`"cidr":"10.0.0.0/8"`, `{"ports":[{"port":443,"protocol":"TCP"}],"to":[{"ipBlock":{"cidr":"0.0.0.0/0"}}]}`, `"port":53`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("egress policy %s is missing %s", data, want)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package vcluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
+	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+	"github.com/ghostcluster-ai/ghostctl/pkg/utils"
+)
+
+const (
+	// ResourceLimitsName names the ResourceQuota and LimitRange ghostctl
+	// manages in a host namespace
+	ResourceLimitsName = "ghostctl-resources"
+
+	// Requests given to containers that set none, so that they count
+	// against the quota instead of being rejected by it
+	defaultCPURequestMillis   = 100
+	defaultMemoryRequestBytes = 128 * 1024 * 1024
+
+	// Resources of the pods every vCluster runs in its host namespace, the
+	// control plane and CoreDNS, with the vCluster chart's default
+	// resources. The quota adds the requests for each cluster on top of the
+	// cluster's own size, and the limit range admits the limits.
+	controlPlaneCPUMillis      = 200 + 20                 // control plane + CoreDNS
+	controlPlaneMemoryBytes    = (256 + 64) * 1024 * 1024 // control plane + CoreDNS
+	controlPlaneStorageBytes   = 5 * 1024 * 1024 * 1024   // the control plane's volume
+	controlPlaneMaxCPUMillis   = 1000                     // CoreDNS's CPU limit
+	controlPlaneMaxMemoryBytes = 2 * 1024 * 1024 * 1024   // the control plane's memory limit
+)
+
+// gpuResources maps GPU type prefixes to the extended resource their
+// device plugin advertises
+var gpuResources = []struct {
+	prefix   string
+	resource string
+}{
+	{"nvidia-", "nvidia.com/gpu"},
+	{"amd-", "amd.com/gpu"},
+	{"tpu-", "google.com/tpu"},
+}
+
+// GPUResourceName returns the extended resource name for a GPU type.
+// Unknown and empty types are assumed to be NVIDIA GPUs.
+func GPUResourceName(gpuType string) string {
+	for _, g := range gpuResources {
+		if strings.HasPrefix(gpuType, g.prefix) {
+			return g.resource
+		}
+	}
+	return gpuResources[0].resource
+}
+
+// GPUResourceNames returns the extended resources of every known GPU kind
+func GPUResourceNames() []string {
+	names := make([]string, len(gpuResources))
+	for i, g := range gpuResources {
+		names[i] = g.resource
+	}
+	return names
+}
+
+// ResourceLimits returns the host ResourceQuota and LimitRange bounding the
+// clusters in a namespace. The quota caps the namespace at the sum of the
+// clusters' CPU, memory, storage and GPUs, plus what each cluster's control
+// plane and CoreDNS need; GPU kinds no cluster asked for are capped at
+// zero. The limit range gives containers that set no requests or limits
+// defaults, and bounds single containers and volumes by the largest
+// cluster, or by the control plane's own limits and volume if those are
+// larger. Resources some cluster leaves unset are not bounded.
+func ResourceLimits(namespace string, clusters []cost.Resources) (quota, limitRange map[string]interface{}, err error) {
+	if len(clusters) == 0 {
+		return nil, nil, nil
+	}
+
+	var cpu, memory, storage, maxCPU, maxMemory, maxStorage int64
+	boundCPU, boundMemory, boundStorage := true, true, true
+	gpus := make(map[string]int64, len(gpuResources))
+	for _, g := range gpuResources {
+		gpus[g.resource] = 0
+	}
+
+	for _, c := range clusters {
+		if c.CPU == "" {
+			boundCPU = false
+		} else {
+			cores, err := utils.ParseCPU(c.CPU)
+			if err != nil {
+				return nil, nil, err
+			}
+			millis := int64(cores*1000 + 0.5)
+			cpu += millis + controlPlaneCPUMillis
+			if millis > maxCPU {
+				maxCPU = millis
+			}
+		}
+
+		if c.Memory == "" {
+			boundMemory = false
+		} else {
+			bytes, err := utils.ParseMemory(c.Memory)
+			if err != nil {
+				return nil, nil, err
+			}
+			memory += bytes + controlPlaneMemoryBytes
+			if bytes > maxMemory {
+				maxMemory = bytes
+			}
+		}
+
+		if c.Storage == "" {
+			boundStorage = false
+		} else {
+			bytes, err := utils.ParseMemory(c.Storage)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid storage value: %s", c.Storage)
+			}
+			storage += bytes + controlPlaneStorageBytes
+			if bytes > maxStorage {
+				maxStorage = bytes
+			}
+		}
+
+		if c.GPU > 0 {
+			gpus[GPUResourceName(c.GPUType)] += int64(c.GPU)
+		}
+	}
+
+	hard := make(map[string]string)
+	for resource, count := range gpus {
+		hard["requests."+resource] = fmt.Sprintf("%d", count)
+	}
+
+	containerMax := make(map[string]string)
+	containerDefault := make(map[string]string)
+	containerRequest := make(map[string]string)
+	if boundCPU {
+		hard["requests.cpu"] = formatCPU(cpu)
+		containerMax["cpu"] = formatCPU(maxInt64(maxCPU, controlPlaneMaxCPUMillis))
+		containerDefault["cpu"] = formatCPU(maxCPU)
+		containerRequest["cpu"] = formatCPU(minInt64(defaultCPURequestMillis, maxCPU))
+	}
+	if boundMemory {
+		hard["requests.memory"] = formatBytes(memory)
+		containerMax["memory"] = formatBytes(maxInt64(maxMemory, controlPlaneMaxMemoryBytes))
+		containerDefault["memory"] = formatBytes(maxMemory)
+		containerRequest["memory"] = formatBytes(minInt64(defaultMemoryRequestBytes, maxMemory))
+	}
+
+	var limits []interface{}
+	if len(containerMax) > 0 {
+		limits = append(limits, map[string]interface{}{
+			"type":           "Container",
+			"max":            containerMax,
+			"default":        containerDefault,
+			"defaultRequest": containerRequest,
+		})
+	}
+	if boundStorage {
+		hard["requests.storage"] = formatBytes(storage)
+		limits = append(limits, map[string]interface{}{
+			"type": "PersistentVolumeClaim",
+			"max":  map[string]string{"storage": formatBytes(maxInt64(maxStorage, controlPlaneStorageBytes))},
+		})
+	}
+
+	quota = resourceLimitsObject("ResourceQuota", namespace, map[string]interface{}{"hard": hard})
+	if len(limits) > 0 {
+		limitRange = resourceLimitsObject("LimitRange", namespace, map[string]interface{}{"limits": limits})
+	}
+	return quota, limitRange, nil
+}
+
+func resourceLimitsObject(kind, namespace string, spec map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      ResourceLimitsName,
+			"namespace": namespace,
+			"labels":    map[string]string{ManagedByLabel: managedByValue},
+		},
+		"spec": spec,
+	}
+}
+
+// ApplyResourceLimits creates or updates the ResourceQuota and LimitRange
+// bounding the clusters in a namespace
+func ApplyResourceLimits(namespace string, clusters []cost.Resources) error {
+	quota, limitRange, err := ResourceLimits(namespace, clusters)
+	if err != nil {
+		return err
+	}
+	if quota == nil {
+		return DeleteResourceLimits(namespace)
+	}
+	if !shell.CommandExists("kubectl") {
+		return fmt.Errorf("kubectl not found in PATH")
+	}
+
+	items := []interface{}{quota}
+	if limitRange != nil {
+		items = append(items, limitRange)
+	}
+	manifest, err := json.Marshal(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items})
+	if err != nil {
+		return fmt.Errorf("failed to encode resource limits: %w", err)
+	}
+
+	result, err := shell.ExecuteCommandWithInput(string(manifest), "kubectl", "apply", "-f", "-")
+	if err != nil {
+		return fmt.Errorf("failed to apply resource limits: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to apply resource limits (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stdout))
+	}
+
+	// A limit range left over from clusters that did set every resource
+	// would keep bounding containers
+	if limitRange == nil {
+		result, err := shell.ExecuteCommand("kubectl", "delete", "limitrange", ResourceLimitsName, "-n", namespace, "--ignore-not-found")
+		if err != nil {
+			return fmt.Errorf("failed to delete limit range: %w", err)
+		}
+		if result.ExitCode != 0 {
+			return fmt.Errorf("failed to delete limit range (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stdout))
+		}
+	}
+	return nil
+}
+
+// DeleteResourceLimits removes the ResourceQuota and LimitRange ghostctl
+// manages in a namespace
+func DeleteResourceLimits(namespace string) error {
+	if !shell.CommandExists("kubectl") {
+		return fmt.Errorf("kubectl not found in PATH")
+	}
+	result, err := shell.ExecuteCommand("kubectl", "delete", "resourcequota,limitrange", ResourceLimitsName,
+		"-n", namespace, "--ignore-not-found")
+	if err != nil {
+		return fmt.Errorf("failed to delete resource limits: %w", err)
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("failed to delete resource limits (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stdout))
+	}
+	return nil
+}
+
+// QuotaUsage is the usage of one resource against its quota
+type QuotaUsage struct {
+	Resource string
+	Used     string
+	Hard     string
+}
+
+// GetQuotaUsage returns the usage of the ResourceQuota ghostctl manages in
+// a namespace, sorted by resource. found is false if there is none.
+func GetQuotaUsage(namespace string) (usage []QuotaUsage, found bool, err error) {
+	if !shell.CommandExists("kubectl") {
+		return nil, false, fmt.Errorf("kubectl not found in PATH")
+	}
+	result, err := shell.ExecuteCommand("kubectl", "get", "resourcequota", ResourceLimitsName, "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read resource quota: %w", err)
+	}
+	if result.ExitCode != 0 {
+		if strings.Contains(result.Stdout, "NotFound") {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read resource quota (exit code %d): %s", result.ExitCode, strings.TrimSpace(result.Stdout))
+	}
+
+	var quota struct {
+		Status struct {
+			Hard map[string]string `json:"hard"`
+			Used map[string]string `json:"used"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(result.Stdout), &quota); err != nil {
+		return nil, false, fmt.Errorf("failed to parse resource quota: %w", err)
+	}
+
+	for resource, hard := range quota.Status.Hard {
+		used := quota.Status.Used[resource]
+		if used == "" {
+			used = "0"
+		}
+		usage = append(usage, QuotaUsage{Resource: resource, Used: used, Hard: hard})
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Resource < usage[j].Resource })
+	return usage, true, nil
+}
+
+// formatCPU renders millicores as a Kubernetes quantity
+func formatCPU(millis int64) string {
+	if millis%1000 == 0 {
+		return fmt.Sprintf("%d", millis/1000)
+	}
+	return fmt.Sprintf("%dm", millis)
+}
+
+// formatBytes renders bytes as a Kubernetes quantity, using the largest
+// binary suffix that represents it exactly
+func formatBytes(bytes int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{
+		{"Ti", 1 << 40},
+		{"Gi", 1 << 30},
+		{"Mi", 1 << 20},
+		{"Ki", 1 << 10},
+	} {
+		if bytes >= unit.size && bytes%unit.size == 0 {
+			return fmt.Sprintf("%d%s", bytes/unit.size, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%d", bytes)
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
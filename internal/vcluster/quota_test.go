@@ -0,0 +1,84 @@
+package vcluster
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ghostcluster-ai/ghostctl/internal/cost"
+)
+
+func TestResourceLimits(t *testing.T) {
+	hard := func(quota map[string]interface{}) map[string]string {
+		return quota["spec"].(map[string]interface{})["hard"].(map[string]string)
+	}
+
+	if quota, limitRange, err := ResourceLimits("ns", nil); err != nil || quota != nil || limitRange != nil {
+		t.Errorf("ResourceLimits() without clusters = %v, %v, %v; want nothing", quota, limitRange, err)
+	}
+
+	// Two clusters sharing an owner's namespace add up, with room for each
+	// one's control plane and CoreDNS; containers are bounded by the larger
+	// one
+	quota, limitRange, err := ResourceLimits("ghostcluster-alice", []cost.Resources{
+		{CPU: "1", Memory: "2Gi", Storage: "10Gi"},
+		{CPU: "500m", Memory: "512Mi", Storage: "20Gi", GPU: 2, GPUType: "nvidia-t4"},
+	})
+	if err != nil {
+		t.Fatalf("ResourceLimits() error = %v", err)
+	}
+	want := map[string]string{
+		"requests.cpu":            "1940m",
+		"requests.memory":         "3200Mi",
+		"requests.storage":        "40Gi",
+		"requests.nvidia.com/gpu": "2",
+		"requests.amd.com/gpu":    "0",
+		"requests.google.com/tpu": "0",
+	}
+	if got := hard(quota); !reflect.DeepEqual(got, want) {
+		t.Errorf("quota = %v, want %v", got, want)
+	}
+	limits := limitRange["spec"].(map[string]interface{})["limits"].([]interface{})
+	container := limits[0].(map[string]interface{})
+	if got := container["max"].(map[string]string); got["cpu"] != "1" || got["memory"] != "2Gi" {
+		t.Errorf("container max = %v, want cpu 1 and memory 2Gi", got)
+	}
+	if got := container["defaultRequest"].(map[string]string); got["cpu"] != "100m" || got["memory"] != "128Mi" {
+		t.Errorf("container default request = %v, want 100m and 128Mi", got)
+	}
+	if got := limits[1].(map[string]interface{})["max"].(map[string]string)["storage"]; got != "20Gi" {
+		t.Errorf("volume max = %s, want 20Gi", got)
+	}
+
+	// Containers and volumes of a small cluster's control plane still fit
+	_, limitRange, err = ResourceLimits("ns", []cost.Resources{{CPU: "500m", Memory: "1Gi", Storage: "1Gi"}})
+	if err != nil {
+		t.Fatalf("ResourceLimits() error = %v", err)
+	}
+	limits = limitRange["spec"].(map[string]interface{})["limits"].([]interface{})
+	container = limits[0].(map[string]interface{})
+	if got := container["max"].(map[string]string); got["cpu"] != "1" || got["memory"] != "2Gi" {
+		t.Errorf("container max = %v, want the control plane's cpu 1 and memory 2Gi", got)
+	}
+	if got := container["default"].(map[string]string); got["cpu"] != "500m" || got["memory"] != "1Gi" {
+		t.Errorf("container default = %v, want the cluster's 500m and 1Gi", got)
+	}
+	if got := limits[1].(map[string]interface{})["max"].(map[string]string)["storage"]; got != "5Gi" {
+		t.Errorf("volume max = %s, want the control plane's 5Gi", got)
+	}
+
+	// A resource one cluster leaves unset cannot be bounded
+	quota, limitRange, err = ResourceLimits("ns", []cost.Resources{{Memory: "1Gi"}, {CPU: "2", Memory: "1Gi"}})
+	if err != nil {
+		t.Fatalf("ResourceLimits() error = %v", err)
+	}
+	if _, ok := hard(quota)["requests.cpu"]; ok {
+		t.Errorf("quota = %v, want no CPU bound", hard(quota))
+	}
+	if len(limitRange["spec"].(map[string]interface{})["limits"].([]interface{})) != 1 {
+		t.Errorf("limit range = %v, want only the container limits", limitRange)
+	}
+
+	if _, _, err := ResourceLimits("ns", []cost.Resources{{Memory: "lots"}}); err == nil {
+		t.Error("ResourceLimits() with invalid memory succeeded, want error")
+	}
+}
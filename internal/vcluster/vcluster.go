@@ -2,10 +2,12 @@ package vcluster
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/ghostcluster-ai/ghostctl/internal/shell"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -18,8 +20,9 @@ type VCluster struct {
 	Namespace string
 }
 
-// Create creates a new vCluster using the vcluster CLI
-func Create(name, namespace string) error {
+// Create creates a new vCluster using the vcluster CLI, passing values as
+// the chart values
+func Create(name, namespace string, values map[string]interface{}) error {
 	if !shell.CommandExists("vcluster") {
 		return fmt.Errorf("vcluster CLI not found in PATH. Please install vCluster: https://www.vcluster.com/docs/getting-started/setup")
 	}
@@ -31,6 +34,15 @@ func Create(name, namespace string) error {
 		"--update-current=false",
 	}
 
+	if len(values) > 0 {
+		valuesFile, err := writeValuesFile(values)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(valuesFile)
+		args = append(args, "-f", valuesFile)
+	}
+
 	result, err := shell.ExecuteCommand("vcluster", args...)
 	if err != nil {
 		return fmt.Errorf("failed to create vCluster: %w", err)
@@ -222,3 +234,21 @@ func List(namespace string) ([]string, error) {
 
 	return clusters, nil
 }
+
+// writeValuesFile writes chart values to a temporary file for the vcluster CLI
+func writeValuesFile(values map[string]interface{}) (string, error) {
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode vCluster values: %w", err)
+	}
+	f, err := os.CreateTemp("", "ghostctl-values-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to write vCluster values: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write vCluster values: %w", err)
+	}
+	return f.Name(), nil
+}
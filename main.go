@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/ghostcluster-ai/ghostctl/cmd"
@@ -13,6 +14,10 @@ func main() {
 
 	// Execute root command
 	if err := cmd.RootCmd.Execute(); err != nil {
+		var exitErr *cmd.ExitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
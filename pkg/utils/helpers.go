@@ -3,28 +3,63 @@ package utils
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// ParseDuration parses a duration string like "1h", "30m", "10s"
+// durationPattern matches durations like "1d", "2h", "30m", "10s" and combinations such as "1h30m"
+var durationPattern = regexp.MustCompile(`^(?:(\d+)d)?(?:(\d+)h)?(?:(\d+)m)?(?:(\d+)s)?$`)
+
+// ParseDuration parses a duration string like "1d", "1h", "30m", "10s" or "1h30m"
 func ParseDuration(durationStr string) (time.Duration, error) {
-	// Handle common patterns
-	patterns := map[string]string{
-		`^(\d+)d$`: "${1}h", // days to hours
-		`^(\d+)h$`: "${1}h", // hours
-		`^(\d+)m$`: "${1}m", // minutes
-		`^(\d+)s$`: "${1}s", // seconds
-	}
-
-	for pattern := range patterns {
-		if matched, _ := regexp.MatchString(pattern, durationStr); matched {
-			// Use time.ParseDuration for standard formats
-			return time.ParseDuration(durationStr)
+	durationStr = strings.TrimSpace(durationStr)
+	matches := durationPattern.FindStringSubmatch(durationStr)
+	if durationStr == "" || matches == nil {
+		return 0, fmt.Errorf("invalid duration format: %s", durationStr)
+	}
+
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var total time.Duration
+	for i, unit := range units {
+		if matches[i+1] == "" {
+			continue
 		}
+		value, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration format: %s", durationStr)
+		}
+		total += time.Duration(value) * unit
 	}
 
-	return 0, fmt.Errorf("invalid duration format: %s", durationStr)
+	return total, nil
+}
+
+// FormatDuration formats a duration compactly, e.g. "2d3h", "1h5m", "45m" or "30s"
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0 && hours > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
 }
 
 // FormatBytes formats bytes as human-readable string
@@ -50,38 +85,60 @@ func FormatBytes(bytes int64) string {
 func ParseMemory(memStr string) (int64, error) {
 	memStr = strings.TrimSpace(memStr)
 
-	suffixes := map[string]int64{
-		"Ki": 1024,
-		"Mi": 1024 * 1024,
-		"Gi": 1024 * 1024 * 1024,
-		"Ti": 1024 * 1024 * 1024 * 1024,
-		"K":  1000,
-		"M":  1000 * 1000,
-		"G":  1000 * 1000 * 1000,
-		"T":  1000 * 1000 * 1000 * 1000,
+	// Binary suffixes are checked first so "Gi" is not read as "G"
+	suffixes := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"Ki", 1024},
+		{"Mi", 1024 * 1024},
+		{"Gi", 1024 * 1024 * 1024},
+		{"Ti", 1024 * 1024 * 1024 * 1024},
+		{"K", 1000},
+		{"M", 1000 * 1000},
+		{"G", 1000 * 1000 * 1000},
+		{"T", 1000 * 1000 * 1000 * 1000},
 	}
 
 	// Check for suffix
-	for suffix, multiplier := range suffixes {
-		if strings.HasSuffix(memStr, suffix) {
-			baseStr := strings.TrimSuffix(memStr, suffix)
-			var value float64
-			if _, err := fmt.Sscanf(baseStr, "%f", &value); err != nil {
+	for _, s := range suffixes {
+		if strings.HasSuffix(memStr, s.suffix) {
+			baseStr := strings.TrimSuffix(memStr, s.suffix)
+			value, err := strconv.ParseFloat(baseStr, 64)
+			if err != nil || value < 0 {
 				return 0, fmt.Errorf("invalid memory value: %s", memStr)
 			}
-			return int64(value * float64(multiplier)), nil
+			return int64(value * float64(s.multiplier)), nil
 		}
 	}
 
 	// Try parsing as plain integer (bytes)
-	var value int64
-	if _, err := fmt.Sscanf(memStr, "%d", &value); err != nil {
-		return 0, fmt.Errorf("invalid memory format: %s", memStr)
+	value, err := strconv.ParseInt(memStr, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid memory format: %s (expected e.g. 512Mi, 4Gi)", memStr)
 	}
 
 	return value, nil
 }
 
+// ParseCPU parses a CPU quantity like "2", "1.5" or "500m" into cores
+func ParseCPU(cpuStr string) (float64, error) {
+	cpuStr = strings.TrimSpace(cpuStr)
+	if strings.HasSuffix(cpuStr, "m") {
+		millis, err := strconv.ParseFloat(strings.TrimSuffix(cpuStr, "m"), 64)
+		if err != nil || millis < 0 {
+			return 0, fmt.Errorf("invalid CPU value: %s", cpuStr)
+		}
+		return millis / 1000, nil
+	}
+
+	cores, err := strconv.ParseFloat(cpuStr, 64)
+	if err != nil || cores < 0 {
+		return 0, fmt.Errorf("invalid CPU value: %s", cpuStr)
+	}
+	return cores, nil
+}
+
 // ValidateClusterName validates a cluster name
 func ValidateClusterName(name string) error {
 	if len(name) == 0 {
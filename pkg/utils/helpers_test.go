@@ -2,6 +2,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 )
 
 // TestParseDuration tests the ParseDuration function
@@ -15,6 +16,9 @@ func TestParseDuration(t *testing.T) {
 		{"valid 1h", "1h", false, true},
 		{"valid 30m", "30m", false, true},
 		{"valid 10s", "10s", false, true},
+		{"valid 1d", "1d", false, true},
+		{"valid 1h30m", "1h30m", false, true},
+		{"empty", "", true, false},
 		{"invalid format", "invalid", true, false},
 	}
 
@@ -31,6 +35,38 @@ func TestParseDuration(t *testing.T) {
 	}
 }
 
+// TestParseDurationDays tests that day durations are converted to hours
+func TestParseDurationDays(t *testing.T) {
+	got, err := ParseDuration("2d")
+	if err != nil {
+		t.Fatalf("ParseDuration() err = %v", err)
+	}
+	if got != 48*time.Hour {
+		t.Errorf("ParseDuration(2d) = %v, want 48h", got)
+	}
+}
+
+// TestFormatDuration tests the FormatDuration function
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{30 * time.Second, "30s"},
+		{45 * time.Minute, "45m"},
+		{time.Hour, "1h"},
+		{65 * time.Minute, "1h5m"},
+		{51 * time.Hour, "2d3h"},
+		{48 * time.Hour, "2d"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.in); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
 // TestFormatBytes tests the FormatBytes function
 func TestFormatBytes(t *testing.T) {
 	tests := []struct {
@@ -55,6 +91,60 @@ func TestFormatBytes(t *testing.T) {
 	}
 }
 
+// TestParseCPU tests the ParseCPU function
+func TestParseCPU(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{"2", 2, false},
+		{"1.5", 1.5, false},
+		{"500m", 0.5, false},
+		{"two", 0, true},
+		{"-1", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCPU(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCPU(%s) err = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseCPU(%s) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestParseMemory tests the ParseMemory function
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"4Gi", 4 << 30, false},
+		{"512Mi", 512 << 20, false},
+		{"1.5Gi", 3 << 29, false},
+		{"2G", 2000000000, false},
+		{"1024", 1024, false},
+		{"4GB", 0, true},
+		{"4xGi", 0, true},
+		{"-1Gi", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMemory(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMemory(%s) err = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseMemory(%s) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
 // TestValidateClusterName tests cluster name validation
 func TestValidateClusterName(t *testing.T) {
 	tests := []struct {